@@ -21,13 +21,54 @@ type Config struct {
 	RestBindAddress    string `json:"RestBindAddress"`
 	RpcBindAddress     string `json:"RpcBindAddress"`
 	BindAddress        string `json:"BindAddress"`
+	BindIPv6Prefix     string `json:"BindIPv6Prefix"`
 	BindPort           int    `json:"BindPort"`
 	BasePortOffset     int    `json:"BasePortOffset"`
 	SizeOfPortsPool    int    `json:"SizeOfPortsPool"`
+	EsphomeProxy       bool   `json:"EsphomeProxy"`
+	EsphomeCacheGrace  int    `json:"EsphomeCacheGrace"`
+	MdnsAdvertise      bool   `json:"MdnsAdvertise"`
+	ApiAllowedClients  string `json:"ApiAllowedClients"`
+	OtaAllowedClients  string `json:"OtaAllowedClients"`
+	MaxClientsPerNode  int    `json:"MaxClientsPerNode"`
+	MaxClients         int    `json:"MaxClients"`
+	DiscoverySlots       int    `json:"DiscoverySlots"`
+	DiscoveryMask        int    `json:"DiscoveryMask"`
+	DiscoveryFilter      int    `json:"DiscoveryFilter"`
+	DiscoveryWait        int    `json:"DiscoveryWait"`
+	DiscoveryRepetitions int    `json:"DiscoveryRepetitions"`
+	DiscoveryAggregation string `json:"DiscoveryAggregation"`
+	DiscoveryRssiMin     int    `json:"DiscoveryRssiMin"`
+	DiscoveryRssiMax     int    `json:"DiscoveryRssiMax"`
+	AdoptionApproval     bool   `json:"AdoptionApproval"`
+	RouteMaxHops         int    `json:"RouteMaxHops"`
+	RouteMetric          string `json:"RouteMetric"`
+	HistoryMaxVersions   int    `json:"HistoryMaxVersions"`
+	HistoryMaxDays       int    `json:"HistoryMaxDays"`
+	// Set by the export and import commands, they work on the graph file and the program exits
+	ExportFormat         string `json:"-"`
+	ExportOutput         string `json:"-"`
+	ImportFormat         string `json:"-"`
+	ImportMode           string `json:"-"`
+	ImportWeights        string `json:"-"`
+	ImportInput          cli.StringSlice `json:"-"`
+	ImportDryRun         bool   `json:"-"`
+	CommandLocalNode     string `json:"-"`
+	// Per node allowed clients, the key is the node id (e.g. N123456)
+	NodesAllowedClients map[string]NodeAllowedClients `json:"NodesAllowedClients"`
 }
 
+type NodeAllowedClients struct {
+	Api string `json:"Api"`
+	Ota string `json:"Ota"`
+}
+
+// CommandAction runs a command of the program, the config file is already read when it's called
+type CommandAction func(config *Config) error
 
-func NewConfig() (*Config, error) {
+// NewConfig parse the command line and the config file. The export and import commands run their
+// action and leave WantHelp set, the program exits after them.
+func NewConfig(exportAction CommandAction, importAction CommandAction) (*Config, error) {
 	var err error
 	config := Config{
 		WantHelp:        true,
@@ -40,6 +81,15 @@ func NewConfig() (*Config, error) {
 		ConfigFile: "meshmeshgo.json",
 		SerialPortName: "/dev/ttyUSB0",
 		SerialPortBaudRate: 460800,
+		DiscoverySlots: 100,
+		DiscoveryWait: 5,
+		DiscoveryRepetitions: 3,
+		DiscoveryAggregation: "last",
+		DiscoveryRssiMin: -80,
+		DiscoveryRssiMax: -40,
+		RouteMetric: "weight",
+		HistoryMaxVersions: 200,
+		HistoryMaxDays: 30,
 	}
 
 	app := &cli.App{
@@ -98,9 +148,15 @@ func NewConfig() (*Config, error) {
 			&cli.StringFlag{
 				Name:        "bind_address",
 				Value:       config.BindAddress,
-				Usage:       "Bind address for the esphome servers. Use 'dynamic' to auto-assign a port based on the node id",
+				Usage:       "Comma separated bind addresses for the esphome servers. Use 'dynamic' for a 127.x.y.z address and 'dynamic6' for an address of bind_ipv6_prefix based on the node id",
 				Destination: &config.BindAddress,
 			},
+			&cli.StringFlag{
+				Name:        "bind_ipv6_prefix",
+				Value:       config.BindIPv6Prefix,
+				Usage:       "IPv6 prefix used by the 'dynamic6' bind address (e.g. fd00:6d6d::/64)",
+				Destination: &config.BindIPv6Prefix,
+			},
 			&cli.IntFlag{
 				Name:        "bind_port",
 				Value:       config.BindPort,
@@ -119,6 +175,102 @@ func NewConfig() (*Config, error) {
 				Usage:       "Size of ports pool for the server",
 				Destination: &config.SizeOfPortsPool,
 			},
+			&cli.BoolFlag{
+				Name:        "esphome_proxy",
+				Value:       config.EsphomeProxy,
+				Usage:       "Share a single esphome api connection per node between all the clients",
+				Destination: &config.EsphomeProxy,
+			},
+			&cli.IntFlag{
+				Name:        "esphome_cache_grace",
+				Value:       config.EsphomeCacheGrace,
+				Usage:       "Seconds the cached entities states are served while a node is unreachable. Use 0 to disable",
+				Destination: &config.EsphomeCacheGrace,
+			},
+			&cli.BoolFlag{
+				Name:        "mdns",
+				Value:       config.MdnsAdvertise,
+				Usage:       "Advertise the in use nodes as esphome devices with mDNS",
+				Destination: &config.MdnsAdvertise,
+			},
+			&cli.StringFlag{
+				Name:        "api_allow",
+				Value:       config.ApiAllowedClients,
+				Usage:       "Comma separated networks (CIDR) allowed to connect to the esphome api ports. Empty allow everyone",
+				Destination: &config.ApiAllowedClients,
+			},
+			&cli.StringFlag{
+				Name:        "ota_allow",
+				Value:       config.OtaAllowedClients,
+				Usage:       "Comma separated networks (CIDR) allowed to connect to the esphome ota ports. Empty allow everyone",
+				Destination: &config.OtaAllowedClients,
+			},
+			&cli.IntFlag{
+				Name:        "max_clients_per_node",
+				Value:       config.MaxClientsPerNode,
+				Usage:       "Maximum number of concurrent clients per node. Use 0 for no limit",
+				Destination: &config.MaxClientsPerNode,
+			},
+			&cli.IntFlag{
+				Name:        "max_clients",
+				Value:       config.MaxClients,
+				Usage:       "Maximum number of concurrent clients of the hub. Use 0 for no limit",
+				Destination: &config.MaxClients,
+			},
+			&cli.IntFlag{
+				Name:        "discovery_slots",
+				Value:       config.DiscoverySlots,
+				Usage:       "Default number of slots of the discovery on each node",
+				Destination: &config.DiscoverySlots,
+			},
+			&cli.IntFlag{
+				Name:        "discovery_wait",
+				Value:       config.DiscoveryWait,
+				Usage:       "Default seconds to wait the neighbors replies on each discovery step",
+				Destination: &config.DiscoveryWait,
+			},
+			&cli.IntFlag{
+				Name:        "discovery_repetitions",
+				Value:       config.DiscoveryRepetitions,
+				Usage:       "Default number of discovery repetitions on each node",
+				Destination: &config.DiscoveryRepetitions,
+			},
+			&cli.StringFlag{
+				Name:        "discovery_aggregation",
+				Value:       config.DiscoveryAggregation,
+				Usage:       "Default method to combine the weights of the repetitions: last, min, mean or median",
+				Destination: &config.DiscoveryAggregation,
+			},
+			&cli.BoolFlag{
+				Name:        "adoption_approval",
+				Value:       config.AdoptionApproval,
+				Usage:       "New nodes associated to the mesh wait the operator approval before being adopted",
+				Destination: &config.AdoptionApproval,
+			},
+			&cli.IntFlag{
+				Name:        "route_max_hops",
+				Value:       config.RouteMaxHops,
+				Usage:       "Maximum number of hops of the routes to the nodes. Use 0 for the firmware limit",
+				Destination: &config.RouteMaxHops,
+			},
+			&cli.StringFlag{
+				Name:        "route_metric",
+				Value:       config.RouteMetric,
+				Usage:       "Metric used to choose the routes: weight (sum of the links) or bottleneck (worst link)",
+				Destination: &config.RouteMetric,
+			},
+			&cli.IntFlag{
+				Name:        "history_max_versions",
+				Value:       config.HistoryMaxVersions,
+				Usage:       "Maximum number of graph versions kept in the history. Use 0 for no limit",
+				Destination: &config.HistoryMaxVersions,
+			},
+			&cli.IntFlag{
+				Name:        "history_max_days",
+				Value:       config.HistoryMaxDays,
+				Usage:       "Days a graph version is kept in the history. Use 0 for no limit",
+				Destination: &config.HistoryMaxDays,
+			},
 			&cli.StringFlag{
 				Name:    "config",
 				Aliases: []string{"c"},
@@ -130,24 +282,141 @@ func NewConfig() (*Config, error) {
 			config.WantHelp = false
 			return nil
 		},
+		Commands: []*cli.Command{
+			{
+				Name:  "export",
+				Usage: "Export the network graph as dot, gexf, json, csv_nodes, csv_edges or csv",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:        "format",
+						Value:       "dot",
+						Aliases:     []string{"f"},
+						Usage:       "Export format: dot, gexf, json, csv_nodes, csv_edges or csv (zip of both tables)",
+						Destination: &config.ExportFormat,
+					},
+					&cli.StringFlag{
+						Name:        "output",
+						Aliases:     []string{"o"},
+						Usage:       "Output file, the standard output if not set",
+						Destination: &config.ExportOutput,
+					},
+					&cli.StringFlag{
+						Name:        "local",
+						Aliases:     []string{"l"},
+						Usage:       "Id of the local node (e.g. N123456), the root of the routing tree",
+						Required:    true,
+						Destination: &config.CommandLocalNode,
+					},
+				},
+				Action: func(cCtx *cli.Context) error {
+					return exportAction(&config)
+				},
+			},
+			{
+				Name:  "import",
+				Usage: "Import a graphml, json or csv graph replacing or merging the network graph",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:        "format",
+						Value:       "graphml",
+						Aliases:     []string{"f"},
+						Usage:       "Import format: graphml, json or csv",
+						Destination: &config.ImportFormat,
+					},
+					&cli.StringFlag{
+						Name:        "mode",
+						Value:       "merge",
+						Aliases:     []string{"m"},
+						Usage:       "Import mode: replace or merge",
+						Destination: &config.ImportMode,
+					},
+					&cli.StringFlag{
+						Name:        "weights",
+						Value:       "imported",
+						Aliases:     []string{"w"},
+						Usage:       "Weight kept by a merge for the edges already in the graph: imported, existing or newer",
+						Destination: &config.ImportWeights,
+					},
+					&cli.StringSliceFlag{
+						Name:        "input",
+						Aliases:     []string{"i"},
+						Usage:       "File to import, repeat it to import the csv tables of the nodes and of the edges",
+						Required:    true,
+						Destination: &config.ImportInput,
+					},
+					&cli.StringFlag{
+						Name:        "local",
+						Aliases:     []string{"l"},
+						Usage:       "Id of the local node (e.g. N123456)",
+						Required:    true,
+						Destination: &config.CommandLocalNode,
+					},
+					&cli.BoolFlag{
+						Name:        "dry-run",
+						Aliases:     []string{"n"},
+						Usage:       "Print the changes without saving the graph",
+						Destination: &config.ImportDryRun,
+					},
+				},
+				Action: func(cCtx *cli.Context) error {
+					return importAction(&config)
+				},
+			},
+		},
+		// The config file is read before the actions of the commands
+		Before: func(cCtx *cli.Context) error {
+			return config.readFile()
+		},
 	}
 
 	if err = app.Run(os.Args); err != nil {
 		logger.Log().Fatal(err)
 	}
 
-	if _, err = os.Stat(config.ConfigFile); err == nil {
-		data, err := os.ReadFile(config.ConfigFile)
-		fmt.Println("Data: "+string(data))
+	return &config, err
+}
+
+// readFile override the command line options with the values of the config file
+func (c *Config) readFile() error {
+	_, err := os.Stat(c.ConfigFile)
+	if err == nil {
+		data, err := os.ReadFile(c.ConfigFile)
+		logger.WithField("data", string(data)).Debug("Config file")
 		if err == nil {
-		  json.Unmarshal(data, &config)
+		  json.Unmarshal(data, c)
 		}
     }
 	if err != nil {
-		res2B, _ := json.MarshalIndent(&config, "", "  ")
+		res2B, _ := json.MarshalIndent(c, "", "  ")
 		fmt.Println(string(res2B))
-		logger.Log().Fatal(err)
 	}
+	return err
+}
 
-	return &config, err
+// SaveKeys writes the given settings to the config file, the other keys of the file are left untouched
+func (c *Config) SaveKeys(keys ...string) error {
+	file := make(map[string]json.RawMessage)
+	if data, err := os.ReadFile(c.ConfigFile); err == nil {
+		if err = json.Unmarshal(data, &file); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	values := make(map[string]json.RawMessage)
+	if err = json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		file[key] = values[key]
+	}
+
+	data, err = json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.ConfigFile, data, 0644)
 }
@@ -0,0 +1,247 @@
+package graph
+
+import (
+	"container/heap"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// NodeAnalysis describe the role of a node in the topology seen from the local device
+type NodeAnalysis struct {
+	Node int64
+	// Hops of the route from the local device, -1 when unreachable
+	Hops int
+	// Betweenness is the weighted betweenness centrality of the node among all the nodes
+	Betweenness float64
+	// Relayed is the number of routes from the local device that pass through the node
+	Relayed int
+	// Articulation is true when the failure of the node disconnects other nodes
+	Articulation bool
+	// Dependents are the nodes that lose connectivity if the node fails
+	Dependents []int64
+	// SinglePath is true when the failure of a single relay or link disconnects the node
+	SinglePath bool
+}
+
+// LinkAnalysis is a link whose failure disconnects some nodes
+type LinkAnalysis struct {
+	From       int64
+	To         int64
+	Dependents []int64
+}
+
+type Analysis struct {
+	Nodes []NodeAnalysis
+	// Bridges are the links whose failure disconnects some nodes
+	Bridges []LinkAnalysis
+	// Unreachable are the nodes without any route from the local device
+	Unreachable []int64
+}
+
+// RouteChange is a route that changes when a node fails
+type RouteChange struct {
+	Node    int64
+	OldPath []int64
+	NewPath []int64
+}
+
+// FailureImpact is the effect of the failure of a node on the other nodes
+type FailureImpact struct {
+	Node     int64
+	Lost     []int64
+	Rerouted []RouteChange
+}
+
+// reachable returns the nodes reachable from the local device without using the banned ones
+func (r *routeGraph) reachable(bans routeBans) map[int64]int64 {
+	// parents keep the node used to reach every node, the local device has itself as parent
+	parents := map[int64]int64{r.local: r.local}
+	queue := []int64{r.local}
+	for len(queue) > 0 {
+		from := queue[0]
+		queue = queue[1:]
+		for _, link := range r.links[from] {
+			if _, ok := parents[link.to]; ok || bans.nodes[link.to] || bans.links[[2]int64{from, link.to}] {
+				continue
+			}
+			parents[link.to] = from
+			queue = append(queue, link.to)
+		}
+	}
+	return parents
+}
+
+// lost returns the nodes reachable in all but not in reached, excluding the failed node
+func lost(all map[int64]int64, reached map[int64]int64, failed int64) []int64 {
+	nodes := make([]int64, 0)
+	for id := range all {
+		if _, ok := reached[id]; !ok && id != failed {
+			nodes = append(nodes, id)
+		}
+	}
+	slices.Sort(nodes)
+	return nodes
+}
+
+type distItem struct {
+	node int64
+	dist float64
+}
+
+type distQueue []distItem
+
+func (q distQueue) Len() int            { return len(q) }
+func (q distQueue) Less(i, j int) bool  { return q[i].dist < q[j].dist }
+func (q distQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *distQueue) Push(x interface{}) { *q = append(*q, x.(distItem)) }
+func (q *distQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// betweenness returns the weighted betweenness centrality of the nodes using the Brandes algorithm
+func (r *routeGraph) betweenness(nodes []int64) map[int64]float64 {
+	centrality := make(map[int64]float64, len(nodes))
+	for _, source := range nodes {
+		// Shortest paths from source, visited keep the nodes by increasing distance
+		dist := map[int64]float64{source: 0}
+		paths := map[int64]float64{source: 1}
+		preds := make(map[int64][]int64)
+		visited := make([]int64, 0, len(nodes))
+		done := make(map[int64]bool)
+		queue := &distQueue{{source, 0}}
+		for queue.Len() > 0 {
+			item := heap.Pop(queue).(distItem)
+			if done[item.node] || item.dist > dist[item.node] {
+				continue
+			}
+			done[item.node] = true
+			visited = append(visited, item.node)
+			for _, link := range r.links[item.node] {
+				d := item.dist + link.cost
+				if current, ok := dist[link.to]; !ok || d < current {
+					dist[link.to] = d
+					paths[link.to] = 0
+					preds[link.to] = preds[link.to][:0]
+					heap.Push(queue, distItem{link.to, d})
+				}
+				if d == dist[link.to] && !done[link.to] {
+					paths[link.to] += paths[item.node]
+					preds[link.to] = append(preds[link.to], item.node)
+				}
+			}
+		}
+
+		dependency := make(map[int64]float64)
+		for i := len(visited) - 1; i >= 0; i-- {
+			node := visited[i]
+			for _, pred := range preds[node] {
+				dependency[pred] += paths[pred] / paths[node] * (1 + dependency[node])
+			}
+			if node != source {
+				centrality[node] += dependency[node]
+			}
+		}
+	}
+	return centrality
+}
+
+// Analyze search the single points of failure of the network and the load of the nodes
+func (g *Network) Analyze() Analysis {
+	r := g.routeGraph()
+	tree := g.shortestTree()
+	all := r.reachable(routeBans{})
+	analysis := Analysis{Nodes: make([]NodeAnalysis, 0), Bridges: make([]LinkAnalysis, 0), Unreachable: make([]int64, 0)}
+
+	ids := sortedNodeIds(g)
+	singlePath := make(map[int64]bool)
+	nodes := make(map[int64]*NodeAnalysis, len(ids))
+	for _, id := range ids {
+		nodes[id] = &NodeAnalysis{Node: id, Hops: -1, Dependents: make([]int64, 0)}
+	}
+	for _, id := range ids {
+		node := nodes[id]
+		if _, ok := all[id]; !ok {
+			analysis.Unreachable = append(analysis.Unreachable, id)
+			continue
+		}
+		if route, ok := tree.route(g, id); ok {
+			node.Hops = route.Hops()
+			for i := 1; i < len(route.Path)-1; i++ {
+				nodes[route.Path[i]].Relayed++
+			}
+		}
+		if id == r.local {
+			continue
+		}
+		node.Dependents = lost(all, r.reachable(routeBans{nodes: map[int64]bool{id: true}}), id)
+		node.Articulation = len(node.Dependents) > 0
+		for _, dependent := range node.Dependents {
+			singlePath[dependent] = true
+		}
+	}
+
+	// Only the links of the search tree can disconnect a node
+	for to, from := range all {
+		if to == from {
+			continue
+		}
+		dependents := lost(all, r.reachable(routeBans{links: map[[2]int64]bool{{from, to}: true}}), -1)
+		if len(dependents) > 0 {
+			analysis.Bridges = append(analysis.Bridges, LinkAnalysis{From: from, To: to, Dependents: dependents})
+			for _, dependent := range dependents {
+				singlePath[dependent] = true
+			}
+		}
+	}
+	sort.Slice(analysis.Bridges, func(i, j int) bool {
+		if analysis.Bridges[i].From != analysis.Bridges[j].From {
+			return analysis.Bridges[i].From < analysis.Bridges[j].From
+		}
+		return analysis.Bridges[i].To < analysis.Bridges[j].To
+	})
+
+	betweenness := r.betweenness(ids)
+	for _, id := range ids {
+		node := nodes[id]
+		node.Betweenness = betweenness[id]
+		node.SinglePath = singlePath[id]
+		analysis.Nodes = append(analysis.Nodes, *node)
+	}
+	return analysis
+}
+
+// FailureImpact returns the nodes that lose connectivity or change route if the node fails
+func (g *Network) FailureImpact(id int64) (FailureImpact, error) {
+	if !g.NodeIdExists(id) {
+		return FailureImpact{}, fmt.Errorf("node 0x%06X not found in network graph", id)
+	}
+	if id == g.localDeviceId {
+		return FailureImpact{}, fmt.Errorf("node 0x%06X is the local device", id)
+	}
+
+	failed := g.CopyNetwork()
+	failed.RemoveNode(id)
+	impact := FailureImpact{Node: id, Lost: make([]int64, 0), Rerouted: make([]RouteChange, 0)}
+	before := g.shortestTree()
+	after := failed.shortestTree()
+	for _, nodeId := range sortedNodeIds(g) {
+		if nodeId == id {
+			continue
+		}
+		oldRoute, ok := before.route(g, nodeId)
+		if !ok {
+			continue
+		}
+		newRoute, ok := after.route(failed, nodeId)
+		if !ok {
+			impact.Lost = append(impact.Lost, nodeId)
+		} else if !slices.Equal(oldRoute.Path, newRoute.Path) {
+			impact.Rerouted = append(impact.Rerouted, RouteChange{Node: nodeId, OldPath: oldRoute.Path, NewPath: newRoute.Path})
+		}
+	}
+	return impact, nil
+}
@@ -0,0 +1,141 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+// newRingNetwork returns a network where the nodes from 1 to size form a ring
+func newRingNetwork(size int) *Network {
+	network := newChainNetwork(size)
+	network.ChangeEdgeWeight(int64(size), 1, 0.1, 0.1)
+	network.ChangeEdgeWeight(1, int64(size), 0.1, 0.1)
+	return network
+}
+
+// newStarNetwork returns a network where node 2 is the only neighbor of the local device and of the leaves
+func newStarNetwork(leaves int) *Network {
+	network := newChainNetwork(2)
+	for id := int64(3); id < int64(3+leaves); id++ {
+		network.AddNode(NewNodeDevice(id, true, ""))
+		network.ChangeEdgeWeight(2, id, 0.1, 0.1)
+		network.ChangeEdgeWeight(id, 2, 0.1, 0.1)
+	}
+	return network
+}
+
+func TestAnalyze(t *testing.T) {
+	type node struct {
+		hops         int
+		betweenness  float64
+		relayed      int
+		articulation bool
+		dependents   []int64
+		singlePath   bool
+	}
+	tests := []struct {
+		name        string
+		network     *Network
+		nodes       map[int64]node
+		bridges     []LinkAnalysis
+		unreachable []int64
+	}{
+		{
+			name:    "chain",
+			network: newChainNetwork(4),
+			nodes: map[int64]node{
+				1: {0, 0, 0, false, []int64{}, false},
+				2: {1, 4, 2, true, []int64{3, 4}, true},
+				3: {2, 4, 1, true, []int64{4}, true},
+				4: {3, 0, 0, false, []int64{}, true},
+			},
+			bridges:     []LinkAnalysis{{1, 2, []int64{2, 3, 4}}, {2, 3, []int64{3, 4}}, {3, 4, []int64{4}}},
+			unreachable: []int64{},
+		},
+		{
+			name:    "ring",
+			network: newRingNetwork(5),
+			nodes: map[int64]node{
+				1: {0, 2, 0, false, []int64{}, false},
+				2: {1, 2, 1, false, []int64{}, false},
+				3: {2, 2, 0, false, []int64{}, false},
+				4: {2, 2, 0, false, []int64{}, false},
+				5: {1, 2, 1, false, []int64{}, false},
+			},
+			bridges:     []LinkAnalysis{},
+			unreachable: []int64{},
+		},
+		{
+			name: "star",
+			network: func() *Network {
+				network := newStarNetwork(3)
+				network.AddNode(NewNodeDevice(9, true, ""))
+				return network
+			}(),
+			nodes: map[int64]node{
+				1: {0, 0, 0, false, []int64{}, false},
+				2: {1, 12, 3, true, []int64{3, 4, 5}, true},
+				3: {2, 0, 0, false, []int64{}, true},
+				4: {2, 0, 0, false, []int64{}, true},
+				5: {2, 0, 0, false, []int64{}, true},
+				9: {-1, 0, 0, false, []int64{}, false},
+			},
+			bridges:     []LinkAnalysis{{1, 2, []int64{2, 3, 4, 5}}, {2, 3, []int64{3}}, {2, 4, []int64{4}}, {2, 5, []int64{5}}},
+			unreachable: []int64{9},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := tt.network.Analyze()
+			if len(analysis.Nodes) != len(tt.nodes) {
+				t.Fatalf("%d nodes analyzed, want %d", len(analysis.Nodes), len(tt.nodes))
+			}
+			for _, got := range analysis.Nodes {
+				want := tt.nodes[got.Node]
+				if got.Hops != want.hops || got.Betweenness != want.betweenness || got.Relayed != want.relayed ||
+					got.Articulation != want.articulation || !reflect.DeepEqual(got.Dependents, want.dependents) || got.SinglePath != want.singlePath {
+					t.Errorf("node %d: %+v, want %+v", got.Node, got, want)
+				}
+			}
+			if !reflect.DeepEqual(analysis.Bridges, tt.bridges) {
+				t.Errorf("bridges %v, want %v", analysis.Bridges, tt.bridges)
+			}
+			if !reflect.DeepEqual(analysis.Unreachable, tt.unreachable) {
+				t.Errorf("unreachable %v, want %v", analysis.Unreachable, tt.unreachable)
+			}
+		})
+	}
+}
+
+func TestFailureImpact(t *testing.T) {
+	tests := []struct {
+		name     string
+		network  *Network
+		node     int64
+		lost     []int64
+		rerouted []RouteChange
+	}{
+		{"chain", newChainNetwork(4), 3, []int64{4}, []RouteChange{}},
+		{"chain end", newChainNetwork(4), 4, []int64{}, []RouteChange{}},
+		{"ring", newRingNetwork(5), 2, []int64{}, []RouteChange{{3, []int64{1, 2, 3}, []int64{1, 5, 4, 3}}}},
+		{"star", newStarNetwork(3), 2, []int64{3, 4, 5}, []RouteChange{}},
+		{"star leaf", newStarNetwork(3), 4, []int64{}, []RouteChange{}},
+	}
+	for _, tt := range tests {
+		impact, err := tt.network.FailureImpact(tt.node)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(impact.Lost, tt.lost) || !reflect.DeepEqual(impact.Rerouted, tt.rerouted) {
+			t.Errorf("%s: failure of %d lost %v rerouted %v, want %v %v", tt.name, tt.node, impact.Lost, impact.Rerouted, tt.lost, tt.rerouted)
+		}
+	}
+
+	network := newChainNetwork(3)
+	if _, err := network.FailureImpact(1); err == nil {
+		t.Error("failure impact of the local device")
+	}
+	if _, err := network.FailureImpact(7); err == nil {
+		t.Error("failure impact of an unknown node")
+	}
+}
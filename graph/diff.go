@@ -0,0 +1,220 @@
+package graph
+
+import (
+	"math"
+	"slices"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+type DiffKind string
+
+const (
+	DiffNodeAdded    DiffKind = "node_added"
+	DiffNodeRemoved  DiffKind = "node_removed"
+	DiffEdgeAdded    DiffKind = "edge_added"
+	DiffEdgeRemoved  DiffKind = "edge_removed"
+	DiffEdgeChanged  DiffKind = "edge_changed"
+	DiffRouteChanged DiffKind = "route_changed"
+	DiffNodeChanged  DiffKind = "node_changed"
+)
+
+// DiffItem is a single difference between two networks. Route changes are informative, they
+// are the consequence of the edges changes.
+type DiffItem struct {
+	Id        int
+	Kind      DiffKind
+	Node      int64
+	From      int64
+	To        int64
+	OldWeight float64
+	NewWeight float64
+	OldPath   []int64
+	NewPath   []int64
+	OldTag    string
+	NewTag    string
+	OldInUse  bool
+	NewInUse  bool
+}
+
+func sortedNodeIds(g *Network) []int64 {
+	ids := make([]int64, 0, g.Nodes().Len())
+	nodes := g.Nodes()
+	for nodes.Next() {
+		ids = append(ids, nodes.Node().ID())
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func sortedEdges(g *Network) []graph.WeightedEdge {
+	edges := make([]graph.WeightedEdge, 0)
+	iter := g.WeightedEdges()
+	for iter.Next() {
+		edges = append(edges, iter.WeightedEdge())
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From().ID() != edges[j].From().ID() {
+			return edges[i].From().ID() < edges[j].From().ID()
+		}
+		return edges[i].To().ID() < edges[j].To().ID()
+	})
+	return edges
+}
+
+// routes returns the shortest path from the local device to every in use node
+func (g *Network) routes() map[int64][]int64 {
+	routes := make(map[int64][]int64)
+	tree := g.shortestTree()
+	nodes := g.Nodes()
+	for nodes.Next() {
+		dev := nodes.Node().(NodeDevice)
+		if !dev.Device().InUse() {
+			continue
+		}
+		route, ok := tree.route(g, dev.ID())
+		if !ok {
+			route.Path = []int64{}
+		}
+		routes[dev.ID()] = route.Path
+	}
+	return routes
+}
+
+// DiffNetworks list the changes needed to turn current into proposed, weight changes smaller
+// than threshold are ignored.
+func DiffNetworks(current *Network, proposed *Network, threshold float64) []DiffItem {
+	return diffNetworks(current, proposed, threshold, false)
+}
+
+// DiffNetworkVersions list all the differences between two versions of the network, the changes
+// of tag and in use flag of the nodes included. The weights are compared at the precision of the
+// graph files, a version loaded from file doesn't differ from the network it was saved from.
+func DiffNetworkVersions(older *Network, newer *Network) []DiffItem {
+	return diffNetworks(older, newer, 0, true)
+}
+
+func diffNetworks(current *Network, proposed *Network, threshold float64, devices bool) []DiffItem {
+	items := make([]DiffItem, 0)
+	add := func(item DiffItem) {
+		item.Id = len(items) + 1
+		items = append(items, item)
+	}
+
+	currentIds := sortedNodeIds(current)
+	proposedIds := sortedNodeIds(proposed)
+	for _, id := range proposedIds {
+		if !current.NodeIdExists(id) {
+			add(DiffItem{Kind: DiffNodeAdded, Node: id})
+		}
+	}
+	if devices {
+		for _, id := range proposedIds {
+			oldDev, err := current.GetNodeDevice(id)
+			if err != nil {
+				continue
+			}
+			newDev, _ := proposed.GetNodeDevice(id)
+			item := DiffItem{Kind: DiffNodeChanged, Node: id,
+				OldTag: oldDev.Device().Tag(), NewTag: newDev.Device().Tag(),
+				OldInUse: oldDev.Device().InUse(), NewInUse: newDev.Device().InUse()}
+			if item.OldTag != item.NewTag || item.OldInUse != item.NewInUse {
+				add(item)
+			}
+		}
+	}
+	for _, id := range currentIds {
+		if !proposed.NodeIdExists(id) {
+			add(DiffItem{Kind: DiffNodeRemoved, Node: id})
+		}
+	}
+
+	for _, edge := range sortedEdges(proposed) {
+		from, to := edge.From().ID(), edge.To().ID()
+		oldWeight, ok := current.Weight(from, to)
+		if !ok {
+			add(DiffItem{Kind: DiffEdgeAdded, From: from, To: to, NewWeight: edge.Weight()})
+		} else if !sameWeight(edge.Weight(), oldWeight) && math.Abs(edge.Weight()-oldWeight) > threshold {
+			add(DiffItem{Kind: DiffEdgeChanged, From: from, To: to, OldWeight: oldWeight, NewWeight: edge.Weight()})
+		}
+	}
+	for _, edge := range sortedEdges(current) {
+		from, to := edge.From().ID(), edge.To().ID()
+		if !proposed.HasEdgeFromTo(from, to) {
+			add(DiffItem{Kind: DiffEdgeRemoved, From: from, To: to, OldWeight: edge.Weight()})
+		}
+	}
+
+	currentRoutes := current.routes()
+	proposedRoutes := proposed.routes()
+	for _, id := range proposedIds {
+		newPath, ok := proposedRoutes[id]
+		if !ok || id == proposed.localDeviceId {
+			continue
+		}
+		if oldPath := currentRoutes[id]; !slices.Equal(oldPath, newPath) {
+			add(DiffItem{Kind: DiffRouteChanged, Node: id, OldPath: oldPath, NewPath: newPath})
+		}
+	}
+	return items
+}
+
+// ApplyDiffItem apply a single change to the network taking nodes and weights from proposed
+func (g *Network) ApplyDiffItem(proposed *Network, item DiffItem) {
+	switch item.Kind {
+	case DiffNodeAdded:
+		if dev, err := proposed.GetNodeDevice(item.Node); err == nil && !g.NodeIdExists(item.Node) {
+			g.AddNode(dev.CopyDevice())
+		}
+	case DiffNodeRemoved:
+		if item.Node != g.localDeviceId && g.NodeIdExists(item.Node) {
+			g.RemoveNode(item.Node)
+		}
+	case DiffEdgeAdded, DiffEdgeChanged:
+		if !g.NodeIdExists(item.From) {
+			if dev, err := proposed.GetNodeDevice(item.From); err == nil {
+				g.AddNode(dev.CopyDevice())
+			}
+		}
+		if !g.NodeIdExists(item.To) {
+			if dev, err := proposed.GetNodeDevice(item.To); err == nil {
+				g.AddNode(dev.CopyDevice())
+			}
+		}
+		g.ChangeEdgeWeight(item.From, item.To, item.NewWeight, item.NewWeight)
+	case DiffEdgeRemoved:
+		g.RemoveEdge(item.From, item.To)
+		g.SetLinkAttributes(item.From, item.To, LinkAttributes{})
+	case DiffNodeChanged:
+		if dev, err := g.GetNodeDevice(item.Node); err == nil {
+			dev.Device().SetTag(item.NewTag)
+			dev.Device().SetInUse(item.NewInUse)
+		}
+	}
+}
+
+// sameWeight compare two weights at the float32 precision used by the graph files
+func sameWeight(a float64, b float64) bool {
+	return float32(a) == float32(b)
+}
+
+// DiffItemIsCurrent reports if the item can still be applied to the network: an item is stale
+// when applying it would overwrite a change made after the diff. The items whose change is
+// already in the network are current, applying them does nothing.
+func (g *Network) DiffItemIsCurrent(item DiffItem) bool {
+	switch item.Kind {
+	case DiffEdgeAdded:
+		return !g.HasEdgeFromTo(item.From, item.To)
+	case DiffEdgeChanged:
+		weight, ok := g.Weight(item.From, item.To)
+		return ok && g.HasEdgeFromTo(item.From, item.To) && sameWeight(weight, item.OldWeight)
+	case DiffEdgeRemoved:
+		weight, ok := g.Weight(item.From, item.To)
+		return !ok || !g.HasEdgeFromTo(item.From, item.To) || sameWeight(weight, item.OldWeight)
+	case DiffNodeChanged:
+		dev, err := g.GetNodeDevice(item.Node)
+		return err != nil || (dev.Device().Tag() == item.OldTag && dev.Device().InUse() == item.OldInUse)
+	}
+	return true
+}
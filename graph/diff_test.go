@@ -0,0 +1,91 @@
+package graph
+
+import "testing"
+
+func TestApplyDiffEdgeRemovedClearLink(t *testing.T) {
+	current := newChainNetwork(3)
+	current.SetLinkAttributes(2, 3, LinkAttributes{Preferred: true})
+	proposed := newChainNetwork(3)
+	proposed.RemoveEdge(2, 3)
+
+	items := DiffNetworks(current, proposed, 0)
+	if len(items) == 0 || items[0].Kind != DiffEdgeRemoved {
+		t.Fatalf("unexpected diff %+v", items)
+	}
+	current.ApplyDiffItem(proposed, items[0])
+	if current.HasEdgeFromTo(2, 3) {
+		t.Fatal("edge not removed")
+	}
+	if current.LinkAttributes(2, 3).Preferred {
+		t.Fatal("attributes of the removed link kept")
+	}
+}
+
+func TestDiffItemIsCurrent(t *testing.T) {
+	current := newChainNetwork(4)
+	proposed := newChainNetwork(4)
+	proposed.AddNode(NewNodeDevice(5, true, ""))
+	proposed.ChangeEdgeWeight(4, 5, 0.2, 0.2)
+	proposed.ChangeEdgeWeight(1, 2, 0.5, 0.5)
+	proposed.ChangeEdgeWeight(2, 3, 0.5, 0.5)
+	proposed.RemoveEdge(3, 2)
+	proposed.RemoveEdge(4, 3)
+
+	items := DiffNetworks(current, proposed, 0)
+	for _, item := range items {
+		if !current.DiffItemIsCurrent(item) {
+			t.Fatalf("item %+v not current before any change", item)
+		}
+	}
+
+	// Changes made after the diff, only the ones the items would overwrite make them stale
+	current.AddNode(NewNodeDevice(5, true, ""))
+	current.ChangeEdgeWeight(4, 5, 0.3, 0.3)
+	current.ChangeEdgeWeight(1, 2, 0.3, 0.3)
+	current.RemoveEdge(2, 3)
+	current.ChangeEdgeWeight(3, 2, 0.3, 0.3)
+	current.RemoveEdge(4, 3)
+	stale := map[[2]int64]bool{{4, 5}: true, {1, 2}: true, {2, 3}: true, {3, 2}: true}
+	for _, item := range items {
+		if item.Kind == DiffRouteChanged || item.Kind == DiffNodeAdded {
+			continue
+		}
+		if want := !stale[[2]int64{item.From, item.To}]; current.DiffItemIsCurrent(item) != want {
+			t.Errorf("item %+v current %v, want %v", item, !want, want)
+		}
+	}
+}
+
+func TestDiffNetworkVersionsWeightPrecision(t *testing.T) {
+	older := newChainNetwork(3)
+	newer := newChainNetwork(3)
+	// The graph files store the weights as float32
+	newer.ChangeEdgeWeight(1, 2, float64(float32(0.1)), float64(float32(0.1)))
+	if items := DiffNetworkVersions(older, newer); len(items) != 0 {
+		t.Fatalf("weight rounded to float32 reported as changed: %+v", items)
+	}
+
+	newer.ChangeEdgeWeight(1, 2, 0.1001, 0.1001)
+	items := DiffNetworkVersions(older, newer)
+	if len(items) != 1 || items[0].Kind != DiffEdgeChanged || items[0].From != 1 || items[0].To != 2 {
+		t.Fatalf("unexpected diff %+v", items)
+	}
+}
+
+func TestApplyDiffRoundTrip(t *testing.T) {
+	current := newChainNetwork(5)
+	proposed := newImportNetwork()
+	proposed.AddNode(NewNodeDevice(7, true, "new"))
+	proposed.ChangeEdgeWeight(4, 7, 0.2, 0.2)
+
+	result := current.CopyNetwork()
+	for _, item := range DiffNetworks(current, proposed, 0) {
+		if !result.DiffItemIsCurrent(item) {
+			t.Fatalf("item %+v not current", item)
+		}
+		result.ApplyDiffItem(proposed, item)
+	}
+	if items := DiffNetworks(result, proposed, 0); len(items) != 0 {
+		t.Fatalf("differences left after applying the diff: %+v", items)
+	}
+}
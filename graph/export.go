@@ -0,0 +1,429 @@
+package graph
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"leguru.net/m/v2/utils"
+)
+
+type ExportFormat string
+
+const (
+	// ExportDot is a Graphviz digraph, the links of the routing tree are highlighted
+	ExportDot ExportFormat = "dot"
+	// ExportGexf is the Gephi exchange format
+	ExportGexf ExportFormat = "gexf"
+	// ExportJson is the D3 style node-link JSON
+	ExportJson ExportFormat = "json"
+	// ExportCsvNodes and ExportCsvEdges are the tables of the nodes and of the edges
+	ExportCsvNodes ExportFormat = "csv_nodes"
+	ExportCsvEdges ExportFormat = "csv_edges"
+	// ExportCsv is a zip archive with both the tables
+	ExportCsv ExportFormat = "csv"
+)
+
+var ExportFormats = []ExportFormat{ExportDot, ExportGexf, ExportJson, ExportCsvNodes, ExportCsvEdges, ExportCsv}
+
+func ParseExportFormat(format string) (ExportFormat, error) {
+	for _, f := range ExportFormats {
+		if string(f) == format {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown export format %s", format)
+}
+
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case ExportDot:
+		return "text/vnd.graphviz"
+	case ExportGexf:
+		return "application/xml"
+	case ExportJson:
+		return "application/json"
+	case ExportCsv:
+		return "application/zip"
+	}
+	return "text/csv"
+}
+
+func (f ExportFormat) Extension() string {
+	switch f {
+	case ExportCsvNodes:
+		return "nodes.csv"
+	case ExportCsvEdges:
+		return "edges.csv"
+	case ExportCsv:
+		return "csv.zip"
+	}
+	return string(f)
+}
+
+// exportNode and exportEdge are the values written by all the formats
+type exportNode struct {
+	Id            int64
+	Tag           string
+	InUse         bool
+	Local         bool
+	Chip          string
+	WeightProfile string
+	// Hops and Path of the route from the local device, -1 and empty when unreachable
+	Hops int
+	Path []int64
+}
+
+type exportEdge struct {
+	From      int64
+	To        int64
+	Weight    float64
+	Pinned    bool
+	Excluded  bool
+	Preferred bool
+	// Routing is true for the links used by the routes from the local device
+	Routing bool
+}
+
+func (g *Network) exportData() ([]exportNode, []exportEdge) {
+	tree := g.shortestTree()
+	routing := make(map[linkKey]bool)
+	nodes := make([]exportNode, 0)
+	for _, id := range sortedNodeIds(g) {
+		dev, err := g.GetNodeDevice(id)
+		if err != nil {
+			continue
+		}
+		node := exportNode{
+			Id:            id,
+			Tag:           dev.Device().Tag(),
+			InUse:         dev.Device().InUse(),
+			Local:         id == g.localDeviceId,
+			Chip:          dev.Device().Chip(),
+			WeightProfile: dev.Device().WeightProfile(),
+			Hops:          -1,
+		}
+		if route, ok := tree.route(g, id); ok {
+			node.Hops = route.Hops()
+			node.Path = route.Path
+			for i := 1; i < len(route.Path); i++ {
+				routing[linkKey{route.Path[i-1], route.Path[i]}] = true
+			}
+		}
+		nodes = append(nodes, node)
+	}
+
+	edges := make([]exportEdge, 0)
+	for _, edge := range sortedEdges(g) {
+		from, to := edge.From().ID(), edge.To().ID()
+		attrs := g.LinkAttributes(from, to)
+		// Weights are stored as float32, drop the digits added by the conversion
+		weight, _ := strconv.ParseFloat(strconv.FormatFloat(edge.Weight(), 'f', -1, 32), 64)
+		edges = append(edges, exportEdge{
+			From:      from,
+			To:        to,
+			Weight:    weight,
+			Pinned:    attrs.Pinned,
+			Excluded:  attrs.Excluded,
+			Preferred: attrs.Preferred,
+			Routing:   routing[linkKey{from, to}],
+		})
+	}
+	return nodes, edges
+}
+
+// Export write the network in the requested format
+func (g *Network) Export(w io.Writer, format ExportFormat) error {
+	nodes, edges := g.exportData()
+	switch format {
+	case ExportDot:
+		return exportDot(w, nodes, edges)
+	case ExportGexf:
+		return exportGexf(w, nodes, edges)
+	case ExportJson:
+		return exportJson(w, nodes, edges)
+	case ExportCsvNodes:
+		return exportCsvNodes(w, nodes)
+	case ExportCsvEdges:
+		return exportCsvEdges(w, edges)
+	case ExportCsv:
+		return exportCsvZip(w, nodes, edges)
+	}
+	return fmt.Errorf("unknown export format %s", format)
+}
+
+func formatWeight(weight float64) string {
+	return strconv.FormatFloat(weight, 'f', -1, 64)
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+func exportDot(w io.Writer, nodes []exportNode, edges []exportEdge) error {
+	var b strings.Builder
+	b.WriteString("digraph meshmesh {\n")
+	b.WriteString("  node [shape=box];\n")
+	for _, node := range nodes {
+		label := utils.FmtNodeId(node.Id)
+		if node.Tag != "" {
+			label = node.Tag + "\n" + label
+		}
+		attrs := []string{"label=" + dotQuote(label)}
+		if node.Local {
+			attrs = append(attrs, "style=filled", "fillcolor=lightblue")
+		} else if !node.InUse {
+			attrs = append(attrs, "style=dashed", "fontcolor=gray")
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", dotQuote(utils.FmtNodeId(node.Id)), strings.Join(attrs, ", "))
+	}
+	for _, edge := range edges {
+		attrs := []string{"label=" + dotQuote(formatWeight(edge.Weight))}
+		if edge.Routing {
+			attrs = append(attrs, "color=red", "penwidth=2")
+		} else {
+			attrs = append(attrs, "color=gray")
+		}
+		if edge.Excluded {
+			attrs = append(attrs, "style=dotted")
+		} else if edge.Pinned {
+			attrs = append(attrs, "style=bold")
+		}
+		fmt.Fprintf(&b, "  %s -> %s [%s];\n", dotQuote(utils.FmtNodeId(edge.From)), dotQuote(utils.FmtNodeId(edge.To)), strings.Join(attrs, ", "))
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+type gexfAttribute struct {
+	Id    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+type gexfAttributes struct {
+	Class      string          `xml:"class,attr"`
+	Attributes []gexfAttribute `xml:"attribute"`
+}
+
+type gexfValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type gexfNode struct {
+	Id     string      `xml:"id,attr"`
+	Label  string      `xml:"label,attr"`
+	Values []gexfValue `xml:"attvalues>attvalue"`
+}
+
+type gexfEdge struct {
+	Id     string      `xml:"id,attr"`
+	Source string      `xml:"source,attr"`
+	Target string      `xml:"target,attr"`
+	Weight string      `xml:"weight,attr"`
+	Values []gexfValue `xml:"attvalues>attvalue"`
+}
+
+type gexfDocument struct {
+	XMLName xml.Name `xml:"gexf"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Version string   `xml:"version,attr"`
+	Creator string   `xml:"meta>creator"`
+	Graph   struct {
+		EdgeType   string           `xml:"defaultedgetype,attr"`
+		Attributes []gexfAttributes `xml:"attributes"`
+		Nodes      []gexfNode       `xml:"nodes>node"`
+		Edges      []gexfEdge       `xml:"edges>edge"`
+	} `xml:"graph"`
+}
+
+func exportGexf(w io.Writer, nodes []exportNode, edges []exportEdge) error {
+	doc := gexfDocument{Xmlns: "http://gexf.net/1.3", Version: "1.3", Creator: "meshmeshgo"}
+	doc.Graph.EdgeType = "directed"
+	doc.Graph.Attributes = []gexfAttributes{
+		{Class: "node", Attributes: []gexfAttribute{
+			{"tag", "tag", "string"},
+			{"inuse", "inuse", "boolean"},
+			{"local", "local", "boolean"},
+			{"chip", "chip", "string"},
+			{"weightprofile", "weightprofile", "string"},
+			{"hops", "hops", "integer"},
+		}},
+		{Class: "edge", Attributes: []gexfAttribute{
+			{"pinned", "pinned", "boolean"},
+			{"excluded", "excluded", "boolean"},
+			{"preferred", "preferred", "boolean"},
+			{"routing", "routing", "boolean"},
+		}},
+	}
+	for _, node := range nodes {
+		label := node.Tag
+		if label == "" {
+			label = utils.FmtNodeId(node.Id)
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, gexfNode{Id: utils.FmtNodeId(node.Id), Label: label, Values: []gexfValue{
+			{"tag", node.Tag},
+			{"inuse", strconv.FormatBool(node.InUse)},
+			{"local", strconv.FormatBool(node.Local)},
+			{"chip", node.Chip},
+			{"weightprofile", node.WeightProfile},
+			{"hops", strconv.Itoa(node.Hops)},
+		}})
+	}
+	for i, edge := range edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, gexfEdge{
+			Id:     strconv.Itoa(i),
+			Source: utils.FmtNodeId(edge.From),
+			Target: utils.FmtNodeId(edge.To),
+			Weight: formatWeight(edge.Weight),
+			Values: []gexfValue{
+				{"pinned", strconv.FormatBool(edge.Pinned)},
+				{"excluded", strconv.FormatBool(edge.Excluded)},
+				{"preferred", strconv.FormatBool(edge.Preferred)},
+				{"routing", strconv.FormatBool(edge.Routing)},
+			},
+		})
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err = encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+type jsonNode struct {
+	Id            string  `json:"id"`
+	Node          int64   `json:"node"`
+	Tag           string  `json:"tag"`
+	InUse         bool    `json:"inuse"`
+	Local         bool    `json:"local"`
+	Chip          string  `json:"chip,omitempty"`
+	WeightProfile string  `json:"weightprofile,omitempty"`
+	Hops          int     `json:"hops"`
+	Path          []int64 `json:"path"`
+}
+
+type jsonLink struct {
+	Source    string  `json:"source"`
+	Target    string  `json:"target"`
+	Weight    float64 `json:"weight"`
+	Pinned    bool    `json:"pinned"`
+	Excluded  bool    `json:"excluded"`
+	Preferred bool    `json:"preferred"`
+	Routing   bool    `json:"routing"`
+}
+
+type jsonNodeLink struct {
+	Directed   bool       `json:"directed"`
+	Multigraph bool       `json:"multigraph"`
+	Nodes      []jsonNode `json:"nodes"`
+	Links      []jsonLink `json:"links"`
+}
+
+func exportJson(w io.Writer, nodes []exportNode, edges []exportEdge) error {
+	doc := jsonNodeLink{Directed: true, Nodes: make([]jsonNode, 0, len(nodes)), Links: make([]jsonLink, 0, len(edges))}
+	for _, node := range nodes {
+		path := node.Path
+		if path == nil {
+			path = []int64{}
+		}
+		doc.Nodes = append(doc.Nodes, jsonNode{
+			Id:            utils.FmtNodeId(node.Id),
+			Node:          node.Id,
+			Tag:           node.Tag,
+			InUse:         node.InUse,
+			Local:         node.Local,
+			Chip:          node.Chip,
+			WeightProfile: node.WeightProfile,
+			Hops:          node.Hops,
+			Path:          path,
+		})
+	}
+	for _, edge := range edges {
+		doc.Links = append(doc.Links, jsonLink{
+			Source:    utils.FmtNodeId(edge.From),
+			Target:    utils.FmtNodeId(edge.To),
+			Weight:    edge.Weight,
+			Pinned:    edge.Pinned,
+			Excluded:  edge.Excluded,
+			Preferred: edge.Preferred,
+			Routing:   edge.Routing,
+		})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
+
+func exportCsvNodes(w io.Writer, nodes []exportNode) error {
+	writer := csv.NewWriter(w)
+	_ = writer.Write([]string{"id", "node", "tag", "inuse", "local", "chip", "weightprofile", "hops", "path"})
+	for _, node := range nodes {
+		_ = writer.Write([]string{
+			utils.FmtNodeId(node.Id),
+			strconv.FormatInt(node.Id, 10),
+			node.Tag,
+			strconv.FormatBool(node.InUse),
+			strconv.FormatBool(node.Local),
+			node.Chip,
+			node.WeightProfile,
+			strconv.Itoa(node.Hops),
+			utils.FmtPath2Str(node.Path),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func exportCsvEdges(w io.Writer, edges []exportEdge) error {
+	writer := csv.NewWriter(w)
+	_ = writer.Write([]string{"from", "to", "weight", "pinned", "excluded", "preferred", "routing"})
+	for _, edge := range edges {
+		_ = writer.Write([]string{
+			utils.FmtNodeId(edge.From),
+			utils.FmtNodeId(edge.To),
+			formatWeight(edge.Weight),
+			strconv.FormatBool(edge.Pinned),
+			strconv.FormatBool(edge.Excluded),
+			strconv.FormatBool(edge.Preferred),
+			strconv.FormatBool(edge.Routing),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// exportCsvZip write the tables of the nodes and of the edges in a zip archive, the files are
+// named as the csv_nodes and csv_edges downloads
+func exportCsvZip(w io.Writer, nodes []exportNode, edges []exportEdge) error {
+	archive := zip.NewWriter(w)
+	file, err := archive.Create("meshmesh." + ExportCsvNodes.Extension())
+	if err != nil {
+		return err
+	}
+	if err = exportCsvNodes(file, nodes); err != nil {
+		return err
+	}
+	file, err = archive.Create("meshmesh." + ExportCsvEdges.Extension())
+	if err != nil {
+		return err
+	}
+	if err = exportCsvEdges(file, edges); err != nil {
+		return err
+	}
+	return archive.Close()
+}
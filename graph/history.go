@@ -0,0 +1,214 @@
+package graph
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"leguru.net/m/v2/logger"
+)
+
+const historyIndexFilename = "history.json"
+
+// GraphVersion is a network saved in the history
+type GraphVersion struct {
+	Id    int       `json:"id"`
+	Time  time.Time `json:"time"`
+	Nodes int       `json:"nodes"`
+	Edges int       `json:"edges"`
+	// Rollback is the version restored by this one, 0 for the normal changes
+	Rollback int `json:"rollback,omitempty"`
+}
+
+// GraphHistory keep the versions of the main network, a version is recorded at every change
+type GraphHistory struct {
+	lock        sync.Mutex
+	dir         string
+	maxVersions int
+	maxAge      time.Duration
+	rollback    int
+	// lastSum is the hash of the last version, computed from its file when missing
+	lastSum  []byte
+	NextId   int            `json:"next_id"`
+	Versions []GraphVersion `json:"versions"`
+}
+
+var graphHistory = &GraphHistory{NextId: 1}
+
+// GetGraphHistory returns the global graph history, it's disabled until loaded
+func GetGraphHistory() *GraphHistory {
+	return graphHistory
+}
+
+// LoadGraphHistory open the history kept in dir. The versions exceeding maxVersions or older than
+// maxAge are deleted, 0 disables the limit. The last version is always kept.
+func LoadGraphHistory(dir string, maxVersions int, maxAge time.Duration) error {
+	graphHistory.lock.Lock()
+	defer graphHistory.lock.Unlock()
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	graphHistory.dir = dir
+	graphHistory.maxVersions = maxVersions
+	graphHistory.maxAge = maxAge
+	graphHistory.lastSum = nil
+
+	data, err := os.ReadFile(filepath.Join(dir, historyIndexFilename))
+	if err == nil {
+		history := GraphHistory{}
+		if err := json.Unmarshal(data, &history); err != nil {
+			logger.WithFields(logger.Fields{"dir": dir, "err": err}).Warn("Invalid graph history index, starting a new history")
+		} else {
+			graphHistory.NextId = max(history.NextId, 1)
+			graphHistory.Versions = graphHistory.Versions[:0]
+			for _, version := range history.Versions {
+				if _, err := os.Stat(graphHistory.filename(version.Id)); err == nil {
+					graphHistory.Versions = append(graphHistory.Versions, version)
+				}
+			}
+		}
+	}
+	graphHistory.prune()
+	return graphHistory.save()
+}
+
+func (h *GraphHistory) filename(id int) string {
+	return filepath.Join(h.dir, fmt.Sprintf("meshmesh_%06d.graphml", id))
+}
+
+func (h *GraphHistory) save() error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(h.dir, historyIndexFilename), data, 0644)
+}
+
+// prune delete the versions beyond the retention limits
+func (h *GraphHistory) prune() {
+	for len(h.Versions) > 1 {
+		oldest := h.Versions[0]
+		tooMany := h.maxVersions > 0 && len(h.Versions) > h.maxVersions
+		tooOld := h.maxAge > 0 && time.Since(oldest.Time) > h.maxAge
+		if !tooMany && !tooOld {
+			break
+		}
+		_ = os.Remove(h.filename(oldest.Id))
+		h.Versions = h.Versions[1:]
+	}
+}
+
+// Record save the network as a new version unless it's equal to the last one
+func (h *GraphHistory) Record(network *Network) (GraphVersion, error) {
+	data, err := network.encodeGraph()
+	if err != nil {
+		return GraphVersion{}, err
+	}
+	return h.record(network, data)
+}
+
+// SaveAndRecord write the network to filename and record it as a new version, the network is encoded once
+func (h *GraphHistory) SaveAndRecord(network *Network, filename string) (GraphVersion, error) {
+	data, err := network.encodeGraph()
+	if err != nil {
+		return GraphVersion{}, err
+	}
+	if err = saveGraphFile(filename, data); err != nil {
+		return GraphVersion{}, err
+	}
+	return h.record(network, data)
+}
+
+// record add data, the encoded network, as a new version unless its hash is the one of the last version
+func (h *GraphHistory) record(network *Network, data []byte) (GraphVersion, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.dir == "" {
+		return GraphVersion{}, nil
+	}
+	rollback := h.rollback
+	h.rollback = 0
+
+	sum := sha256.Sum256(data)
+	if len(h.Versions) > 0 {
+		last := h.Versions[len(h.Versions)-1]
+		if h.lastSum == nil {
+			if previous, err := os.ReadFile(h.filename(last.Id)); err == nil {
+				previousSum := sha256.Sum256(previous)
+				h.lastSum = previousSum[:]
+			}
+		}
+		if bytes.Equal(h.lastSum, sum[:]) {
+			return last, nil
+		}
+	}
+	if err := os.WriteFile(h.filename(h.NextId), data, 0644); err != nil {
+		return GraphVersion{}, err
+	}
+	h.lastSum = sum[:]
+
+	version := GraphVersion{Id: h.NextId, Time: time.Now(), Nodes: network.Nodes().Len(), Edges: network.WeightedEdges().Len(), Rollback: rollback}
+	h.Versions = append(h.Versions, version)
+	h.NextId++
+	h.prune()
+	return version, h.save()
+}
+
+// List returns the versions from the oldest to the newest
+func (h *GraphHistory) List() []GraphVersion {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return append([]GraphVersion(nil), h.Versions...)
+}
+
+func (h *GraphHistory) Get(id int) (GraphVersion, bool) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	for _, version := range h.Versions {
+		if version.Id == id {
+			return version, true
+		}
+	}
+	return GraphVersion{}, false
+}
+
+// Load read a version of the network
+func (h *GraphHistory) Load(id int, localDeviceId int64) (*Network, error) {
+	if _, ok := h.Get(id); !ok {
+		return nil, fmt.Errorf("graph version %d not found", id)
+	}
+	return NewNeworkFromFile(h.filename(id), localDeviceId)
+}
+
+// Diff returns the changes from the version from to the version to
+func (h *GraphHistory) Diff(from int, to int, localDeviceId int64) ([]DiffItem, error) {
+	older, err := h.Load(from, localDeviceId)
+	if err != nil {
+		return nil, err
+	}
+	newer, err := h.Load(to, localDeviceId)
+	if err != nil {
+		return nil, err
+	}
+	return DiffNetworkVersions(older, newer), nil
+}
+
+// Rollback replace the main network with a version, the change is recorded as a new version
+func (h *GraphHistory) Rollback(id int) error {
+	network, err := h.Load(id, GetMainNetwork().LocalDeviceId())
+	if err != nil {
+		return err
+	}
+	h.lock.Lock()
+	h.rollback = id
+	h.lock.Unlock()
+	logger.WithField("version", id).Info("Rollback of the network graph")
+	SetMainNetwork(network)
+	return nil
+}
@@ -0,0 +1,542 @@
+package graph
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"gonum.org/v1/gonum/graph/simple"
+	"leguru.net/m/v2/logger"
+	"leguru.net/m/v2/utils"
+)
+
+type ImportFormat string
+
+const (
+	ImportGraphml ImportFormat = "graphml"
+	// ImportJson is the node-link JSON written by the json export
+	ImportJson ImportFormat = "json"
+	// ImportCsv is the node and the edge tables as written by the csv exports, recognized by the header.
+	// Each file is a table or a zip archive of tables.
+	ImportCsv ImportFormat = "csv"
+)
+
+var ImportFormats = []ImportFormat{ImportGraphml, ImportJson, ImportCsv}
+
+func ParseImportFormat(format string) (ImportFormat, error) {
+	for _, f := range ImportFormats {
+		if string(f) == format {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown import format %s", format)
+}
+
+type ImportMode string
+
+const (
+	// ImportReplace use the imported graph as the new network
+	ImportReplace ImportMode = "replace"
+	// ImportMerge add the imported nodes and edges to the network, see MergeNetworks
+	ImportMerge ImportMode = "merge"
+)
+
+func ParseImportMode(mode string) (ImportMode, error) {
+	switch ImportMode(mode) {
+	case ImportReplace, ImportMerge:
+		return ImportMode(mode), nil
+	}
+	return "", fmt.Errorf("unknown import mode %s", mode)
+}
+
+// MergeWeights choose the weight kept by a merge when an edge is in both the networks
+type MergeWeights string
+
+const (
+	// MergeWeightsImported replace the existing weights with the imported ones
+	MergeWeightsImported MergeWeights = "imported"
+	// MergeWeightsExisting keep the existing weights, only the new edges are taken from the import
+	MergeWeightsExisting MergeWeights = "existing"
+	// MergeWeightsNewer keep the weight of the network where the link was seen more recently, a
+	// link is as recent as the less recently seen of its nodes. The existing weight wins the ties.
+	MergeWeightsNewer MergeWeights = "newer"
+)
+
+func ParseMergeWeights(weights string) (MergeWeights, error) {
+	switch MergeWeights(weights) {
+	case MergeWeightsImported, MergeWeightsExisting, MergeWeightsNewer:
+		return MergeWeights(weights), nil
+	}
+	return "", fmt.Errorf("unknown merge weights %s", weights)
+}
+
+// ReadNetwork read a network in one of the import formats, only the csv format accepts more than
+// one file. The local device declared in the data must be localDeviceId, when missing the local
+// device is added as an isolated node.
+func ReadNetwork(files []io.Reader, format ImportFormat, localDeviceId int64) (*Network, error) {
+	if len(files) == 0 {
+		return nil, errors.New("no file to import")
+	}
+	if len(files) > 1 && format != ImportCsv {
+		return nil, fmt.Errorf("the %s format accepts a single file", format)
+	}
+	network := &Network{localDeviceId: localDeviceId, directed: simple.NewWeightedDirectedGraph(0, math.Inf(1))}
+	var declared int64
+	var err error
+	switch format {
+	case ImportGraphml:
+		declared, err = network.readGraphML(files[0])
+	case ImportJson:
+		declared, err = network.readNodeLink(files[0])
+	case ImportCsv:
+		declared, err = network.readCsv(files)
+	default:
+		err = fmt.Errorf("unknown import format %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if declared != 0 && declared != localDeviceId {
+		return nil, fmt.Errorf("imported graph belongs to coordinator %s, the local coordinator is %s", utils.FmtNodeId(declared), utils.FmtNodeId(localDeviceId))
+	}
+	if !network.NodeIdExists(localDeviceId) {
+		network.AddNode(NewNodeDevice(localDeviceId, true, "local"))
+	}
+	return network, nil
+}
+
+// parseImportId accept both the N123456 form and the decimal node number
+func parseImportId(id string) (int64, error) {
+	if n, err := strconv.ParseInt(id, 10, 32); err == nil {
+		return n, nil
+	}
+	return utils.ParseNodeId(id)
+}
+
+func (g *Network) addImportedNode(dev NodeDevice) error {
+	if g.NodeIdExists(dev.ID()) {
+		return fmt.Errorf("duplicated node %s", utils.FmtNodeId(dev.ID()))
+	}
+	g.AddNode(dev)
+	return nil
+}
+
+func (g *Network) addImportedEdge(from int64, to int64, weight float64, attrs LinkAttributes) error {
+	if weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+		return fmt.Errorf("invalid weight %v of edge %s %s", weight, utils.FmtNodeId(from), utils.FmtNodeId(to))
+	}
+	if from == to {
+		return fmt.Errorf("invalid edge from %s to itself", utils.FmtNodeId(from))
+	}
+	for _, id := range []int64{from, to} {
+		if id == g.localDeviceId && !g.NodeIdExists(id) {
+			g.AddNode(NewNodeDevice(id, true, "local"))
+		} else if !g.NodeIdExists(id) {
+			g.AddNode(NewNodeDevice(id, true, ""))
+		}
+	}
+	// The graph file keeps the weights as float32, the imported ones are equal to the saved ones only
+	// after the same conversion
+	weight = float64(float32(weight))
+	g.ChangeEdgeWeight(from, to, weight, weight)
+	g.SetLinkAttributes(from, to, attrs)
+	return nil
+}
+
+func (g *Network) readNodeLink(r io.Reader) (int64, error) {
+	var localDeviceId int64
+	doc := jsonNodeLink{}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return 0, err
+	}
+
+	for _, node := range doc.Nodes {
+		id := node.Node
+		if node.Id != "" {
+			var err error
+			id, err = parseImportId(node.Id)
+			if err != nil {
+				return 0, err
+			}
+		}
+		if id <= 0 {
+			return 0, fmt.Errorf("invalid node id %s", node.Id)
+		}
+		if node.Local {
+			localDeviceId = id
+		}
+		dev := NewNodeDevice(id, node.InUse, node.Tag)
+		dev.Device().SetChip(node.Chip)
+		dev.Device().SetWeightProfile(node.WeightProfile)
+		if err := g.addImportedNode(dev); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, link := range doc.Links {
+		from, err := parseImportId(link.Source)
+		if err != nil {
+			return 0, err
+		}
+		to, err := parseImportId(link.Target)
+		if err != nil {
+			return 0, err
+		}
+		err = g.addImportedEdge(from, to, link.Weight, LinkAttributes{Pinned: link.Pinned, Excluded: link.Excluded, Preferred: link.Preferred})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return localDeviceId, nil
+}
+
+// readCsvTables returns the tables in r, a csv file or a zip archive of csv files
+func readCsvTables(r io.Reader) ([][][]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		table, err := readCsvTable(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		return [][][]string{table}, nil
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	tables := make([][][]string, 0, len(archive.File))
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		f, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		table, err := readCsvTable(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file.Name, err)
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
+
+func readCsvTable(r io.Reader) ([][]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	table, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(table) == 0 {
+		return nil, errors.New("empty csv table")
+	}
+	return table, nil
+}
+
+func isCsvNodesTable(table [][]string) bool {
+	return slices.Contains(table[0], "id")
+}
+
+// readCsv read the tables of all the files, the node tables are read first so the nodes added by
+// the edges are only the ones missing from them
+func (g *Network) readCsv(files []io.Reader) (int64, error) {
+	tables := make([][][]string, 0, len(files))
+	for _, r := range files {
+		t, err := readCsvTables(r)
+		if err != nil {
+			return 0, err
+		}
+		tables = append(tables, t...)
+	}
+	sort.SliceStable(tables, func(i, j int) bool {
+		return isCsvNodesTable(tables[i]) && !isCsvNodesTable(tables[j])
+	})
+
+	var localDeviceId int64
+	for _, table := range tables {
+		declared, err := g.readCsvRecords(table)
+		if err != nil {
+			return 0, err
+		}
+		if declared != 0 {
+			localDeviceId = declared
+		}
+	}
+	return localDeviceId, nil
+}
+
+func (g *Network) readCsvRecords(table [][]string) (int64, error) {
+	header := table[0]
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[name] = i
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+	boolField := func(record []string, name string, value bool) bool {
+		if b, err := strconv.ParseBool(field(record, name)); err == nil {
+			return b
+		}
+		return value
+	}
+
+	_, nodes := columns["id"]
+	_, from := columns["from"]
+	_, to := columns["to"]
+	if !nodes && !(from && to) {
+		return 0, errors.New("csv header must contain the id column of the nodes or the from and to columns of the edges")
+	}
+
+	var localDeviceId int64
+	for _, record := range table[1:] {
+		if nodes {
+			id, err := parseImportId(field(record, "id"))
+			if err != nil {
+				return 0, err
+			}
+			if boolField(record, "local", false) {
+				localDeviceId = id
+			}
+			dev := NewNodeDevice(id, boolField(record, "inuse", true), field(record, "tag"))
+			dev.Device().SetChip(field(record, "chip"))
+			dev.Device().SetWeightProfile(field(record, "weightprofile"))
+			if err := g.addImportedNode(dev); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		fromId, err := parseImportId(field(record, "from"))
+		if err != nil {
+			return 0, err
+		}
+		toId, err := parseImportId(field(record, "to"))
+		if err != nil {
+			return 0, err
+		}
+		weight, err := strconv.ParseFloat(field(record, "weight"), 64)
+		if err != nil {
+			return 0, err
+		}
+		err = g.addImportedEdge(fromId, toId, weight, LinkAttributes{
+			Pinned:    boolField(record, "pinned", false),
+			Excluded:  boolField(record, "excluded", false),
+			Preferred: boolField(record, "preferred", false),
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return localDeviceId, nil
+}
+
+// linkLastSeen returns when the link was last seen, that is the older of the last seen times of its nodes
+func (g *Network) linkLastSeen(from int64, to int64) time.Time {
+	var seen []time.Time
+	for _, id := range []int64{from, to} {
+		dev, err := g.GetNodeDevice(id)
+		if err != nil {
+			return time.Time{}
+		}
+		seen = append(seen, dev.Device().LastSeen())
+	}
+	if seen[1].Before(seen[0]) {
+		return seen[1]
+	}
+	return seen[0]
+}
+
+// MergeNetworks returns a copy of current with the nodes and the edges of imported added. The
+// existing nodes keep their tag, in use flag, settings and metadata, the empty ones are taken from
+// imported and the last seen time is the newer one. The edges are the union of both, the weight of
+// the edges in both is chosen by weights, a pinned link always keeps the existing weight.
+func MergeNetworks(current *Network, imported *Network, weights MergeWeights) *Network {
+	merged := current.CopyNetwork()
+	for _, id := range sortedNodeIds(imported) {
+		dev, _ := imported.GetNodeDevice(id)
+		existing, err := merged.GetNodeDevice(id)
+		if err != nil {
+			merged.AddNode(dev.CopyDevice())
+			continue
+		}
+		if existing.Device().Tag() == "" {
+			existing.Device().SetTag(dev.Device().Tag())
+		}
+		if existing.Device().Chip() == "" {
+			existing.Device().SetChip(dev.Device().Chip())
+		}
+		if existing.Device().WeightProfile() == "" {
+			existing.Device().SetWeightProfile(dev.Device().WeightProfile())
+		}
+		if existing.Device().Firmware() == "" {
+			existing.Device().SetFirmware(dev.Device().Firmware())
+		}
+		if _, ok := existing.Device().Config(); !ok {
+			if config, ok := dev.Device().Config(); ok {
+				existing.Device().SetConfig(config)
+			}
+		}
+		if dev.Device().LastSeen().After(existing.Device().LastSeen()) {
+			existing.Device().SetLastSeen(dev.Device().LastSeen())
+		}
+		if existing.Device().Location() == "" {
+			existing.Device().SetLocation(dev.Device().Location())
+		}
+		if existing.Device().Notes() == "" {
+			existing.Device().SetNotes(dev.Device().Notes())
+		}
+		if len(existing.Device().Labels()) == 0 {
+			existing.Device().SetLabels(dev.Device().Labels())
+		}
+	}
+
+	for _, edge := range sortedEdges(imported) {
+		from, to := edge.From().ID(), edge.To().ID()
+		attrs := merged.LinkAttributes(from, to)
+		if merged.HasEdgeFromTo(from, to) && (attrs.Pinned || weights == MergeWeightsExisting ||
+			(weights == MergeWeightsNewer && !imported.linkLastSeen(from, to).After(current.linkLastSeen(from, to)))) {
+			continue
+		}
+		merged.ChangeEdgeWeight(from, to, edge.Weight(), edge.Weight())
+		if attrs.isZero() {
+			merged.SetLinkAttributes(from, to, imported.LinkAttributes(from, to))
+		}
+	}
+	return merged
+}
+
+// GraphImport is an imported graph waiting to be committed to the main network
+type GraphImport struct {
+	Mode   ImportMode
+	Format ImportFormat
+	// Weights is used by the merge mode, the imported weights by default
+	Weights MergeWeights
+	Created time.Time
+	network *Network
+
+	// The result of the last preview and the main network it was computed from
+	lock    sync.Mutex
+	result  *Network
+	base    *Network
+	version uint64
+}
+
+// NewGraphImport read the graph to import, the local device must be the one of the main network.
+// A graph replacing the network must link the local device.
+func NewGraphImport(files []io.Reader, format ImportFormat, mode ImportMode) (*GraphImport, error) {
+	localDeviceId := GetMainNetwork().LocalDeviceId()
+	network, err := ReadNetwork(files, format, localDeviceId)
+	if err != nil {
+		return nil, err
+	}
+	if mode == ImportReplace && network.From(localDeviceId).Len() == 0 && network.To(localDeviceId).Len() == 0 {
+		return nil, fmt.Errorf("imported graph has no links to the local coordinator %s", utils.FmtNodeId(localDeviceId))
+	}
+	return &GraphImport{Mode: mode, Format: format, Weights: MergeWeightsImported, Created: time.Now(), network: network}, nil
+}
+
+// Result returns the network obtained importing the graph in current
+func (i *GraphImport) Result(current *Network) *Network {
+	if i.Mode == ImportMerge {
+		return MergeNetworks(current, i.network, i.Weights)
+	}
+	return i.network.CopyNetwork()
+}
+
+// Preview returns the changes the import would make to the main network, the result is the one
+// applied by Commit
+func (i *GraphImport) Preview() []DiffItem {
+	current, base, version := mainNetworkSnapshotOf()
+	result := i.Result(current)
+	i.lock.Lock()
+	i.result, i.base, i.version = result, base, version
+	i.lock.Unlock()
+	return DiffNetworkVersions(current, result)
+}
+
+// Commit replace the main network with the result of the last preview, it fails if the main
+// network changed after the preview. The import is previewed first if it never was.
+func (i *GraphImport) Commit() error {
+	i.lock.Lock()
+	previewed := i.result != nil
+	i.lock.Unlock()
+	if !previewed {
+		i.Preview()
+	}
+
+	i.lock.Lock()
+	result, base, version := i.result, i.base, i.version
+	i.lock.Unlock()
+	if err := replaceMainNetwork(result, base, version); err != nil {
+		return fmt.Errorf("%w after the import preview, preview it again", err)
+	}
+	logger.WithFields(logger.Fields{"mode": i.Mode, "format": i.Format, "weights": i.Weights, "nodes": result.Nodes().Len(), "edges": result.WeightedEdges().Len()}).
+		Info("Imported network graph")
+	return nil
+}
+
+var _graphImport struct {
+	lock    sync.Mutex
+	pending *GraphImport
+}
+
+// SetPendingImport keep the import waiting for the operator review, replacing the previous one
+func SetPendingImport(i *GraphImport) {
+	_graphImport.lock.Lock()
+	defer _graphImport.lock.Unlock()
+	_graphImport.pending = i
+}
+
+// PendingImport returns the import waiting for review, nil if there is none
+func PendingImport() *GraphImport {
+	_graphImport.lock.Lock()
+	defer _graphImport.lock.Unlock()
+	return _graphImport.pending
+}
+
+// CommitPendingImport apply the pending import to the main network
+func CommitPendingImport() error {
+	_graphImport.lock.Lock()
+	defer _graphImport.lock.Unlock()
+	if _graphImport.pending == nil {
+		return errors.New("no graph import to commit")
+	}
+	if err := _graphImport.pending.Commit(); err != nil {
+		return err
+	}
+	_graphImport.pending = nil
+	return nil
+}
+
+// DiscardPendingImport drop the pending import
+func DiscardPendingImport() error {
+	_graphImport.lock.Lock()
+	defer _graphImport.lock.Unlock()
+	if _graphImport.pending == nil {
+		return errors.New("no graph import to discard")
+	}
+	_graphImport.pending = nil
+	return nil
+}
@@ -0,0 +1,235 @@
+package graph
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+// newImportNetwork returns a chain of four nodes with settings and link attributes that all the
+// import formats keep
+func newImportNetwork() *Network {
+	network := newChainNetwork(4)
+	for id, tag := range map[int64]string{2: "kitchen", 3: "garage", 4: "garden"} {
+		dev, _ := network.GetNodeDevice(id)
+		dev.Device().SetTag(tag)
+		dev.Device().SetChip("esp8266")
+	}
+	dev, _ := network.GetNodeDevice(4)
+	dev.Device().SetInUse(false)
+	dev.Device().SetWeightProfile("outdoor")
+	network.ChangeEdgeWeight(2, 3, 0.37, 0.37)
+	network.SetLinkAttributes(1, 2, LinkAttributes{Pinned: true})
+	network.SetLinkAttributes(2, 3, LinkAttributes{Preferred: true})
+	network.SetLinkAttributes(4, 3, LinkAttributes{Excluded: true})
+	return network
+}
+
+// exportFiles returns the network written in the formats, one file each
+func exportFiles(t *testing.T, network *Network, formats ...ExportFormat) []io.Reader {
+	files := make([]io.Reader, 0, len(formats))
+	for _, format := range formats {
+		var buf bytes.Buffer
+		if err := network.Export(&buf, format); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, &buf)
+	}
+	return files
+}
+
+func graphmlFile(t *testing.T, network *Network) []io.Reader {
+	var buf bytes.Buffer
+	if err := network.writeGraph(&buf); err != nil {
+		t.Fatal(err)
+	}
+	return []io.Reader{&buf}
+}
+
+func TestReadNetworkRoundTrip(t *testing.T) {
+	network := newImportNetwork()
+	tests := []struct {
+		name   string
+		format ImportFormat
+		files  []io.Reader
+	}{
+		{"graphml", ImportGraphml, graphmlFile(t, network)},
+		{"json", ImportJson, exportFiles(t, network, ExportJson)},
+		{"csv zip", ImportCsv, exportFiles(t, network, ExportCsv)},
+		// The edges table first, the nodes must be read before it anyway
+		{"csv files", ImportCsv, exportFiles(t, network, ExportCsvEdges, ExportCsvNodes)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imported, err := ReadNetwork(tt.files, tt.format, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if items := DiffNetworkVersions(network, imported); len(items) != 0 {
+				t.Fatalf("imported network differs from the exported one: %+v", items)
+			}
+			for _, id := range sortedNodeIds(network) {
+				want, _ := network.GetNodeDevice(id)
+				got, _ := imported.GetNodeDevice(id)
+				if got.Device().Chip() != want.Device().Chip() || got.Device().WeightProfile() != want.Device().WeightProfile() {
+					t.Errorf("node %d chip %q profile %q, want %q %q", id, got.Device().Chip(), got.Device().WeightProfile(), want.Device().Chip(), want.Device().WeightProfile())
+				}
+			}
+			for _, edge := range sortedEdges(network) {
+				from, to := edge.From().ID(), edge.To().ID()
+				if got, want := imported.LinkAttributes(from, to), network.LinkAttributes(from, to); got != want {
+					t.Errorf("link %d->%d attributes %+v, want %+v", from, to, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestReadNetworkErrors(t *testing.T) {
+	network := newImportNetwork()
+	tests := []struct {
+		name   string
+		format ImportFormat
+		files  []io.Reader
+		local  int64
+	}{
+		{"graphml other coordinator", ImportGraphml, graphmlFile(t, network), 5},
+		{"json other coordinator", ImportJson, exportFiles(t, network, ExportJson), 5},
+		{"csv other coordinator", ImportCsv, exportFiles(t, network, ExportCsv), 5},
+		{"json more files", ImportJson, exportFiles(t, network, ExportJson, ExportJson), 1},
+		{"csv without header", ImportCsv, []io.Reader{bytes.NewBufferString("N000001,N000002,0.1\n")}, 1},
+		{"csv duplicated node", ImportCsv, exportFiles(t, network, ExportCsvNodes, ExportCsvNodes), 1},
+		{"csv negative weight", ImportCsv, []io.Reader{bytes.NewBufferString("from,to,weight\nN000001,N000002,-1\n")}, 1},
+		{"no files", ImportCsv, nil, 1},
+	}
+	for _, tt := range tests {
+		if _, err := ReadNetwork(tt.files, tt.format, tt.local); err == nil {
+			t.Errorf("%s: read without errors", tt.name)
+		}
+	}
+}
+
+func TestGraphImportModes(t *testing.T) {
+	current := newChainNetwork(3)
+	current.ChangeEdgeWeight(1, 2, 0.5, 0.5)
+	current.SetLinkAttributes(2, 3, LinkAttributes{Pinned: true})
+	dev, _ := current.GetNodeDevice(2)
+	dev.Device().SetTag("existing")
+
+	imported := newImportNetwork()
+	imported.ChangeEdgeWeight(2, 3, 0.8, 0.8)
+	imported.RemoveEdge(3, 2)
+
+	tests := []struct {
+		name     string
+		mode     ImportMode
+		weights  MergeWeights
+		tag      string
+		w12, w23 float64
+		edge32   bool
+	}{
+		{"replace", ImportReplace, MergeWeightsImported, "kitchen", 0.1, 0.8, false},
+		{"merge imported", ImportMerge, MergeWeightsImported, "existing", 0.1, 0.1, true},
+		{"merge existing", ImportMerge, MergeWeightsExisting, "existing", 0.5, 0.1, true},
+	}
+	for _, tt := range tests {
+		result := (&GraphImport{Mode: tt.mode, Weights: tt.weights, network: imported}).Result(current)
+		dev, _ := result.GetNodeDevice(2)
+		w12, _ := result.Weight(1, 2)
+		w23, _ := result.Weight(2, 3)
+		if dev.Device().Tag() != tt.tag || w12 != tt.w12 || w23 != tt.w23 || result.HasEdgeFromTo(3, 2) != tt.edge32 {
+			t.Errorf("%s: tag %q weights %.2f %.2f edge 3->2 %v, want %q %.2f %.2f %v", tt.name, dev.Device().Tag(), w12, w23, result.HasEdgeFromTo(3, 2), tt.tag, tt.w12, tt.w23, tt.edge32)
+		}
+		if !result.NodeIdExists(4) {
+			t.Errorf("%s: imported node missing", tt.name)
+		}
+	}
+	if current.NodeIdExists(4) {
+		t.Fatal("import changed the current network")
+	}
+}
+
+func TestNewGraphImportReplaceWithoutCoordinator(t *testing.T) {
+	SetMainNetwork(newChainNetwork(3))
+	data := "from,to,weight\nN000002,N000003,0.1\n"
+	if _, err := NewGraphImport([]io.Reader{bytes.NewBufferString(data)}, ImportCsv, ImportReplace); err == nil {
+		t.Fatal("replace with a graph not linking the coordinator accepted")
+	}
+	if _, err := NewGraphImport([]io.Reader{bytes.NewBufferString(data)}, ImportCsv, ImportMerge); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGraphImportCommitPreview(t *testing.T) {
+	SetMainNetwork(newChainNetwork(3))
+	imported := newChainNetwork(4)
+	graphImport := &GraphImport{Mode: ImportMerge, Weights: MergeWeightsImported, network: imported}
+
+	graphImport.Preview()
+	_ = UpdateMainNetwork(func(network *Network) error {
+		network.ChangeEdgeWeight(1, 2, 0.5, 0.5)
+		return nil
+	})
+	if err := graphImport.Commit(); err == nil {
+		t.Fatal("import committed over a change made after the preview")
+	}
+	if GetMainNetwork().NodeIdExists(4) {
+		t.Fatal("refused import changed the main network")
+	}
+
+	items := graphImport.Preview()
+	if err := graphImport.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	main := GetMainNetwork()
+	if !main.NodeIdExists(4) || len(items) == 0 {
+		t.Fatalf("import not applied, preview %+v", items)
+	}
+	if weight, _ := main.Weight(1, 2); weight != 0.1 {
+		t.Fatalf("weight 1->2 %f, want the imported 0.1", weight)
+	}
+	if len(DiffNetworkVersions(main, graphImport.result)) != 0 || main != graphImport.result {
+		t.Fatal("committed network is not the previewed one")
+	}
+}
+
+func TestMergeWeightsNewer(t *testing.T) {
+	old, recent := time.Now().Add(-time.Hour), time.Now()
+	seen := func(network *Network, times map[int64]time.Time) *Network {
+		for id, seen := range times {
+			dev, _ := network.GetNodeDevice(id)
+			dev.Device().SetLastSeen(seen)
+		}
+		return network
+	}
+	current := seen(newChainNetwork(3), map[int64]time.Time{1: recent, 2: recent, 3: old})
+	imported := seen(newChainNetwork(3), map[int64]time.Time{1: recent, 2: old, 3: recent})
+	imported.ChangeEdgeWeight(1, 2, 0.4, 0.4)
+	imported.ChangeEdgeWeight(2, 3, 0.4, 0.4)
+	imported.ChangeEdgeWeight(1, 3, 0.4, 0.4)
+
+	merged := MergeNetworks(current, imported, MergeWeightsNewer)
+	tests := []struct {
+		from, to int64
+		weight   float64
+	}{
+		// Node 2 is older in the import
+		{1, 2, 0.1},
+		// Both the links are seen at the time of the older node, the existing weight wins
+		{2, 3, 0.1},
+		// New edges are always added
+		{1, 3, 0.4},
+	}
+	for _, tt := range tests {
+		if weight, _ := merged.Weight(tt.from, tt.to); weight != tt.weight {
+			t.Errorf("weight %d->%d %f, want %f", tt.from, tt.to, weight, tt.weight)
+		}
+	}
+
+	imported = seen(newChainNetwork(3), map[int64]time.Time{1: recent, 2: recent, 3: recent})
+	imported.ChangeEdgeWeight(2, 3, 0.4, 0.4)
+	if weight, _ := MergeNetworks(current, imported, MergeWeightsNewer).Weight(2, 3); weight != 0.4 {
+		t.Errorf("weight 2->3 %f, want the newer imported 0.4", weight)
+	}
+}
@@ -0,0 +1,157 @@
+package graph
+
+import (
+	"math"
+	"strconv"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// preferredLinkFactor scale the weight of the preferred links when searching the routes
+const preferredLinkFactor = 0.5
+
+// LinkAttributes are the operator settings of a link that the discovery must respect
+type LinkAttributes struct {
+	// Pinned links keep their weight when the neighbors are discovered again
+	Pinned bool
+	// Excluded links are never used for routing
+	Excluded bool
+	// Preferred links are favoured when searching the routes
+	Preferred bool
+}
+
+func (a LinkAttributes) isZero() bool {
+	return !a.Pinned && !a.Excluded && !a.Preferred
+}
+
+type linkKey struct {
+	from int64
+	to   int64
+}
+
+func (g *Network) LinkAttributes(fromId int64, toId int64) LinkAttributes {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return g.links[linkKey{fromId, toId}]
+}
+
+func (g *Network) SetLinkAttributes(fromId int64, toId int64, attrs LinkAttributes) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.version++
+	g.setLinkAttributes(linkKey{fromId, toId}, attrs)
+}
+
+// setLinkAttributes must be called with the lock held
+func (g *Network) setLinkAttributes(key linkKey, attrs LinkAttributes) {
+	if g.links == nil {
+		g.links = make(map[linkKey]LinkAttributes)
+	}
+	if attrs.isZero() {
+		delete(g.links, key)
+	} else {
+		g.links[key] = attrs
+	}
+}
+
+// linksCopy returns a copy of the links attributes
+func (g *Network) linksCopy() map[linkKey]LinkAttributes {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	links := make(map[linkKey]LinkAttributes, len(g.links))
+	for key, attrs := range g.links {
+		links[key] = attrs
+	}
+	return links
+}
+
+// SetDiscoveredEdgeWeight change the weight of an edge found by the discovery unless it is pinned
+func (g *Network) SetDiscoveredEdgeWeight(fromId int64, toId int64, weight float64) bool {
+	if g.LinkAttributes(fromId, toId).Pinned && g.HasEdgeFromTo(fromId, toId) {
+		return false
+	}
+	g.ChangeEdgeWeight(fromId, toId, weight, weight)
+	return true
+}
+
+// RemoveDiscoveredEdges remove the outgoing edges of a node before a new discovery, pinned edges are kept
+func (g *Network) RemoveDiscoveredEdges(nodeId int64) {
+	nodes := graph.NodesOf(g.From(nodeId))
+	for _, neighbor := range nodes {
+		if !g.LinkAttributes(nodeId, neighbor.ID()).Pinned {
+			g.RemoveEdge(nodeId, neighbor.ID())
+		}
+	}
+}
+
+// routing returns the view of the network used to search the routes: excluded links are hidden and
+// preferred links are cheaper. It must be called with the lock held.
+func (g *Network) routing() *simple.WeightedDirectedGraph {
+	routing := simple.NewWeightedDirectedGraph(0, math.Inf(1))
+	nodes := g.directed.Nodes()
+	for nodes.Next() {
+		routing.AddNode(nodes.Node())
+	}
+	edges := g.directed.WeightedEdges()
+	for edges.Next() {
+		edge := edges.WeightedEdge()
+		attrs := g.links[linkKey{edge.From().ID(), edge.To().ID()}]
+		if attrs.Excluded {
+			continue
+		}
+		weight := edge.Weight()
+		if attrs.Preferred {
+			weight *= preferredLinkFactor
+		}
+		routing.SetWeightedEdge(routing.NewWeightedEdge(edge.From(), edge.To(), weight))
+	}
+	return routing
+}
+
+func attributeBool(attrs map[string]interface{}, name string) bool {
+	switch value := attrs[name].(type) {
+	case bool:
+		return value
+	case string:
+		b, _ := strconv.ParseBool(value)
+		return b
+	}
+	return false
+}
+
+func attributeString(attrs map[string]interface{}, name string) string {
+	value, _ := attrs[name].(string)
+	return value
+}
+
+func attributeInt(attrs map[string]interface{}, name string) (int64, bool) {
+	switch value := attrs[name].(type) {
+	case int:
+		return int64(value), true
+	case int64:
+		return value, true
+	case string:
+		i, err := strconv.ParseInt(value, 10, 64)
+		return i, err == nil
+	}
+	return 0, false
+}
+
+// InheritLinks copy the links attributes and the pinned edges of another network
+func (g *Network) InheritLinks(other *Network) {
+	for key, attrs := range other.linksCopy() {
+		g.SetLinkAttributes(key.from, key.to, attrs)
+		if !attrs.Pinned {
+			continue
+		}
+		if weight, ok := other.Weight(key.from, key.to); ok && other.HasEdgeFromTo(key.from, key.to) {
+			for _, id := range []int64{key.from, key.to} {
+				if dev, err := other.GetNodeDevice(id); err == nil && !g.NodeIdExists(id) {
+					g.AddNode(NewNodeDevice(id, dev.Device().InUse(), dev.Device().Tag()))
+				}
+			}
+			g.ChangeEdgeWeight(key.from, key.to, weight, weight)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package graph
+
+import (
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/iterator"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// Version returns a number incremented at every change of nodes, edges or links
+func (g *Network) Version() uint64 {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return g.version
+}
+
+func (g *Network) Node(id int64) graph.Node {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return g.directed.Node(id)
+}
+
+func (g *Network) Nodes() graph.Nodes {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return iterator.NewOrderedNodes(graph.NodesOf(g.directed.Nodes()))
+}
+
+func (g *Network) From(id int64) graph.Nodes {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return iterator.NewOrderedNodes(graph.NodesOf(g.directed.From(id)))
+}
+
+func (g *Network) To(id int64) graph.Nodes {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return iterator.NewOrderedNodes(graph.NodesOf(g.directed.To(id)))
+}
+
+func (g *Network) HasEdgeBetween(xid, yid int64) bool {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return g.directed.HasEdgeBetween(xid, yid)
+}
+
+func (g *Network) HasEdgeFromTo(uid, vid int64) bool {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return g.directed.HasEdgeFromTo(uid, vid)
+}
+
+func (g *Network) Edge(uid, vid int64) graph.Edge {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return g.directed.Edge(uid, vid)
+}
+
+func (g *Network) WeightedEdge(uid, vid int64) graph.WeightedEdge {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return g.directed.WeightedEdge(uid, vid)
+}
+
+func (g *Network) Weight(xid, yid int64) (float64, bool) {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return g.directed.Weight(xid, yid)
+}
+
+func (g *Network) Edges() graph.Edges {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return iterator.NewOrderedEdges(graph.EdgesOf(g.directed.Edges()))
+}
+
+func (g *Network) WeightedEdges() graph.WeightedEdges {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	return iterator.NewOrderedWeightedEdges(graph.WeightedEdgesOf(g.directed.WeightedEdges()))
+}
+
+func (g *Network) NewWeightedEdge(from, to graph.Node, weight float64) graph.WeightedEdge {
+	return simple.WeightedEdge{F: from, T: to, W: weight}
+}
+
+func (g *Network) AddNode(n graph.Node) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.version++
+	g.directed.AddNode(n)
+}
+
+// RemoveNode remove the node, its edges and the attributes of its links
+func (g *Network) RemoveNode(id int64) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.version++
+	g.directed.RemoveNode(id)
+	for key := range g.links {
+		if key.from == id || key.to == id {
+			delete(g.links, key)
+		}
+	}
+}
+
+func (g *Network) SetWeightedEdge(e graph.WeightedEdge) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.version++
+	g.directed.SetWeightedEdge(e)
+}
+
+func (g *Network) RemoveEdge(fid, tid int64) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.version++
+	g.directed.RemoveEdge(fid, tid)
+}
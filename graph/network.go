@@ -1,47 +1,218 @@
 package graph
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"math"
+	"os"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
-	"github.com/sirupsen/logrus"
-	"gonum.org/v1/gonum/graph/path"
 	"gonum.org/v1/gonum/graph/simple"
 	"leguru.net/m/v2/logger"
 	"leguru.net/m/v2/utils"
 )
 
+// NodeConfig is the configuration reported by the node firmware
+type NodeConfig struct {
+	Channel      uint8
+	TxPower      uint8
+	Groups       uint32
+	BindedServer uint32
+	Flags        uint8
+}
+
 type Device struct {
+	lock       sync.RWMutex
 	inuse      bool
 	discovered bool
 	tag        string
+	// chip type and rssi weight profile of the node, empty for the defaults
+	chip          string
+	weightProfile string
+	// firmware revision and configuration last read from the node, config is nil until read
+	firmware string
+	config   *NodeConfig
+	lastSeen time.Time
+	// location, notes and labels are set by the user
+	location string
+	notes    string
+	labels   []string
 }
 
-func (d Device) InUse() bool {
+func (d *Device) InUse() bool {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
 	return d.inuse
 }
 
 func (d *Device) SetInUse(inuse bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
 	d.inuse = inuse
 }
 
-func (d Device) Discovered() bool {
+func (d *Device) Discovered() bool {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
 	return d.discovered
 }
 
 func (d *Device) SetDiscovered(discovered bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
 	d.discovered = discovered
 }
 
-func (d Device) Tag() string {
+func (d *Device) Tag() string {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
 	return d.tag
 }
 
 func (d *Device) SetTag(tag string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
 	d.tag = tag
 }
 
+func (d *Device) Chip() string {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return d.chip
+}
+
+func (d *Device) SetChip(chip string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.chip = chip
+}
+
+func (d *Device) WeightProfile() string {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return d.weightProfile
+}
+
+func (d *Device) SetWeightProfile(profile string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.weightProfile = profile
+}
+
+func (d *Device) Firmware() string {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return d.firmware
+}
+
+func (d *Device) SetFirmware(firmware string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.firmware = firmware
+}
+
+// Config returns the last configuration read from the node, false if never read
+func (d *Device) Config() (NodeConfig, bool) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	if d.config == nil {
+		return NodeConfig{}, false
+	}
+	return *d.config, true
+}
+
+func (d *Device) SetConfig(config NodeConfig) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.config = &config
+}
+
+// LastSeen returns the last time the node replied to the hub, zero if never
+func (d *Device) LastSeen() time.Time {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return d.lastSeen
+}
+
+func (d *Device) SetLastSeen(seen time.Time) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.lastSeen = seen
+}
+
+func (d *Device) Location() string {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return d.location
+}
+
+func (d *Device) SetLocation(location string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.location = location
+}
+
+func (d *Device) Notes() string {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return d.notes
+}
+
+func (d *Device) SetNotes(notes string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.notes = notes
+}
+
+func (d *Device) Labels() []string {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return append([]string{}, d.labels...)
+}
+
+// SetLabels set the user labels, the empty ones are dropped
+func (d *Device) SetLabels(labels []string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.labels = make([]string, 0, len(labels))
+	for _, label := range labels {
+		if label = strings.TrimSpace(label); label != "" {
+			d.labels = append(d.labels, label)
+		}
+	}
+}
+
+// ValidateLabels check the labels can be saved in the graph file, where they are comma separated
+func ValidateLabels(labels []string) error {
+	for _, label := range labels {
+		if strings.Contains(label, ",") {
+			return fmt.Errorf("label %q contains a comma", label)
+		}
+	}
+	return nil
+}
+
+// copyMetadata copy the settings and the metadata of other, it must be called with the lock held
+func (d *Device) copyMetadata(other *Device) {
+	d.chip = other.chip
+	d.weightProfile = other.weightProfile
+	d.firmware = other.firmware
+	if other.config != nil {
+		config := *other.config
+		d.config = &config
+	} else {
+		d.config = nil
+	}
+	d.lastSeen = other.lastSeen
+	d.location = other.location
+	d.notes = other.notes
+	d.labels = append([]string{}, other.labels...)
+}
+
 func NewDevice(inuse bool, tag string) *Device {
 	return &Device{inuse: inuse, tag: tag}
 }
@@ -59,8 +230,17 @@ func (n NodeDevice) Device() *Device {
 	return n.device
 }
 
+// CopyDevice returns the node with a copy of its device, changes to the copy don't affect the original
 func (n NodeDevice) CopyDevice() NodeDevice {
-	return NodeDevice{id: n.id, device: n.device}
+	n.device.lock.RLock()
+	defer n.device.lock.RUnlock()
+	device := &Device{
+		inuse:      n.device.inuse,
+		discovered: n.device.discovered,
+		tag:        n.device.tag,
+	}
+	device.copyMetadata(n.device)
+	return NodeDevice{id: n.id, device: device}
 }
 
 func NewNodeDevice(id int64, inuse bool, tag string) NodeDevice {
@@ -72,6 +252,17 @@ var mainNetwork *Network
 var mainNetworkChancgedCallbacks []func()
 var mainNetworkLock sync.Mutex
 
+// State of the main network updates, guarded by mainNetworkLock
+var mainNetworkBatches int
+var mainNetworkPending bool
+var mainNetworkSnapshot *Network
+var mainNetworkSnapshotVersion uint64
+
+var errMainNetworkChanged = errors.New("network graph changed in the meantime")
+
+// mainNetworkIdle is signalled when the last running update completes
+var mainNetworkIdle = sync.NewCond(&mainNetworkLock)
+
 func GetMainNetwork() *Network {
 	mainNetworkLock.Lock()
 	defer mainNetworkLock.Unlock()
@@ -79,27 +270,151 @@ func GetMainNetwork() *Network {
 }
 
 // SetMainNetwork sets the current main network instance pointer and notify all callbacks.
-// It acquires a lock to ensure thread-safe access to the global mainNetwork variable.
+// It waits for the running updates to complete, so their changes are not lost with the
+// replaced network. It must not be called from inside an update.
 func SetMainNetwork(network *Network) {
+	_ = replaceMainNetwork(network, nil, 0)
+}
+
+// replaceMainNetwork set the main network like SetMainNetwork, when base is not nil the network
+// is replaced only if the main network is still base at the given version.
+func replaceMainNetwork(network *Network, base *Network, version uint64) error {
 	mainNetworkLock.Lock()
+	for mainNetworkBatches > 0 {
+		mainNetworkIdle.Wait()
+	}
+	if base != nil && (mainNetwork != base || mainNetwork.Version() != version) {
+		mainNetworkLock.Unlock()
+		return errMainNetworkChanged
+	}
 	mainNetwork = network
+	mainNetworkSnapshot = nil
 	mainNetworkLock.Unlock()
 	NotifyMainNetworkChanged()
+	return nil
+}
+
+// updateSnapshot must be called with mainNetworkLock held
+func updateSnapshot() *Network {
+	if mainNetwork == nil {
+		return nil
+	}
+	version := mainNetwork.Version()
+	if mainNetworkSnapshot == nil || mainNetworkSnapshotVersion != version {
+		mainNetworkSnapshot = mainNetwork.CopyNetwork()
+		mainNetworkSnapshotVersion = version
+	}
+	return mainNetworkSnapshot
+}
+
+// GetMainNetworkSnapshot returns a read only copy of the main network. The copy is shared between
+// the readers until the main network changes, while an update is running it shows the network
+// before the update.
+func GetMainNetworkSnapshot() *Network {
+	snapshot, _, _ := mainNetworkSnapshotOf()
+	return snapshot
+}
+
+// mainNetworkSnapshotOf returns the snapshot of the main network with the network and the
+// version it was copied from
+func mainNetworkSnapshotOf() (*Network, *Network, uint64) {
+	mainNetworkLock.Lock()
+	defer mainNetworkLock.Unlock()
+	if mainNetworkBatches == 0 || mainNetworkSnapshot == nil {
+		updateSnapshot()
+	}
+	return mainNetworkSnapshot, mainNetwork, mainNetworkSnapshotVersion
+}
+
+// UpdateMainNetwork run update on the main network and send a single change notification at the
+// end. Updates can be nested, the notification is sent when the outer one completes.
+func UpdateMainNetwork(update func(network *Network) error) error {
+	mainNetworkLock.Lock()
+	updateSnapshot()
+	mainNetworkBatches++
+	network := mainNetwork
+	version := network.Version()
+	mainNetworkLock.Unlock()
+
+	err := update(network)
+
+	mainNetworkLock.Lock()
+	mainNetworkBatches--
+	if network.Version() != version {
+		mainNetworkPending = true
+	}
+	notify := mainNetworkBatches == 0 && mainNetworkPending
+	if notify {
+		mainNetworkPending = false
+	}
+	if mainNetworkBatches == 0 {
+		mainNetworkIdle.Broadcast()
+	}
+	mainNetworkLock.Unlock()
+
+	if notify {
+		NotifyMainNetworkChanged()
+	}
+	return err
 }
 
 func AddMainNetworkChangedCallback(cb func()) {
+	mainNetworkLock.Lock()
+	defer mainNetworkLock.Unlock()
 	mainNetworkChancgedCallbacks = append(mainNetworkChancgedCallbacks, cb)
 }
 
+// NotifyMainNetworkChanged call the callbacks, while an update is running the notification is
+// delayed to its end.
 func NotifyMainNetworkChanged() {
-	for _, cb := range mainNetworkChancgedCallbacks {
+	mainNetworkLock.Lock()
+	if mainNetworkBatches > 0 {
+		mainNetworkPending = true
+		mainNetworkLock.Unlock()
+		return
+	}
+	// Device changes don't change the network version
+	mainNetworkSnapshot = nil
+	callbacks := mainNetworkChancgedCallbacks
+	mainNetworkLock.Unlock()
+
+	for _, cb := range callbacks {
 		cb()
 	}
 }
 
+// lastSeenResolution limit the updates of the last seen time of the nodes
+const lastSeenResolution = time.Minute
+
+// MarkNodeSeen update the last seen time of a node of the main network. It doesn't notify the
+// change, the time is saved in the graph file with the next change of the network.
+func MarkNodeSeen(id int64, seen time.Time) {
+	mainNetworkLock.Lock()
+	defer mainNetworkLock.Unlock()
+	if mainNetwork == nil {
+		return
+	}
+	dev, err := mainNetwork.GetNodeDevice(id)
+	if err != nil || seen.Sub(dev.Device().LastSeen()) < lastSeenResolution {
+		return
+	}
+	dev.Device().SetLastSeen(seen)
+	if mainNetworkBatches == 0 {
+		mainNetworkSnapshot = nil
+	}
+}
+
+// Network is safe for concurrent use, the iterators returned are copies taken under the lock
 type Network struct {
-	simple.WeightedDirectedGraph
+	lock          sync.RWMutex
+	directed      *simple.WeightedDirectedGraph
 	localDeviceId int64
+	links         map[linkKey]LinkAttributes
+	// version is incremented at every change of nodes, edges or links
+	version uint64
+	// tree caches the shortest paths from the local device, see shortestTree
+	treeLock sync.Mutex
+	tree     *pathTree
 }
 
 func (g *Network) LocalDeviceId() int64 {
@@ -122,100 +437,143 @@ func (g *Network) NodeIdExists(id int64) bool {
 }
 
 func (g *Network) ChangeEdgeWeight(fromId int64, toId int64, weightFrom float64, weightTo float64) {
-	fromNode, err := g.GetNodeDevice(fromId)
-	if err != nil {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.version++
+
+	fromNode := g.directed.Node(fromId)
+	if fromNode == nil {
 		fromNode = NewNodeDevice(fromId, false, "")
-		g.AddNode(fromNode)
+		g.directed.AddNode(fromNode)
 	}
 
-	toNode, err := g.GetNodeDevice(toId)
-	if err != nil {
+	toNode := g.directed.Node(toId)
+	if toNode == nil {
 		toNode = NewNodeDevice(toId, true, "")
-		g.AddNode(toNode)
+		g.directed.AddNode(toNode)
 	}
 
-	if !g.HasEdgeFromTo(fromId, toId) {
-		g.SetWeightedEdge(g.NewWeightedEdge(fromNode, toNode, weightTo))
+	if !g.directed.HasEdgeFromTo(fromId, toId) {
+		g.directed.SetWeightedEdge(g.directed.NewWeightedEdge(fromNode, toNode, weightTo))
 	} else {
-		edgeTo := g.WeightedEdge(fromId, toId).(simple.WeightedEdge)
+		edgeTo := g.directed.WeightedEdge(fromId, toId).(simple.WeightedEdge)
 		edgeTo.W = weightTo
-		g.SetWeightedEdge(edgeTo)
+		g.directed.SetWeightedEdge(edgeTo)
 	}
 }
 
-// GetPath returns the shortest path from the local device to the target device, along with the total path weight.
-//
-// Parameters:
-//   - to: The target Device to find a path to
-//
-// Returns:
-//   - []int64: Array of node IDs representing the path from local device to target
-//   - float64: Total weight/cost of the path
-//   - error: Error if no path exists or target device is not active
-//
-// The path returned will be the shortest path based on edge weights using Dijkstra's algorithm.
-// Returns an error if:
-// - The target device is not marked as in use/active
-// - No valid path exists between the local device and target
-// The weight returned is currently always 0 (not implemented).
+// RoutingDescendants returns the node and the in use nodes routed through it, ordered by hops from the local device
+func (g *Network) RoutingDescendants(id int64) ([]int64, error) {
+	if !g.NodeIdExists(id) {
+		return nil, fmt.Errorf("node 0x%06X not found in network graph", id)
+	}
 
-func (g *Network) GetPath(to NodeDevice) ([]int64, float64, error) {
-	if !to.Device().InUse() {
-		return nil, 0, fmt.Errorf("node is 0x%06X is not active", to.ID())
+	tree := g.shortestTree()
+	hops := map[int64]int{id: 0}
+	nodes := g.Nodes()
+	for nodes.Next() {
+		dev := nodes.Node().(NodeDevice)
+		if dev.ID() == id || !dev.Device().InUse() {
+			continue
+		}
+		route, _ := tree.route(g, dev.ID())
+		for _, nodeId := range route.Path {
+			if nodeId == id {
+				hops[dev.ID()] = len(route.Path)
+				break
+			}
+		}
 	}
-	allShortest := path.DijkstraAllPaths(g)
-	allBetween, weight := allShortest.AllBetween(g.localDeviceId, to.ID())
-	if len(allBetween) == 0 {
-		return nil, 0, fmt.Errorf("no path found between 0x%06X and 0x%06X", g.localDeviceId, to.ID())
+
+	ids := make([]int64, 0, len(hops))
+	for nodeId := range hops {
+		ids = append(ids, nodeId)
 	}
-	logrus.WithFields(logrus.Fields{"length": len(allBetween[0]), "weight": weight}).
-		Debug(fmt.Sprintf("Get path from 0x%06X to 0x%06X", g.localDeviceId, to.ID()))
+	sort.Slice(ids, func(i, j int) bool {
+		if hops[ids[i]] != hops[ids[j]] {
+			return hops[ids[i]] < hops[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	return ids, nil
+}
 
-	nodes := allBetween[0]
-	path := make([]int64, len(nodes))
-	for i, item := range nodes {
-		item := item.(NodeDevice)
-		path[i] = item.ID()
+// SaveToFile write the network to file, the previous versions are kept by the graph history
+func (g *Network) SaveToFile(filename string) error {
+	data, err := g.encodeGraph()
+	if err != nil {
+		return err
 	}
+	return saveGraphFile(filename, data)
+}
 
-	return path, 0, nil
+// encodeGraph returns the network in GraphML format
+func (g *Network) encodeGraph() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := g.writeGraph(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
 }
 
-func (g *Network) SaveToFile(filename string) error {
-	utils.BackupFile(filename, "backup")
-	return g.writeGraph(filename)
+// saveGraphFile replace filename with data, the file is never left half written
+func saveGraphFile(filename string, data []byte) error {
+	err := os.WriteFile(filename+".tmp", data, 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(filename+".tmp", filename)
 }
 
+// CopyNetwork returns a deep copy of the network, devices included
 func (g *Network) CopyNetwork() *Network {
-	network := Network{}
-	network.WeightedDirectedGraph = *simple.NewWeightedDirectedGraph(0, math.Inf(1))
-	network.localDeviceId = g.localDeviceId
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	network := &Network{localDeviceId: g.localDeviceId, directed: simple.NewWeightedDirectedGraph(0, math.Inf(1))}
 
-	nodes := g.Nodes()
+	nodes := g.directed.Nodes()
 	for nodes.Next() {
 		dev := nodes.Node().(NodeDevice)
-		network.AddNode(dev.CopyDevice())
+		network.directed.AddNode(dev.CopyDevice())
 	}
 
-	edges := g.Edges()
+	edges := g.directed.WeightedEdges()
 	for edges.Next() {
-		edge := edges.Edge().(simple.WeightedEdge)
-		network.SetWeightedEdge(g.NewWeightedEdge(edge.From(), edge.To(), edge.Weight()))
+		edge := edges.WeightedEdge()
+		from := network.directed.Node(edge.From().ID())
+		to := network.directed.Node(edge.To().ID())
+		network.directed.SetWeightedEdge(network.directed.NewWeightedEdge(from, to, edge.Weight()))
+	}
+
+	for key, attrs := range g.links {
+		network.setLinkAttributes(key, attrs)
 	}
 
-	return &network
+	return network
+}
+
+// InheritDeviceSettings copy the settings and the metadata of the nodes also present in other
+func (g *Network) InheritDeviceSettings(other *Network) {
+	nodes := g.Nodes()
+	for nodes.Next() {
+		dev := nodes.Node().(NodeDevice)
+		if otherDev, err := other.GetNodeDevice(dev.ID()); err == nil && otherDev.Device() != dev.Device() {
+			otherCopy := otherDev.CopyDevice()
+			dev.Device().lock.Lock()
+			dev.Device().copyMetadata(otherCopy.Device())
+			dev.Device().lock.Unlock()
+		}
+	}
 }
 
 func NewNetwork(localDeviceId int64) *Network {
-	network := Network{localDeviceId: localDeviceId}
-	network.WeightedDirectedGraph = *simple.NewWeightedDirectedGraph(0, math.Inf(1))
+	network := &Network{localDeviceId: localDeviceId, directed: simple.NewWeightedDirectedGraph(0, math.Inf(1))}
 	network.AddNode(NewNodeDevice(localDeviceId, true, "local"))
-	return &network
+	return network
 }
 
 func NewNeworkFromFile(filename string, localDeviceId int64) (*Network, error) {
-	network := Network{}
-	network.WeightedDirectedGraph = *simple.NewWeightedDirectedGraph(0, math.Inf(1))
+	network := &Network{directed: simple.NewWeightedDirectedGraph(0, math.Inf(1))}
 	err := network.readGraph(filename)
 	if err != nil {
 		return nil, err
@@ -226,5 +584,5 @@ func NewNeworkFromFile(filename string, localDeviceId int64) (*Network, error) {
 		network.AddNode(NewNodeDevice(localDeviceId, true, "local"))
 		logger.WithField("device", utils.FmtNodeId(localDeviceId)).Warn("Local device not found in graph, adding it. Will be an isolated node")
 	}
-	return &network, nil
+	return network, nil
 }
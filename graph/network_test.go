@@ -0,0 +1,206 @@
+package graph
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newChainNetwork returns a network where node i is linked to node i+1, node 1 is the local device
+func newChainNetwork(size int) *Network {
+	network := NewNetwork(1)
+	for id := int64(2); id <= int64(size); id++ {
+		network.AddNode(NewNodeDevice(id, true, ""))
+		network.ChangeEdgeWeight(id-1, id, 0.1, 0.1)
+		network.ChangeEdgeWeight(id, id-1, 0.1, 0.1)
+	}
+	return network
+}
+
+var notifications atomic.Int64
+var notificationsOnce sync.Once
+
+// countNotifications register, once for all the tests, a callback counting the main network notifications
+func countNotifications() {
+	notificationsOnce.Do(func() {
+		AddMainNetworkChangedCallback(func() { notifications.Add(1) })
+	})
+}
+
+func TestNetworkConcurrentAccess(t *testing.T) {
+	const size = 50
+	const workers = 4
+	const rounds = 200
+	network := newChainNetwork(size)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(4)
+		// Each writer use its own range of ids, gonum panics when a node id is added twice
+		base := int64(1000 * (w + 1))
+		go func() {
+			defer wg.Done()
+			for i := int64(0); i < rounds; i++ {
+				network.AddNode(NewNodeDevice(base+i, true, ""))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := int64(0); i < rounds; i++ {
+				from := NewNodeDevice(int64(size)-i%int64(size-1), true, "")
+				to := NewNodeDevice(base+rounds+i, true, "")
+				network.SetWeightedEdge(network.NewWeightedEdge(from, to, 0.5))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := int64(0); i < rounds; i++ {
+				network.RemoveNode(base + i)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				if to, err := network.GetNodeDevice(int64(2 + i%(size-1))); err == nil {
+					_, _, _ = network.GetPath(to)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The chain is never changed by the workers
+	to, err := network.GetNodeDevice(size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, _, err := network.GetPath(to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(path) != size {
+		t.Fatalf("path to the last node has %d hops, expected %d", len(path), size)
+	}
+}
+
+func TestUpdateMainNetworkNesting(t *testing.T) {
+	countNotifications()
+	SetMainNetwork(newChainNetwork(5))
+
+	before := notifications.Load()
+	err := UpdateMainNetwork(func(network *Network) error {
+		network.AddNode(NewNodeDevice(10, true, ""))
+		NotifyMainNetworkChanged()
+		return UpdateMainNetwork(func(network *Network) error {
+			network.ChangeEdgeWeight(5, 10, 0.2, 0.2)
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := notifications.Load() - before; got != 1 {
+		t.Fatalf("nested updates sent %d notifications, expected 1", got)
+	}
+
+	before = notifications.Load()
+	_ = UpdateMainNetwork(func(network *Network) error { return nil })
+	if got := notifications.Load() - before; got != 0 {
+		t.Fatalf("update without changes sent %d notifications, expected 0", got)
+	}
+}
+
+func TestMainNetworkSnapshot(t *testing.T) {
+	SetMainNetwork(newChainNetwork(5))
+
+	snapshot := GetMainNetworkSnapshot()
+	if snapshot == GetMainNetwork() {
+		t.Fatal("snapshot is the main network itself")
+	}
+	if GetMainNetworkSnapshot() != snapshot {
+		t.Fatal("snapshot not shared while the network is unchanged")
+	}
+
+	_ = UpdateMainNetwork(func(network *Network) error {
+		network.AddNode(NewNodeDevice(20, true, ""))
+		if GetMainNetworkSnapshot().NodeIdExists(20) {
+			t.Error("snapshot shows a change while the update is running")
+		}
+		return nil
+	})
+
+	updated := GetMainNetworkSnapshot()
+	if updated == snapshot || !updated.NodeIdExists(20) {
+		t.Fatal("snapshot not refreshed after the update")
+	}
+	if snapshot.NodeIdExists(20) {
+		t.Fatal("previous snapshot changed by the update")
+	}
+}
+
+func TestMainNetworkConcurrentUpdates(t *testing.T) {
+	SetMainNetwork(newChainNetwork(10))
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(2)
+		base := int64(100 * (w + 1))
+		go func() {
+			defer wg.Done()
+			for i := int64(0); i < 50; i++ {
+				_ = UpdateMainNetwork(func(network *Network) error {
+					network.AddNode(NewNodeDevice(base+i, true, ""))
+					network.ChangeEdgeWeight(10, base+i, 0.3, 0.3)
+					return nil
+				})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				snapshot := GetMainNetworkSnapshot()
+				if to, err := snapshot.GetNodeDevice(10); err == nil {
+					_, _, _ = snapshot.GetPath(to)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if nodes := GetMainNetworkSnapshot().Nodes().Len(); nodes != 10+4*50 {
+		t.Fatalf("main network has %d nodes, expected %d", nodes, 10+4*50)
+	}
+}
+
+func TestSetMainNetworkWaitsUpdates(t *testing.T) {
+	SetMainNetwork(newChainNetwork(5))
+
+	started, release, replaced := make(chan struct{}), make(chan struct{}), make(chan struct{})
+	go func() {
+		_ = UpdateMainNetwork(func(network *Network) error {
+			close(started)
+			<-release
+			network.AddNode(NewNodeDevice(30, true, ""))
+			return nil
+		})
+	}()
+	<-started
+
+	replacement := newChainNetwork(3)
+	go func() {
+		SetMainNetwork(replacement)
+		close(replaced)
+	}()
+	select {
+	case <-replaced:
+		t.Fatal("main network replaced while an update was running")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	<-replaced
+	if GetMainNetwork() != replacement {
+		t.Fatal("main network not replaced after the update")
+	}
+}
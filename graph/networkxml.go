@@ -2,10 +2,13 @@ package graph
 
 import (
 	"fmt"
+	"io"
 	"math"
 	"os"
 	"reflect"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"leguru.net/m/v2/graphml"
@@ -18,26 +21,42 @@ func (g *Network) readGraph(filename string) error {
 	if err != nil {
 		return err
 	}
+	defer xmlFile.Close()
+
+	_, err = g.readGraphML(xmlFile)
+	return err
+}
 
+// readGraphML returns the local device declared in the file, 0 if not present
+func (g *Network) readGraphML(r io.Reader) (int64, error) {
+	var localDeviceId int64
 	gml := graphml.NewGraphML("meshmesh network")
-	err = gml.Decode(xmlFile)
+	err := gml.Decode(r)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	for i, gr := range gml.Graphs {
 		if i == 0 {
 			logger.Log().WithFields(logrus.Fields{"description": gr.Description}).Info("found graph")
+			if attrs, err := gr.GetAttributes(); err == nil {
+				if local := attributeString(attrs, "localdevice"); local != "" {
+					localDeviceId, err = utils.ParseNodeId(local)
+					if err != nil {
+						return 0, err
+					}
+				}
+			}
 			for _, n := range gr.Nodes {
 				descr := n.Description
 				attrs, err := n.GetAttributes()
 				if err != nil {
-					return err
+					return 0, err
 				}
 
 				id, err := utils.ParseNodeId(n.ID)
 				if err != nil {
-					return err
+					return 0, err
 				}
 
 				if len(descr) == 0 {
@@ -46,28 +65,58 @@ func (g *Network) readGraph(filename string) error {
 
 				inuse, ok := attrs["inuse"].(bool)
 				if !ok {
-					inuse, err = strconv.ParseBool(attrs["inuse"].(string))
+					inuse, err = strconv.ParseBool(attributeString(attrs, "inuse"))
 					if err != nil {
-						return err
+						return 0, err
 					}
 				}
 
-				g.AddNode(NewNodeDevice(id, inuse, descr))
+				node := NewNodeDevice(id, inuse, descr)
+				node.Device().SetDiscovered(attributeBool(attrs, "discovered"))
+				node.Device().SetChip(attributeString(attrs, "chip"))
+				node.Device().SetWeightProfile(attributeString(attrs, "weightprofile"))
+				node.Device().SetFirmware(attributeString(attrs, "firmware"))
+				if channel, ok := attributeInt(attrs, "channel"); ok {
+					txPower, _ := attributeInt(attrs, "txpower")
+					groups, _ := attributeInt(attrs, "groups")
+					binded, _ := attributeInt(attrs, "bindedserver")
+					flags, _ := attributeInt(attrs, "nodeflags")
+					node.Device().SetConfig(NodeConfig{Channel: uint8(channel), TxPower: uint8(txPower), Groups: uint32(groups), BindedServer: uint32(binded), Flags: uint8(flags)})
+				}
+				if lastSeen := attributeString(attrs, "lastseen"); lastSeen != "" {
+					seen, err := time.Parse(time.RFC3339, lastSeen)
+					if err != nil {
+						return 0, err
+					}
+					node.Device().SetLastSeen(seen)
+				}
+				node.Device().SetLocation(attributeString(attrs, "location"))
+				node.Device().SetNotes(attributeString(attrs, "notes"))
+				if labels := attributeString(attrs, "labels"); labels != "" {
+					node.Device().SetLabels(strings.Split(labels, ","))
+				}
+				if g.NodeIdExists(id) {
+					return 0, fmt.Errorf("duplicated node %s", n.ID)
+				}
+				g.AddNode(node)
 			}
 
 			for _, e := range gr.Edges {
 				attrs, err := e.GetAttributes()
 				if err != nil {
-					return err
+					return 0, err
 				}
 
 				src, err := utils.ParseNodeId(e.Source)
 				if err != nil {
-					return err
+					return 0, err
 				}
 				dst, err := utils.ParseNodeId(e.Target)
 				if err != nil {
-					return err
+					return 0, err
+				}
+				if !g.NodeIdExists(src) || !g.NodeIdExists(dst) {
+					return 0, fmt.Errorf("edge %s %s references an unknown node", e.Source, e.Target)
 				}
 
 				var weight float64
@@ -77,50 +126,98 @@ func (g *Network) readGraph(filename string) error {
 				} else {
 					weight, ok = attrs["weight"].(float64)
 					if !ok {
-						weight, err = strconv.ParseFloat(attrs["weight"].(string), 32)
+						weight, err = strconv.ParseFloat(attributeString(attrs, "weight"), 32)
 						if err != nil {
-							return err
+							return 0, err
 						}
 					}
 				}
 
 				g.SetWeightedEdge(g.NewWeightedEdge(g.Node(src), g.Node(dst), weight))
+				g.SetLinkAttributes(src, dst, LinkAttributes{
+					Pinned:    attributeBool(attrs, "pinned"),
+					Excluded:  attributeBool(attrs, "excluded"),
+					Preferred: attributeBool(attrs, "preferred"),
+				})
 			}
 		}
 	}
-	return nil
+	return localDeviceId, nil
 }
 
-func (g *Network) writeGraph(filename string) error {
+func (g *Network) writeGraph(w io.Writer) error {
 	gml := graphml.NewGraphML("meshmesh network")
 
+	gml.RegisterKey(graphml.KeyForGraph, "localdevice", "the id of the local coordinator", reflect.String, "")
 	gml.RegisterKey(graphml.KeyForNode, "inuse", "is node in use", reflect.Bool, true)
-	gml.RegisterKey(graphml.KeyForNode, "discover", "state variable for discovery", reflect.Bool, false)
-	gml.RegisterKey(graphml.KeyForNode, "buggy", "state variable fr functional status", reflect.Bool, false)
+	gml.RegisterKey(graphml.KeyForNode, "discovered", "state variable for discovery", reflect.Bool, false)
 	gml.RegisterKey(graphml.KeyForNode, "firmware", "the node firmware revision", reflect.String, "")
+	gml.RegisterKey(graphml.KeyForNode, "chip", "the node chip type", reflect.String, "")
+	gml.RegisterKey(graphml.KeyForNode, "weightprofile", "the rssi weight profile of the node", reflect.String, "")
+	gml.RegisterKey(graphml.KeyForNode, "channel", "the node wifi channel", reflect.Int, nil)
+	gml.RegisterKey(graphml.KeyForNode, "txpower", "the node transmission power", reflect.Int, nil)
+	gml.RegisterKey(graphml.KeyForNode, "groups", "the node groups mask", reflect.Int64, nil)
+	gml.RegisterKey(graphml.KeyForNode, "bindedserver", "the node binded server", reflect.Int64, nil)
+	gml.RegisterKey(graphml.KeyForNode, "nodeflags", "the node configuration flags", reflect.Int, nil)
+	gml.RegisterKey(graphml.KeyForNode, "lastseen", "last reply of the node to the hub", reflect.String, "")
+	gml.RegisterKey(graphml.KeyForNode, "location", "the room or location of the node", reflect.String, "")
+	gml.RegisterKey(graphml.KeyForNode, "notes", "free notes about the node", reflect.String, "")
+	gml.RegisterKey(graphml.KeyForNode, "labels", "comma separated user labels", reflect.String, "")
 	gml.RegisterKey(graphml.KeyForEdge, "weight", "the node firmware revision", reflect.Float32, 0.0)
 	gml.RegisterKey(graphml.KeyForEdge, "weight2", "the node firmware revision", reflect.Float32, 0.0)
+	gml.RegisterKey(graphml.KeyForEdge, "pinned", "weight not changed by discovery", reflect.Bool, false)
+	gml.RegisterKey(graphml.KeyForEdge, "excluded", "link never used for routing", reflect.Bool, false)
+	gml.RegisterKey(graphml.KeyForEdge, "preferred", "link favoured for routing", reflect.Bool, false)
 
-	gr, err := gml.AddGraph("the graph", graphml.EdgeDirectionDirected, map[string]interface{}{})
+	gr, err := gml.AddGraph("the graph", graphml.EdgeDirectionDirected, map[string]interface{}{"localdevice": utils.FmtNodeId(g.localDeviceId)})
 	if err != nil {
 		return err
 	}
 
-	nodes := g.Nodes()
-	for nodes.Next() {
-		node := nodes.Node().(NodeDevice)
+	// Nodes and edges are sorted so the same network always gives the same file
+	for _, id := range sortedNodeIds(g) {
+		node, err := g.GetNodeDevice(id)
+		if err != nil {
+			continue
+		}
 
 		attributes := map[string]interface{}{
 			"inuse":      node.Device().InUse(),
 			"discovered": node.Device().Discovered(),
 		}
+		if chip := node.Device().Chip(); chip != "" {
+			attributes["chip"] = chip
+		}
+		if profile := node.Device().WeightProfile(); profile != "" {
+			attributes["weightprofile"] = profile
+		}
+		if firmware := node.Device().Firmware(); firmware != "" {
+			attributes["firmware"] = firmware
+		}
+		if config, ok := node.Device().Config(); ok {
+			attributes["channel"] = int(config.Channel)
+			attributes["txpower"] = int(config.TxPower)
+			attributes["groups"] = int64(config.Groups)
+			attributes["bindedserver"] = int64(config.BindedServer)
+			attributes["nodeflags"] = int(config.Flags)
+		}
+		if lastSeen := node.Device().LastSeen(); !lastSeen.IsZero() {
+			attributes["lastseen"] = lastSeen.UTC().Format(time.RFC3339)
+		}
+		if location := node.Device().Location(); location != "" {
+			attributes["location"] = location
+		}
+		if notes := node.Device().Notes(); notes != "" {
+			attributes["notes"] = notes
+		}
+		if labels := node.Device().Labels(); len(labels) > 0 {
+			attributes["labels"] = strings.Join(labels, ",")
+		}
 
 		gr.AddNode(attributes, utils.FmtNodeId(node.ID()), node.Device().Tag())
 	}
 
-	edges := g.WeightedEdges()
-	for edges.Next() {
-		edge := edges.WeightedEdge()
+	for _, edge := range sortedEdges(g) {
 		from := edge.From().(NodeDevice)
 		to := edge.To().(NodeDevice)
 
@@ -130,17 +227,15 @@ func (g *Network) writeGraph(filename string) error {
 		attributes := map[string]interface{}{
 			"weight": math.Floor(edge.Weight()*100) / 100,
 		}
+		if link := g.LinkAttributes(from.ID(), to.ID()); !link.isZero() {
+			attributes["pinned"] = link.Pinned
+			attributes["excluded"] = link.Excluded
+			attributes["preferred"] = link.Preferred
+		}
 
 		description := fmt.Sprintf("from %s:[%s] to %s:[%s]", from.Device().Tag(), utils.FmtNodeId(from.ID()), to.Device().Tag(), utils.FmtNodeId(to.ID()))
 		gr.AddEdge(n1, n2, attributes, graphml.EdgeDirectionDefault, description)
 	}
 
-	xmlFile, err := os.Create(filename)
-	if err != nil {
-		return err
-	}
-
-	defer xmlFile.Close()
-	err = gml.Encode(xmlFile, true)
-	return err
+	return gml.Encode(w, true)
 }
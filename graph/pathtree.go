@@ -0,0 +1,98 @@
+package graph
+
+import (
+	"fmt"
+	"sync"
+
+	"gonum.org/v1/gonum/graph/path"
+)
+
+// pathTree is the shortest path tree from the local device, computed once per network version
+type pathTree struct {
+	version uint64
+	// routes are indexed by the destination node, the local device included
+	routes map[int64]Route
+	// constrained keep the routes of the nodes whose shortest path doesn't fit the route
+	// settings, they are searched when first needed
+	lock        sync.Mutex
+	maxHops     int
+	metric      RouteMetric
+	constrained map[int64]Route
+	graph       *routeGraph
+}
+
+// shortestTree returns the path tree of the current network version, computing it if needed
+func (g *Network) shortestTree() *pathTree {
+	g.treeLock.Lock()
+	defer g.treeLock.Unlock()
+	if g.tree != nil && g.tree.version == g.Version() {
+		return g.tree
+	}
+
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	tree := &pathTree{version: g.version, routes: make(map[int64]Route)}
+	if local := g.directed.Node(g.localDeviceId); local != nil {
+		shortest := path.DijkstraFrom(local, g.routing())
+		nodes := g.directed.Nodes()
+		for nodes.Next() {
+			nodes, _ := shortest.To(nodes.Node().ID())
+			if len(nodes) == 0 {
+				continue
+			}
+			route := Route{Path: make([]int64, len(nodes))}
+			for i, node := range nodes {
+				route.Path[i] = node.ID()
+				if i > 0 {
+					w, _ := g.directed.Weight(route.Path[i-1], route.Path[i])
+					route.Weight += w
+					route.Bottleneck = max(route.Bottleneck, w)
+				}
+			}
+			tree.routes[route.Path[len(route.Path)-1]] = route
+		}
+	}
+	g.tree = tree
+	return tree
+}
+
+// route returns the route to id that respect the route settings
+func (t *pathTree) route(g *Network, id int64) (Route, bool) {
+	maxHops, metric := GetRouteSettings()
+	route, ok := t.routes[id]
+	if !ok || (metric == RouteMetricWeight && route.Hops() <= maxHops) {
+		return route, ok
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.maxHops != maxHops || t.metric != metric || t.constrained == nil {
+		t.maxHops, t.metric, t.constrained = maxHops, metric, make(map[int64]Route)
+	}
+	if route, ok := t.constrained[id]; ok {
+		return route, len(route.Path) > 0
+	}
+	if t.graph == nil {
+		t.graph = g.routeGraph()
+	}
+	route = Route{}
+	if path := t.graph.best(t.graph.local, id, maxHops, metric, routeBans{}); path != nil {
+		route = t.graph.route(path)
+	}
+	t.constrained[id] = route
+	return route, len(route.Path) > 0
+}
+
+// GetPath returns the route from the local device to the target device and the sum of the
+// weights of its links. The route follows the hop limit and the metric of the route settings,
+// the routes are computed once per network version.
+func (g *Network) GetPath(to NodeDevice) ([]int64, float64, error) {
+	if !to.Device().InUse() {
+		return nil, 0, fmt.Errorf("node is 0x%06X is not active", to.ID())
+	}
+	route, ok := g.shortestTree().route(g, to.ID())
+	if !ok {
+		return nil, 0, fmt.Errorf("no path found between 0x%06X and 0x%06X", g.localDeviceId, to.ID())
+	}
+	return append([]int64(nil), route.Path...), route.Weight, nil
+}
@@ -0,0 +1,93 @@
+package graph
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"gonum.org/v1/gonum/graph/path"
+)
+
+var pathBenchmarkSizes = []int{50, 200, 1000}
+
+// newMeshNetwork returns a connected network of size nodes, each node is linked in both
+// directions to up to four random nodes added before it. The same size gives the same network.
+func newMeshNetwork(size int) *Network {
+	rnd := rand.New(rand.NewSource(int64(size)))
+	network := NewNetwork(1)
+	for id := int64(2); id <= int64(size); id++ {
+		network.AddNode(NewNodeDevice(id, true, ""))
+		for i := 0; i < 4; i++ {
+			peer := 1 + rnd.Int63n(id-1)
+			weight := math.Round(rnd.Float64()*100) / 100
+			network.ChangeEdgeWeight(id, peer, weight, weight)
+			network.ChangeEdgeWeight(peer, id, weight, weight)
+		}
+	}
+	return network
+}
+
+func TestGetPathMatchDijkstra(t *testing.T) {
+	network := newMeshNetwork(200)
+	allShortest := path.DijkstraAllPaths(network.routing())
+	for id := int64(2); id <= 200; id++ {
+		to, _ := network.GetNodeDevice(id)
+		_, weight, err := network.GetPath(to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := allShortest.Weight(1, id); math.Abs(weight-expected) > 1e-9 {
+			t.Fatalf("path to %d weight %f, expected %f", id, weight, expected)
+		}
+	}
+}
+
+// BenchmarkGetPath read the path of every node of an unchanged network, the tree is computed once
+func BenchmarkGetPath(b *testing.B) {
+	for _, size := range pathBenchmarkSizes {
+		b.Run(fmt.Sprintf("nodes=%d", size), func(b *testing.B) {
+			network := newMeshNetwork(size)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				to, _ := network.GetNodeDevice(int64(2 + i%(size-1)))
+				if _, _, err := network.GetPath(to); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkGetPathChanged change a link before every path, the tree is computed at every call
+func BenchmarkGetPathChanged(b *testing.B) {
+	for _, size := range pathBenchmarkSizes {
+		b.Run(fmt.Sprintf("nodes=%d", size), func(b *testing.B) {
+			network := newMeshNetwork(size)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				network.ChangeEdgeWeight(1, 2, 0.5, float64(i%2)/10)
+				to, _ := network.GetNodeDevice(int64(2 + i%(size-1)))
+				if _, _, err := network.GetPath(to); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkDijkstraAllPaths is the baseline, the all pairs search done for every path before the tree
+func BenchmarkDijkstraAllPaths(b *testing.B) {
+	for _, size := range pathBenchmarkSizes {
+		b.Run(fmt.Sprintf("nodes=%d", size), func(b *testing.B) {
+			network := newMeshNetwork(size)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				allShortest := path.DijkstraAllPaths(network.routing())
+				if paths, _ := allShortest.AllBetween(1, int64(2+i%(size-1))); len(paths) == 0 {
+					b.Fatal("no path found")
+				}
+			}
+		})
+	}
+}
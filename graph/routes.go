@@ -0,0 +1,328 @@
+package graph
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// FirmwareMaxHops is the longest route the firmware can follow, the path length of the
+// multipath and connected path frames is an 8 bit field.
+const FirmwareMaxHops = 255
+
+type RouteMetric string
+
+const (
+	// RouteMetricWeight minimise the sum of the link weights
+	RouteMetricWeight RouteMetric = "weight"
+	// RouteMetricBottleneck minimise the worst link of the route, the sum breaks the ties
+	RouteMetricBottleneck RouteMetric = "bottleneck"
+)
+
+func ParseRouteMetric(metric string) (RouteMetric, error) {
+	switch RouteMetric(metric) {
+	case "", RouteMetricWeight:
+		return RouteMetricWeight, nil
+	case RouteMetricBottleneck:
+		return RouteMetricBottleneck, nil
+	}
+	return "", fmt.Errorf("unknown route metric %s", metric)
+}
+
+// RouteOptions select the routes returned by Routes, the zero value use the route settings
+type RouteOptions struct {
+	// K is the number of alternative routes, at least one
+	K int
+	// MaxHops limit the number of links of the routes, 0 to use the route settings
+	MaxHops int
+	// Metric used to sort the routes, empty to use the route settings
+	Metric RouteMetric
+}
+
+// Route is a loopless path from the local device
+type Route struct {
+	Path []int64
+	// Weight is the sum of the weights of the links
+	Weight float64
+	// Bottleneck is the weight of the worst link
+	Bottleneck float64
+}
+
+func (r Route) Hops() int {
+	return len(r.Path) - 1
+}
+
+var routeSettingsLock sync.Mutex
+var routeMaxHops = FirmwareMaxHops
+var routeMetric = RouteMetricWeight
+
+// SetRouteSettings change the hop limit and the metric of the routes used to reach the nodes
+func SetRouteSettings(maxHops int, metric RouteMetric) error {
+	if maxHops <= 0 || maxHops > FirmwareMaxHops {
+		maxHops = FirmwareMaxHops
+	}
+	metric, err := ParseRouteMetric(string(metric))
+	if err != nil {
+		return err
+	}
+	routeSettingsLock.Lock()
+	defer routeSettingsLock.Unlock()
+	routeMaxHops = maxHops
+	routeMetric = metric
+	return nil
+}
+
+// GetRouteSettings returns the hop limit and the metric of the routes used to reach the nodes
+func GetRouteSettings() (int, RouteMetric) {
+	routeSettingsLock.Lock()
+	defer routeSettingsLock.Unlock()
+	return routeMaxHops, routeMetric
+}
+
+func (o RouteOptions) withDefaults() RouteOptions {
+	maxHops, metric := GetRouteSettings()
+	if o.K < 1 {
+		o.K = 1
+	}
+	if o.MaxHops <= 0 || o.MaxHops > FirmwareMaxHops {
+		o.MaxHops = maxHops
+	}
+	if o.Metric == "" {
+		o.Metric = metric
+	}
+	return o
+}
+
+type routeLink struct {
+	to int64
+	// cost is the weight used to search the routes, weight is the weight of the link
+	cost   float64
+	weight float64
+}
+
+// routeGraph is a copy of the routing view of the network used by the route searches
+type routeGraph struct {
+	local int64
+	links map[int64][]routeLink
+}
+
+func (g *Network) routeGraph() *routeGraph {
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	r := &routeGraph{local: g.localDeviceId, links: make(map[int64][]routeLink)}
+	edges := g.routing().WeightedEdges()
+	for edges.Next() {
+		edge := edges.WeightedEdge()
+		from, to := edge.From().ID(), edge.To().ID()
+		weight, _ := g.directed.Weight(from, to)
+		r.links[from] = append(r.links[from], routeLink{to: to, cost: edge.Weight(), weight: weight})
+	}
+	for _, links := range r.links {
+		sort.Slice(links, func(i, j int) bool { return links[i].to < links[j].to })
+	}
+	return r
+}
+
+func (r *routeGraph) link(from int64, to int64) (routeLink, bool) {
+	for _, link := range r.links[from] {
+		if link.to == to {
+			return link, true
+		}
+	}
+	return routeLink{}, false
+}
+
+// routeCost is the cost of a route for a metric, lower is better
+type routeCost struct {
+	first  float64
+	second float64
+}
+
+func (c routeCost) less(other routeCost) bool {
+	if c.first != other.first {
+		return c.first < other.first
+	}
+	return c.second < other.second
+}
+
+func (r *routeGraph) cost(path []int64, metric RouteMetric) routeCost {
+	sum, worst := 0.0, 0.0
+	for i := 1; i < len(path); i++ {
+		link, _ := r.link(path[i-1], path[i])
+		sum += link.cost
+		worst = math.Max(worst, link.cost)
+	}
+	if metric == RouteMetricBottleneck {
+		return routeCost{worst, sum}
+	}
+	return routeCost{sum, 0}
+}
+
+func (r *routeGraph) route(path []int64) Route {
+	route := Route{Path: path}
+	for i := 1; i < len(path); i++ {
+		link, _ := r.link(path[i-1], path[i])
+		route.Weight += link.weight
+		route.Bottleneck = math.Max(route.Bottleneck, link.weight)
+	}
+	return route
+}
+
+// routeBans are the nodes and links hidden to a search
+type routeBans struct {
+	nodes map[int64]bool
+	links map[[2]int64]bool
+}
+
+// shortest returns the path from source to target with the lowest sum of costs and at most
+// maxHops links, using only the links with cost up to maxCost.
+func (r *routeGraph) shortest(source int64, target int64, maxHops int, maxCost float64, bans routeBans) []int64 {
+	type hop struct {
+		cost float64
+		prev int64
+	}
+	// best[h][n] is the cheapest way to reach n with h links, it's kept only if cheaper than
+	// all the ways to reach n with fewer links.
+	best := []map[int64]hop{{source: {0, -1}}}
+	lowest := map[int64]float64{source: 0}
+	for h := 1; h <= maxHops && len(best[h-1]) > 0; h++ {
+		next := make(map[int64]hop)
+		for from, reached := range best[h-1] {
+			if from == target {
+				continue
+			}
+			for _, link := range r.links[from] {
+				if link.cost > maxCost || bans.nodes[link.to] || bans.links[[2]int64{from, link.to}] || link.to == source {
+					continue
+				}
+				cost := reached.cost + link.cost
+				if low, ok := lowest[link.to]; ok && cost >= low {
+					continue
+				}
+				if current, ok := next[link.to]; !ok || cost < current.cost || (cost == current.cost && from < current.prev) {
+					next[link.to] = hop{cost, from}
+				}
+			}
+		}
+		for id, reached := range next {
+			lowest[id] = reached.cost
+		}
+		best = append(best, next)
+	}
+
+	// The last layer reaching the target has the cheapest path
+	found := -1
+	for h := range best {
+		if _, ok := best[h][target]; ok {
+			found = h
+		}
+	}
+	if found < 0 {
+		return nil
+	}
+	path := make([]int64, found+1)
+	node := target
+	for h := found; h >= 0; h-- {
+		path[h] = node
+		node = best[h][node].prev
+	}
+	// Zero weight loops could make a path revisit a node
+	seen := make(map[int64]bool, len(path))
+	for _, id := range path {
+		if seen[id] {
+			return nil
+		}
+		seen[id] = true
+	}
+	return path
+}
+
+// best returns the best path from source to target for the metric
+func (r *routeGraph) best(source int64, target int64, maxHops int, metric RouteMetric, bans routeBans) []int64 {
+	if metric != RouteMetricBottleneck {
+		return r.shortest(source, target, maxHops, math.Inf(1), bans)
+	}
+	// Search the lowest link cost that still connects the nodes, then the shortest path using
+	// only the links up to that cost.
+	costs := make([]float64, 0)
+	for _, links := range r.links {
+		for _, link := range links {
+			costs = append(costs, link.cost)
+		}
+	}
+	sort.Float64s(costs)
+	costs = slices.Compact(costs)
+	i := sort.Search(len(costs), func(i int) bool {
+		return r.shortest(source, target, maxHops, costs[i], bans) != nil
+	})
+	if i == len(costs) {
+		if source == target {
+			return []int64{source}
+		}
+		return nil
+	}
+	return r.shortest(source, target, maxHops, costs[i], bans)
+}
+
+// kBest returns up to k loopless paths from the local device to target using Yen's algorithm
+func (r *routeGraph) kBest(target int64, opts RouteOptions) [][]int64 {
+	first := r.best(r.local, target, opts.MaxHops, opts.Metric, routeBans{})
+	if first == nil {
+		return nil
+	}
+	found := [][]int64{first}
+	candidates := make([][]int64, 0)
+	for len(found) < opts.K {
+		prev := found[len(found)-1]
+		for i := 0; i < len(prev)-1; i++ {
+			root := prev[:i+1]
+			bans := routeBans{nodes: make(map[int64]bool), links: make(map[[2]int64]bool)}
+			for _, path := range found {
+				if len(path) > i+1 && slices.Equal(path[:i+1], root) {
+					bans.links[[2]int64{path[i], path[i+1]}] = true
+				}
+			}
+			for _, id := range root[:i] {
+				bans.nodes[id] = true
+			}
+			spur := r.best(prev[i], target, opts.MaxHops-i, opts.Metric, bans)
+			if spur == nil {
+				continue
+			}
+			path := append(slices.Clone(root[:i]), spur...)
+			if !slices.ContainsFunc(found, func(p []int64) bool { return slices.Equal(p, path) }) &&
+				!slices.ContainsFunc(candidates, func(p []int64) bool { return slices.Equal(p, path) }) {
+				candidates = append(candidates, path)
+			}
+		}
+		if len(candidates) == 0 {
+			break
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return r.cost(candidates[i], opts.Metric).less(r.cost(candidates[j], opts.Metric))
+		})
+		found = append(found, candidates[0])
+		candidates = candidates[1:]
+	}
+	return found
+}
+
+// Routes returns the alternative routes from the local device to the node, best first
+func (g *Network) Routes(to int64, opts RouteOptions) ([]Route, error) {
+	if !g.NodeIdExists(to) {
+		return nil, fmt.Errorf("node 0x%06X not found in network graph", to)
+	}
+	opts = opts.withDefaults()
+	r := g.routeGraph()
+	paths := r.kBest(to, opts)
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no path found between 0x%06X and 0x%06X", g.localDeviceId, to)
+	}
+	routes := make([]Route, len(paths))
+	for i, path := range paths {
+		routes[i] = r.route(path)
+	}
+	return routes, nil
+}
@@ -0,0 +1,141 @@
+package graph
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+// newTwoRoutesNetwork returns a network where node 4 is reached through node 2, the lighter
+// route with the worst link, or through node 3.
+func newTwoRoutesNetwork() *Network {
+	network := NewNetwork(1)
+	for id := int64(2); id <= 4; id++ {
+		network.AddNode(NewNodeDevice(id, true, ""))
+	}
+	network.ChangeEdgeWeight(1, 2, 0.1, 0.1)
+	network.ChangeEdgeWeight(2, 4, 0.6, 0.6)
+	network.ChangeEdgeWeight(1, 3, 0.4, 0.4)
+	network.ChangeEdgeWeight(3, 4, 0.4, 0.4)
+	return network
+}
+
+func TestRoutesHopLimit(t *testing.T) {
+	network := newChainNetwork(5)
+	network.ChangeEdgeWeight(1, 5, 0.9, 0.9)
+
+	tests := []struct {
+		maxHops int
+		path    []int64
+	}{
+		{0, []int64{1, 2, 3, 4, 5}},
+		{4, []int64{1, 2, 3, 4, 5}},
+		{3, []int64{1, 5}},
+		{1, []int64{1, 5}},
+	}
+	for _, tt := range tests {
+		routes, err := network.Routes(5, RouteOptions{MaxHops: tt.maxHops})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(routes[0].Path, tt.path) {
+			t.Fatalf("max hops %d: route %v, want %v", tt.maxHops, routes[0].Path, tt.path)
+		}
+	}
+
+	network.RemoveEdge(1, 5)
+	if _, err := network.Routes(5, RouteOptions{MaxHops: 3}); err == nil {
+		t.Fatal("route found beyond the hop limit")
+	}
+}
+
+func TestRoutesMetric(t *testing.T) {
+	network := newTwoRoutesNetwork()
+
+	tests := []struct {
+		metric     RouteMetric
+		path       []int64
+		weight     float64
+		bottleneck float64
+	}{
+		{RouteMetricWeight, []int64{1, 2, 4}, 0.7, 0.6},
+		{RouteMetricBottleneck, []int64{1, 3, 4}, 0.8, 0.4},
+	}
+	for _, tt := range tests {
+		routes, err := network.Routes(4, RouteOptions{Metric: tt.metric})
+		if err != nil {
+			t.Fatal(err)
+		}
+		route := routes[0]
+		if !slices.Equal(route.Path, tt.path) || math.Abs(route.Weight-tt.weight) > 1e-9 || route.Bottleneck != tt.bottleneck {
+			t.Fatalf("metric %s: route %+v, want %v weight %.1f bottleneck %.1f", tt.metric, route, tt.path, tt.weight, tt.bottleneck)
+		}
+	}
+}
+
+func TestRoutesAlternatives(t *testing.T) {
+	network := newMeshNetwork(100)
+	for _, metric := range []RouteMetric{RouteMetricWeight, RouteMetricBottleneck} {
+		for _, to := range []int64{10, 50, 100} {
+			routes, err := network.Routes(to, RouteOptions{K: 5, Metric: metric})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(routes) != 5 {
+				t.Fatalf("%s to %d: %d routes, want 5", metric, to, len(routes))
+			}
+			r := network.routeGraph()
+			for i, route := range routes {
+				if route.Path[0] != 1 || route.Path[len(route.Path)-1] != to {
+					t.Fatalf("%s to %d: route %v doesn't join the local device to the node", metric, to, route.Path)
+				}
+				if len(slices.Compact(slices.Sorted(slices.Values(route.Path)))) != len(route.Path) {
+					t.Fatalf("%s to %d: route %v has a loop", metric, to, route.Path)
+				}
+				for _, other := range routes[:i] {
+					if slices.Equal(other.Path, route.Path) {
+						t.Fatalf("%s to %d: route %v returned twice", metric, to, route.Path)
+					}
+				}
+				if i > 0 && r.cost(route.Path, metric).less(r.cost(routes[i-1].Path, metric)) {
+					t.Fatalf("%s to %d: route %v better than the previous one", metric, to, route.Path)
+				}
+			}
+		}
+	}
+}
+
+func TestRoutesLinkAttributes(t *testing.T) {
+	tests := []struct {
+		name   string
+		from   int64
+		to     int64
+		attrs  LinkAttributes
+		path   []int64
+		weight float64
+	}{
+		{"none", 0, 0, LinkAttributes{}, []int64{1, 2, 4}, 0.7},
+		{"excluded", 2, 4, LinkAttributes{Excluded: true}, []int64{1, 3, 4}, 0.8},
+		{"preferred", 1, 3, LinkAttributes{Preferred: true}, []int64{1, 3, 4}, 0.8},
+		{"preferred best", 1, 2, LinkAttributes{Preferred: true}, []int64{1, 2, 4}, 0.7},
+	}
+	for _, tt := range tests {
+		network := newTwoRoutesNetwork()
+		network.SetLinkAttributes(tt.from, tt.to, tt.attrs)
+		routes, err := network.Routes(4, RouteOptions{K: 2, Metric: RouteMetricWeight})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The preferred links are cheaper only for the search, the route keeps the real weights
+		if !slices.Equal(routes[0].Path, tt.path) || math.Abs(routes[0].Weight-tt.weight) > 1e-9 {
+			t.Fatalf("%s: route %+v, want %v weight %.1f", tt.name, routes[0], tt.path, tt.weight)
+		}
+		for _, route := range routes {
+			for i := 1; i < len(route.Path); i++ {
+				if tt.attrs.Excluded && route.Path[i-1] == tt.from && route.Path[i] == tt.to {
+					t.Fatalf("%s: route %v uses the excluded link", tt.name, route.Path)
+				}
+			}
+		}
+	}
+}
@@ -2,9 +2,11 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"runtime/debug"
+	"strings"
 	"syscall"
 	"time"
 
@@ -22,6 +24,12 @@ const (
 	programName        = "meshmeshgo"
 	programDescription = "hub server for meshmesh network"
 	graphFilename      = "meshmesh.graphml"
+	historyDirname     = "history"
+	cacheFilename      = "esphomecache.json"
+	portsFilename      = "esphomeports.json"
+	discoveryFilename  = "discovery.json"
+	rssiFilename       = "rssihistory.json"
+	profilesFilename   = "weightprofiles.json"
 )
 
 var (
@@ -60,7 +68,7 @@ func getBuildInfo() {
 }
 
 func initConfig() *config.Config {
-	c, err := config.NewConfig()
+	c, err := config.NewConfig(exportNetwork, importNetwork)
 	if err != nil {
 		logger.WithError(err).Fatal("Invalid config options: ")
 	}
@@ -69,28 +77,57 @@ func initConfig() *config.Config {
 		os.Exit(0)
 	}
 
+	initLogLevel(c)
+	return c
+}
+
+func initLogLevel(c *config.Config) {
 	switch c.VerboseLevel {
 	case 3:
 		logger.SetLevel(logrus.TraceLevel)
-		fmt.Println("Setting loglevel for Tracing")
+		fmt.Fprintln(os.Stderr, "Setting loglevel for Tracing")
 	case 2:
 		logger.SetLevel(logrus.DebugLevel)
-		fmt.Println("Setting loglevel for Debuging")
+		fmt.Fprintln(os.Stderr, "Setting loglevel for Debuging")
 	case 1:
 		logger.SetLevel(logrus.InfoLevel)
-		fmt.Println("Setting loglevel for Info")
+		fmt.Fprintln(os.Stderr, "Setting loglevel for Info")
 	case 0:
 		logger.SetLevel(logrus.WarnLevel)
-		fmt.Println("Setting loglevel for Errors and Warning Only.")
+		fmt.Fprintln(os.Stderr, "Setting loglevel for Errors and Warning Only.")
 	default:
-		fmt.Printf("Setting loglevel: Unknown (%d)\n", c.VerboseLevel)
+		fmt.Fprintf(os.Stderr, "Setting loglevel: Unknown (%d)\n", c.VerboseLevel)
 	}
-
-	return c
 }
 
 func networkChangedCallback() {
-	gra.GetMainNetwork().SaveToFile(graphFilename)
+	if _, err := gra.GetGraphHistory().SaveAndRecord(gra.GetMainNetwork(), graphFilename); err != nil {
+		logger.WithField("err", err).Error("Can't save the graph")
+	}
+}
+
+func initGraphHistory(config *config.Config) {
+	err := gra.LoadGraphHistory(historyDirname, config.HistoryMaxVersions, time.Duration(config.HistoryMaxDays)*24*time.Hour)
+	if err != nil {
+		logger.WithField("err", err).Error("Can't open the graph history")
+		return
+	}
+	if _, err := gra.GetGraphHistory().Record(gra.GetMainNetwork()); err != nil {
+		logger.WithField("err", err).Error("Can't record the graph version")
+	}
+}
+
+func otaSessionCallback(info meshmesh.OtaSessionInfo) {
+	fields := logger.Fields{"node": utils.FmtNodeId(int64(info.Node)), "client": info.Client, "state": info.State.String(), "percent": fmt.Sprintf("%.0f", info.Percent())}
+	switch info.State {
+	case meshmesh.OtaSessionStateSuccess:
+		logger.WithFields(fields).Info("ESPHome OTA completed")
+	case meshmesh.OtaSessionStateFailed:
+		fields["err"] = info.Error
+		logger.WithFields(fields).Error("ESPHome OTA failed")
+	default:
+		logger.WithFields(fields).Debug("ESPHome OTA progress")
+	}
 }
 
 func initNetwork(localNodeId int64) *gra.Network {
@@ -107,6 +144,20 @@ func initNetwork(localNodeId int64) *gra.Network {
 	return network
 }
 
+// initLocalChip set the chip type of the coordinator when it's not configured in the graph
+func initLocalChip(config *config.Config) {
+	network := gra.GetMainNetwork()
+	local, err := network.GetNodeDevice(network.LocalDeviceId())
+	if err != nil || local.Device().Chip() != "" {
+		return
+	}
+	if config.SerialIsEsp8266 {
+		local.Device().SetChip("esp8266")
+	} else {
+		local.Device().SetChip("esp32")
+	}
+}
+
 /* Initialize debug node TODO not implemented yet */
 func initDebugNode(config *config.Config) {
 	if len(config.DebugNodeAddr) > 0 {
@@ -124,26 +175,166 @@ func initDebugNode(config *config.Config) {
 	}
 }
 
-func handleDiscAssociateReply(v *meshmesh.DiscAssociateApiReply, serialPort *meshmesh.SerialConnection) {
-	network := gra.GetMainNetwork()
-	logger.WithFields(logger.Fields{"server": utils.FmtNodeId(int64(v.Server)), "source": utils.FmtNodeId(int64(v.Source))}).Debug("DiscAssociateReply received")
-	source, err := network.GetNodeDevice(int64(v.Source))
+func initAccessControl(config *config.Config) *meshmesh.AccessControl {
+	access := meshmesh.NewAccessControl()
+	rules, err := meshmesh.NewAccessRules(meshmesh.SplitAllowList(config.ApiAllowedClients), meshmesh.SplitAllowList(config.OtaAllowedClients))
 	if err != nil {
-		source = gra.NewNodeDevice(int64(v.Source), true, "")
-		network.AddNode(source)
-	}
-	for i := range 3 {
-		if v.NodeId[i] > 0 {
-			node, err := network.GetNodeDevice(int64(v.NodeId[i]))
-			if err != nil {
-				network.ChangeEdgeWeight(node.ID(), source.ID(), meshmesh.Rssi2weight(v.Rssi[i]), meshmesh.Rssi2weight(v.Rssi[i]))
-				logger.WithFields(logger.Fields{"id": utils.FmtNodeId(int64(v.NodeId[i])), "rssi": v.Rssi[i]}).Debug("DiscAssociateReply received")
-			}
+		logger.WithField("err", err).Fatal("Invalid allowed clients list")
+	}
+	access.SetDefault(rules, config.MaxClientsPerNode, config.MaxClients)
+
+	for nodeId, allowed := range config.NodesAllowedClients {
+		id, err := utils.ParseNodeId(nodeId)
+		if err != nil {
+			logger.WithFields(logger.Fields{"id": nodeId, "err": err}).Fatal("Invalid node id in allowed clients")
+		}
+		rules, err := meshmesh.NewAccessRules(meshmesh.SplitAllowList(allowed.Api), meshmesh.SplitAllowList(allowed.Ota))
+		if err != nil {
+			logger.WithFields(logger.Fields{"id": nodeId, "err": err}).Fatal("Invalid allowed clients list for node")
+		}
+		access.SetNodeRules(meshmesh.MeshNodeId(id), rules)
+	}
+	access.SetChangedCallback(func() { saveAccessControl(config, access) })
+	return access
+}
+
+// saveAccessControl writes the access rules changed at runtime back to the config file
+func saveAccessControl(c *config.Config, access *meshmesh.AccessControl) {
+	rules := access.DefaultRules()
+	c.ApiAllowedClients = strings.Join(rules.ApiAllow, ",")
+	c.OtaAllowedClients = strings.Join(rules.OtaAllow, ",")
+	c.MaxClientsPerNode, c.MaxClients = access.Limits()
+	c.NodesAllowedClients = make(map[string]config.NodeAllowedClients)
+	for _, id := range access.NodeIds() {
+		rules, _ := access.NodeRules(id)
+		c.NodesAllowedClients[utils.FmtNodeId(int64(id))] = config.NodeAllowedClients{
+			Api: strings.Join(rules.ApiAllow, ","),
+			Ota: strings.Join(rules.OtaAllow, ","),
+		}
+	}
+
+	err := c.SaveKeys("ApiAllowedClients", "OtaAllowedClients", "MaxClientsPerNode", "MaxClients", "NodesAllowedClients")
+	if err != nil {
+		logger.WithFields(logger.Fields{"file": c.ConfigFile, "err": err}).Error("Can't save the access rules to the config file")
+	}
+}
+
+func initDiscoveryParams(config *config.Config) {
+	err := meshmesh.SetDefaultDiscoveryParams(meshmesh.DiscoveryParams{
+		Slots:       uint8(config.DiscoverySlots),
+		Mask:        uint8(config.DiscoveryMask),
+		Filter:      uint8(config.DiscoveryFilter),
+		Wait:        time.Duration(config.DiscoveryWait) * time.Second,
+		Repetitions: config.DiscoveryRepetitions,
+		Aggregation: config.DiscoveryAggregation,
+		RssiMin:     int16(config.DiscoveryRssiMin),
+		RssiMax:     int16(config.DiscoveryRssiMax),
+	})
+	if err != nil {
+		logger.WithField("err", err).Fatal("Invalid discovery parameters")
+	}
+}
+
+func initRouteSettings(config *config.Config) {
+	err := gra.SetRouteSettings(config.RouteMaxHops, gra.RouteMetric(config.RouteMetric))
+	if err != nil {
+		logger.WithField("err", err).Fatal("Invalid route settings")
+	}
+}
+
+// exportNetwork is the action of the export command, it write the network graph in the requested format
+func exportNetwork(config *config.Config) error {
+	initLogLevel(config)
+	format, err := gra.ParseExportFormat(config.ExportFormat)
+	if err != nil {
+		return err
+	}
+	localNodeId, err := utils.ParseNodeId(config.CommandLocalNode)
+	if err != nil {
+		return fmt.Errorf("invalid local node id %s: %w", config.CommandLocalNode, err)
+	}
+	network, err := gra.NewNeworkFromFile(graphFilename, localNodeId)
+	if err != nil {
+		return fmt.Errorf("graph read error: %w", err)
+	}
+
+	out := os.Stdout
+	if config.ExportOutput != "" {
+		out, err = os.Create(config.ExportOutput)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+	}
+	return network.Export(out, format)
+}
+
+// importNetwork is the action of the import command, it import a graph in the graph file and print
+// the changes on the standard output
+func importNetwork(config *config.Config) error {
+	initLogLevel(config)
+	format, err := gra.ParseImportFormat(config.ImportFormat)
+	if err != nil {
+		return err
+	}
+	mode, err := gra.ParseImportMode(config.ImportMode)
+	if err != nil {
+		return err
+	}
+	weights, err := gra.ParseMergeWeights(config.ImportWeights)
+	if err != nil {
+		return err
+	}
+	localNodeId, err := utils.ParseNodeId(config.CommandLocalNode)
+	if err != nil {
+		return fmt.Errorf("invalid local node id %s: %w", config.CommandLocalNode, err)
+	}
+	gra.SetMainNetwork(initNetwork(localNodeId))
+
+	files := make([]io.Reader, 0, len(config.ImportInput.Value()))
+	for _, input := range config.ImportInput.Value() {
+		in, err := os.Open(input)
+		if err != nil {
+			return err
 		}
+		defer in.Close()
+		files = append(files, in)
+	}
+	graphImport, err := gra.NewGraphImport(files, format, mode)
+	if err != nil {
+		return fmt.Errorf("import error: %w", err)
+	}
+	graphImport.Weights = weights
+
+	for _, item := range graphImport.Preview() {
+		switch item.Kind {
+		case gra.DiffNodeAdded, gra.DiffNodeRemoved:
+			fmt.Printf("%s %s\n", item.Kind, utils.FmtNodeId(item.Node))
+		case gra.DiffNodeChanged:
+			fmt.Printf("%s %s tag %q -> %q inuse %t -> %t\n", item.Kind, utils.FmtNodeId(item.Node), item.OldTag, item.NewTag, item.OldInUse, item.NewInUse)
+		case gra.DiffRouteChanged:
+			fmt.Printf("%s %s %s -> %s\n", item.Kind, utils.FmtNodeId(item.Node), utils.FmtPath2Str(item.OldPath), utils.FmtPath2Str(item.NewPath))
+		default:
+			fmt.Printf("%s %s > %s %.2f -> %.2f\n", item.Kind, utils.FmtNodeId(item.From), utils.FmtNodeId(item.To), item.OldWeight, item.NewWeight)
+		}
+	}
+	if config.ImportDryRun {
+		return nil
+	}
+	// The network before the import is recorded first, the import can be rolled back from the server
+	initGraphHistory(config)
+	if err := graphImport.Commit(); err != nil {
+		return fmt.Errorf("import error: %w", err)
 	}
-	network.SaveToFile(graphFilename)
-	serialPort.SendReceiveApiProt(meshmesh.NodeIdApiRequest{}, meshmesh.UnicastProtocol, meshmesh.MeshNodeId(source.ID()), nil)
-	// ***** TODO: Update network graph with new node
+	if _, err = gra.GetGraphHistory().SaveAndRecord(gra.GetMainNetwork(), graphFilename); err != nil {
+		return fmt.Errorf("can't save the graph: %w", err)
+	}
+	return nil
+}
+
+func adoptionCallback(adoption meshmesh.Adoption) {
+	logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(adoption.Node)), "state": adoption.State.String(), "tag": adoption.Tag}).
+		Debug("Node adoption changed")
 }
 
 // @title           Meshmesh API
@@ -162,6 +353,7 @@ func handleDiscAssociateReply(v *meshmesh.DiscAssociateApiReply, serialPort *mes
 
 func main() {
 	getBuildInfo()
+	config := initConfig()
 	go waitForTermination()
 
 	fmt.Printf("Starting program: %s\n", programName)
@@ -170,8 +362,6 @@ func main() {
 		"vcsTime":  vcsTime,
 		"vcsDirty": vcsDirty}).Info("Startup information")
 
-	config := initConfig()
-
 	logger.WithFields(logger.Fields{"portName": config.SerialPortName, "baudRate": config.SerialPortBaudRate}).Debug("Opening serial port")
 	// First init serial connection with coordinator
 
@@ -181,28 +371,51 @@ func main() {
 	}
 	// Init network graph
 	gra.SetMainNetwork(initNetwork(int64(serialPort.LocalNode)))
+	initRouteSettings(config)
+	initGraphHistory(config)
+	meshmesh.LoadWeightProfiles(profilesFilename)
+	initLocalChip(config)
 	gra.AddMainNetworkChangedCallback(networkChangedCallback)
 	// Init node for spcific debug
 	initDebugNode(config)
 	gra.PrintTable(gra.GetMainNetwork())
-	// Handle DiscAssociateReply received from other nodes
-	serialPort.DiscAssociateFn = handleDiscAssociateReply
+	// Restore an interrupted discovery procedure
+	initDiscoveryParams(config)
+	meshmesh.LoadDiscoveryProgress(serialPort, discoveryFilename)
+	meshmesh.SetRssiHistory(meshmesh.NewRssiHistoryFromFile(rssiFilename))
+	defer meshmesh.GetRssiHistory().Save()
+	// Adopt the nodes that associate to the mesh
+	meshmesh.GetAdoptions().SetRequireApproval(config.AdoptionApproval)
+	meshmesh.GetAdoptions().AddCallback(adoptionCallback)
+	serialPort.DiscAssociateFn = meshmesh.GetAdoptions().HandleAssociate
 	// Initialize Esphome to HomeAssistant Server
-	esphomeapi := meshmesh.NewMultiServerApi(serialPort, meshmesh.ServerApiConfig{
+	serverApiConfig := meshmesh.ServerApiConfig{
 		BindAddress:     config.BindAddress,
+		IPv6Prefix:      config.BindIPv6Prefix,
 		BindPort:        config.BindPort,
 		BasePortOffset:  config.BasePortOffset,
 		SizeOfPortsPool: config.SizeOfPortsPool,
-	})
+		ProxyMode:       config.EsphomeProxy,
+		CacheGrace:      time.Duration(config.EsphomeCacheGrace) * time.Second,
+		Cache:           meshmesh.NewEspHomeCacheFromFile(cacheFilename),
+		MdnsAdvertise:   config.MdnsAdvertise,
+		Access:          initAccessControl(config),
+	}
+	serverApiConfig.Ports = meshmesh.NewPortAllocationTableFromFile(portsFilename, &serverApiConfig)
+	esphomeapi := meshmesh.NewMultiServerApi(serialPort, serverApiConfig)
+	defer esphomeapi.SaveCache(cacheFilename)
+	defer esphomeapi.ShutdownMdns()
+	meshmesh.GetOtaSessions().AddCallback(otaSessionCallback)
 	// Start RPC Server
 	rpcServer := rpc.NewRpcServer(config.RpcBindAddress)
-	rpcServer.Start(fmt.Sprintf("%s - %s", programName, programDescription), fmt.Sprintf("%s - %s", vcsHash, vcsTime.Format(time.RFC3339)), serialPort)
+	rpcServer.Start(fmt.Sprintf("%s - %s", programName, programDescription), fmt.Sprintf("%s - %s", vcsHash, vcsTime.Format(time.RFC3339)), serialPort, esphomeapi)
 	defer rpcServer.Stop()
 	// Start rest server
 	restHandler := rest.NewHandler(serialPort, esphomeapi)
 	rest.StartRestServer(rest.NewRouter(restHandler), config.RestBindAddress)
 
 	var lastStatsTime time.Time
+	var lastCacheSaveTime time.Time
 	for {
 		time.Sleep(1 * time.Second)
 		if quitProgram {
@@ -217,5 +430,14 @@ func main() {
 			esphomeapi.PrintStats()
 			//}
 		}
+		if time.Since(lastCacheSaveTime) > 30*time.Second {
+			lastCacheSaveTime = time.Now()
+			if err := esphomeapi.SaveCache(cacheFilename); err != nil {
+				logger.WithError(err).Error("Can't save esphome cache")
+			}
+			if err := meshmesh.GetRssiHistory().Save(); err != nil {
+				logger.WithError(err).Error("Can't save rssi history")
+			}
+		}
 	}
 }
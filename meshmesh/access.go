@@ -0,0 +1,250 @@
+package meshmesh
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"leguru.net/m/v2/logger"
+	"leguru.net/m/v2/utils"
+)
+
+// AccessRules are the networks allowed to connect to the API and OTA servers, an empty list allow everyone
+type AccessRules struct {
+	ApiAllow []string `json:"api_allow"`
+	OtaAllow []string `json:"ota_allow"`
+	apiNets  []*net.IPNet
+	otaNets  []*net.IPNet
+}
+
+// parseAllowList parse a list of CIDR or plain IP addresses
+func parseAllowList(list []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(list))
+	for _, item := range list {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if !strings.Contains(item, "/") {
+			ip := net.ParseIP(item)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid address %s", item)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 32
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(item)
+		if err != nil {
+			return nil, err
+		}
+		nets = append(nets, network)
+	}
+	return nets, nil
+}
+
+// SplitAllowList split a comma separated list of networks as used in the configuration
+func SplitAllowList(list string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func NewAccessRules(apiAllow []string, otaAllow []string) (*AccessRules, error) {
+	var err error
+	rules := &AccessRules{ApiAllow: apiAllow, OtaAllow: otaAllow}
+	if rules.apiNets, err = parseAllowList(apiAllow); err != nil {
+		return nil, err
+	}
+	if rules.otaNets, err = parseAllowList(otaAllow); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *AccessRules) allows(remotePort int, ip net.IP) bool {
+	nets := r.apiNets
+	if remotePort == fixedOtaRemotePort {
+		nets = r.otaNets
+	}
+	if len(nets) == 0 {
+		return true
+	}
+	for _, network := range nets {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// AccessControl decide if a client can connect to a node server
+type AccessControl struct {
+	lock              sync.Mutex
+	Default           *AccessRules
+	Nodes             map[MeshNodeId]*AccessRules
+	MaxClientsPerNode int
+	MaxClients        int
+	refused           map[MeshNodeId]int
+	// clients are the connections accepted for each node, total is their sum
+	clients map[MeshNodeId]int
+	total   int
+	changed func()
+}
+
+// resolve returns the rules applied to a node, each list not set for the node is taken from the default rules
+func (a *AccessControl) resolve(address MeshNodeId) (*AccessRules, bool) {
+	rules, ok := a.Nodes[address]
+	if !ok {
+		return a.Default, false
+	}
+	resolved := *rules
+	if len(resolved.apiNets) == 0 {
+		resolved.ApiAllow, resolved.apiNets = a.Default.ApiAllow, a.Default.apiNets
+	}
+	if len(resolved.otaNets) == 0 {
+		resolved.OtaAllow, resolved.otaNets = a.Default.OtaAllow, a.Default.otaNets
+	}
+	return &resolved, true
+}
+
+func (a *AccessControl) notifyChanged() {
+	if a.changed != nil {
+		a.changed()
+	}
+}
+
+// Check returns an error if the client connection to the server must be refused. An accepted
+// client takes a slot of the clients limits, the returned function gives it back.
+func (a *AccessControl) Check(address MeshNodeId, remotePort int, remote net.Addr) (func(), error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	var err error
+	rules, _ := a.resolve(address)
+
+	host, _, _ := net.SplitHostPort(remote.String())
+	ip := net.ParseIP(host)
+	if ip == nil {
+		err = fmt.Errorf("unknown client address %s", remote.String())
+	} else if !rules.allows(remotePort, ip) {
+		err = fmt.Errorf("client %s not allowed", host)
+	} else if a.MaxClientsPerNode > 0 && a.clients[address] >= a.MaxClientsPerNode {
+		err = fmt.Errorf("node clients limit of %d reached", a.MaxClientsPerNode)
+	} else if a.MaxClients > 0 && a.total >= a.MaxClients {
+		err = fmt.Errorf("hub clients limit of %d reached", a.MaxClients)
+	}
+
+	if err != nil {
+		a.refused[address] += 1
+		logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(address)), "port": remotePort, "client": remote.String(), "err": err}).Warn("Refused esphome client connection")
+		return nil, err
+	}
+
+	a.clients[address] += 1
+	a.total += 1
+	var once sync.Once
+	return func() { once.Do(func() { a.release(address) }) }, nil
+}
+
+func (a *AccessControl) release(address MeshNodeId) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.total -= 1
+	if a.clients[address] -= 1; a.clients[address] <= 0 {
+		delete(a.clients, address)
+	}
+}
+
+// Rules returns the rules applied to a node and if they are specific for it
+func (a *AccessControl) Rules(address MeshNodeId) (AccessRules, bool) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	rules, custom := a.resolve(address)
+	return *rules, custom
+}
+
+// NodeRules returns the rules set for a node without the lists inherited from the default rules
+func (a *AccessControl) NodeRules(address MeshNodeId) (AccessRules, bool) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if rules, ok := a.Nodes[address]; ok {
+		return *rules, true
+	}
+	return AccessRules{}, false
+}
+
+func (a *AccessControl) DefaultRules() AccessRules {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return *a.Default
+}
+
+func (a *AccessControl) NodeIds() []MeshNodeId {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	ids := make([]MeshNodeId, 0, len(a.Nodes))
+	for id := range a.Nodes {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (a *AccessControl) Refused(address MeshNodeId) int {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return a.refused[address]
+}
+
+func (a *AccessControl) Limits() (perNode int, total int) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return a.MaxClientsPerNode, a.MaxClients
+}
+
+func (a *AccessControl) SetDefault(rules *AccessRules, maxClientsPerNode int, maxClients int) {
+	a.lock.Lock()
+	a.Default = rules
+	a.MaxClientsPerNode = maxClientsPerNode
+	a.MaxClients = maxClients
+	a.lock.Unlock()
+	a.notifyChanged()
+}
+
+// SetNodeRules replace the rules of a node, nil restore the default rules. An empty list
+// of the node is replaced by the one of the default rules.
+func (a *AccessControl) SetNodeRules(address MeshNodeId, rules *AccessRules) {
+	a.lock.Lock()
+	if rules == nil {
+		delete(a.Nodes, address)
+	} else {
+		a.Nodes[address] = rules
+	}
+	a.lock.Unlock()
+	a.notifyChanged()
+}
+
+// SetChangedCallback set the function called after every change of the rules or of the limits
+func (a *AccessControl) SetChangedCallback(cb func()) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.changed = cb
+}
+
+func NewAccessControl() *AccessControl {
+	return &AccessControl{
+		Default: &AccessRules{},
+		Nodes:   make(map[MeshNodeId]*AccessRules),
+		refused: make(map[MeshNodeId]int),
+		clients: make(map[MeshNodeId]int),
+	}
+}
@@ -0,0 +1,348 @@
+package meshmesh
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"leguru.net/m/v2/logger"
+	"leguru.net/m/v2/utils"
+
+	gra "leguru.net/m/v2/graph"
+)
+
+type AdoptionState int
+
+const (
+	AdoptionStatePending AdoptionState = iota
+	AdoptionStateAdopting
+	AdoptionStateAdopted
+	AdoptionStateRejected
+	AdoptionStateFailed
+)
+
+func (s AdoptionState) String() string {
+	switch s {
+	case AdoptionStatePending:
+		return "pending"
+	case AdoptionStateAdopting:
+		return "adopting"
+	case AdoptionStateAdopted:
+		return "adopted"
+	case AdoptionStateRejected:
+		return "rejected"
+	case AdoptionStateFailed:
+		return "failed"
+	}
+	return "unknown"
+}
+
+// AdoptionNeighbor is a neighbor reported by a node when it associate to the mesh
+type AdoptionNeighbor struct {
+	Id   MeshNodeId
+	Rssi int16
+}
+
+// Adoption is a snapshot of the adoption of a node that associated to the mesh
+type Adoption struct {
+	Node      MeshNodeId
+	Server    MeshNodeId
+	Neighbors []AdoptionNeighbor
+	State     AdoptionState
+	Tag       string
+	Revision  string
+	Channel   uint8
+	TxPower   uint8
+	Groups    uint32
+	Binded    uint32
+	Flags     uint8
+	Error     string
+	Created   time.Time
+	Updated   time.Time
+}
+
+// Adoptions keep the nodes that associated to the mesh and drive their adoption in the main network
+type Adoptions struct {
+	lock            sync.Mutex
+	serial          *SerialConnection
+	requireApproval bool
+	adoptions       map[MeshNodeId]*Adoption
+	callbacks       []func(adoption Adoption)
+}
+
+var adoptions = &Adoptions{adoptions: make(map[MeshNodeId]*Adoption)}
+
+// GetAdoptions returns the global adoptions registry
+func GetAdoptions() *Adoptions {
+	return adoptions
+}
+
+// SetRequireApproval choose if the new nodes wait the operator approval before being adopted
+func (a *Adoptions) SetRequireApproval(require bool) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.requireApproval = require
+}
+
+func (a *Adoptions) RequireApproval() bool {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return a.requireApproval
+}
+
+// AddCallback register a function called at every state change of an adoption
+func (a *Adoptions) AddCallback(cb func(adoption Adoption)) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.callbacks = append(a.callbacks, cb)
+}
+
+func (a *Adoptions) notify(adoption Adoption) {
+	a.lock.Lock()
+	callbacks := a.callbacks
+	a.lock.Unlock()
+	for _, cb := range callbacks {
+		cb(adoption)
+	}
+}
+
+// setState change the state of an adoption and returns its snapshot
+func (a *Adoptions) setState(node MeshNodeId, state AdoptionState, err error) Adoption {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	adoption := a.adoptions[node]
+	adoption.State = state
+	adoption.Error = ""
+	if err != nil {
+		adoption.Error = err.Error()
+	}
+	adoption.Updated = time.Now()
+	return a.snapshot(adoption)
+}
+
+func (a *Adoptions) snapshot(adoption *Adoption) Adoption {
+	snapshot := *adoption
+	snapshot.Neighbors = append([]AdoptionNeighbor(nil), adoption.Neighbors...)
+	return snapshot
+}
+
+// List returns a snapshot of the known adoptions
+func (a *Adoptions) List() []Adoption {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	list := make([]Adoption, 0, len(a.adoptions))
+	for _, adoption := range a.adoptions {
+		list = append(list, a.snapshot(adoption))
+	}
+	return list
+}
+
+func (a *Adoptions) Get(node MeshNodeId) (Adoption, bool) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	adoption, ok := a.adoptions[node]
+	if !ok {
+		return Adoption{}, false
+	}
+	return a.snapshot(adoption), true
+}
+
+// HandleAssociate process the association of a node to the mesh. It's called from the serial
+// reader so the node queries are done in background.
+func (a *Adoptions) HandleAssociate(v *DiscAssociateApiReply, serial *SerialConnection) {
+	if v.Source == 0 {
+		return
+	}
+
+	neighbors := make([]AdoptionNeighbor, 0, len(v.NodeId))
+	for i := range v.NodeId {
+		if v.NodeId[i] > 0 {
+			neighbors = append(neighbors, AdoptionNeighbor{Id: v.NodeId[i], Rssi: v.Rssi[i]})
+		}
+	}
+
+	network := gra.GetMainNetwork()
+	a.lock.Lock()
+	a.serial = serial
+	adoption, ok := a.adoptions[v.Source]
+	if network.NodeIdExists(int64(v.Source)) && (!ok || adoption.State == AdoptionStateAdopted) {
+		// Already known node, just refresh the reported links
+		a.lock.Unlock()
+		_ = gra.UpdateMainNetwork(func(network *gra.Network) error {
+			addAssociateEdges(network, v.Source, neighbors)
+			return nil
+		})
+		return
+	}
+	if ok && (adoption.State == AdoptionStateAdopting || adoption.State == AdoptionStateRejected) {
+		state := adoption.State
+		a.lock.Unlock()
+		logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(v.Source)), "state": state.String()}).
+			Debug("Associate ignored")
+		return
+	}
+	if !ok {
+		adoption = &Adoption{Node: v.Source, State: AdoptionStatePending, Created: time.Now()}
+		a.adoptions[v.Source] = adoption
+	}
+	adoption.Server = v.Server
+	adoption.Neighbors = neighbors
+	adoption.Updated = time.Now()
+	pending := a.requireApproval && adoption.State == AdoptionStatePending
+	a.lock.Unlock()
+
+	if pending {
+		info := a.setState(v.Source, AdoptionStatePending, nil)
+		logger.WithField("node", utils.FmtNodeId(int64(v.Source))).Info("New node waiting for adoption approval")
+		a.notify(info)
+		return
+	}
+	go a.adopt(v.Source)
+}
+
+// Approve adopt a pending, rejected or failed node
+func (a *Adoptions) Approve(node MeshNodeId) error {
+	a.lock.Lock()
+	adoption, ok := a.adoptions[node]
+	if !ok {
+		a.lock.Unlock()
+		return fmt.Errorf("node %s is not waiting for adoption", utils.FmtNodeId(int64(node)))
+	}
+	if adoption.State == AdoptionStateAdopting || adoption.State == AdoptionStateAdopted {
+		a.lock.Unlock()
+		return fmt.Errorf("node %s is already %s", utils.FmtNodeId(int64(node)), adoption.State.String())
+	}
+	if a.serial == nil {
+		a.lock.Unlock()
+		return errors.New("serial connection not available")
+	}
+	a.lock.Unlock()
+
+	go a.adopt(node)
+	return nil
+}
+
+// Reject refuse the adoption of a node, the following associations of the node are ignored
+func (a *Adoptions) Reject(node MeshNodeId) error {
+	a.lock.Lock()
+	adoption, ok := a.adoptions[node]
+	if !ok {
+		a.lock.Unlock()
+		return fmt.Errorf("node %s is not waiting for adoption", utils.FmtNodeId(int64(node)))
+	}
+	if adoption.State != AdoptionStatePending && adoption.State != AdoptionStateFailed {
+		a.lock.Unlock()
+		return fmt.Errorf("node %s is %s and can't be rejected", utils.FmtNodeId(int64(node)), adoption.State.String())
+	}
+	a.lock.Unlock()
+
+	info := a.setState(node, AdoptionStateRejected, nil)
+	logger.WithField("node", utils.FmtNodeId(int64(node))).Info("Node adoption rejected")
+	a.notify(info)
+	return nil
+}
+
+// adopt add the node and its links to the main network then query its configuration
+func (a *Adoptions) adopt(node MeshNodeId) {
+	a.lock.Lock()
+	adoption := a.adoptions[node]
+	if adoption.State == AdoptionStateAdopting {
+		a.lock.Unlock()
+		return
+	}
+	adoption.State = AdoptionStateAdopting
+	adoption.Error = ""
+	adoption.Updated = time.Now()
+	info := a.snapshot(adoption)
+	serial := a.serial
+	a.lock.Unlock()
+	a.notify(info)
+
+	var dev gra.NodeDevice
+	_ = gra.UpdateMainNetwork(func(network *gra.Network) error {
+		var err error
+		dev, err = network.GetNodeDevice(int64(node))
+		if err != nil {
+			dev = gra.NewNodeDevice(int64(node), true, "")
+			network.AddNode(dev)
+		} else {
+			dev.Device().SetInUse(true)
+			gra.NotifyMainNetworkChanged()
+		}
+		addAssociateEdges(network, node, info.Neighbors)
+		return nil
+	})
+
+	err := a.queryNode(serial, node)
+	if err != nil {
+		logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(node)), "err": err}).Error("Node adoption failed")
+		a.notify(a.setState(node, AdoptionStateFailed, err))
+		return
+	}
+
+	info = a.setState(node, AdoptionStateAdopted, nil)
+	if dev.Device().Tag() == "" && info.Tag != "" {
+		dev.Device().SetTag(info.Tag)
+	}
+	dev.Device().SetFirmware(info.Revision)
+	dev.Device().SetConfig(gra.NodeConfig{Channel: info.Channel, TxPower: info.TxPower, Groups: info.Groups, BindedServer: info.Binded, Flags: info.Flags})
+	// Notify again to create the ESPHome servers with the node tag
+	gra.NotifyMainNetworkChanged()
+	logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(node)), "tag": info.Tag, "revision": info.Revision}).Info("Node adopted")
+	a.notify(info)
+}
+
+// queryNode read the identity, firmware and configuration of the adopted node
+func (a *Adoptions) queryNode(serial *SerialConnection, node MeshNodeId) error {
+	network := gra.GetMainNetwork()
+	protocol := FindBestProtocol(node, network)
+	rep, err := serial.SendReceiveApiProt(NodeIdApiRequest{}, protocol, node, network)
+	if err != nil {
+		return err
+	}
+	if id := rep.(NodeIdApiReply).Serial; id != node {
+		return fmt.Errorf("node replied with id %s", utils.FmtNodeId(int64(id)))
+	}
+
+	rep, err = serial.SendReceiveApiProt(FirmRevApiRequest{}, protocol, node, network)
+	if err != nil {
+		return err
+	}
+	rev := rep.(FirmRevApiReply)
+
+	rep, err = serial.SendReceiveApiProt(NodeConfigApiRequest{}, protocol, node, network)
+	if err != nil {
+		return err
+	}
+	cfg := rep.(NodeConfigApiReply)
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	adoption := a.adoptions[node]
+	adoption.Revision = rev.Revision
+	adoption.Tag = utils.TruncateZeros(cfg.Tag)
+	adoption.Channel = cfg.Channel
+	adoption.TxPower = cfg.TxPower
+	adoption.Groups = cfg.Groups
+	adoption.Binded = cfg.BindedServer
+	adoption.Flags = cfg.Flags
+	return nil
+}
+
+// addAssociateEdges add the links reported by an associated node. The reverse links are added
+// with the same weight when missing, so the node is reachable before the next discovery.
+func addAssociateEdges(network *gra.Network, node MeshNodeId, neighbors []AdoptionNeighbor) {
+	for _, neighbor := range neighbors {
+		if !network.NodeIdExists(int64(neighbor.Id)) {
+			logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(node)), "neighbor": utils.FmtNodeId(int64(neighbor.Id))}).
+				Debug("Associate neighbor not in graph")
+			continue
+		}
+		weight := nodeRssi2weight(network, int64(node), neighbor.Rssi, DefaultDiscoveryParams())
+		network.SetDiscoveredEdgeWeight(int64(node), int64(neighbor.Id), weight)
+		if !network.HasEdgeFromTo(int64(neighbor.Id), int64(node)) {
+			network.SetDiscoveredEdgeWeight(int64(neighbor.Id), int64(node), weight)
+		}
+	}
+}
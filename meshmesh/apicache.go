@@ -0,0 +1,299 @@
+package meshmesh
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"leguru.net/m/v2/logger"
+)
+
+const apiCacheMaxQueuedCommands = 32
+
+// EspHomeCachedFrame is an ESPHome message stored in the cache with its reception time
+type EspHomeCachedFrame struct {
+	Type    uint32    `json:"type"`
+	Payload []byte    `json:"payload"`
+	Updated time.Time `json:"updated"`
+}
+
+func (c *EspHomeCachedFrame) Frame() *EspHomeFrame {
+	return NewEspHomeFrame(c.Type, c.Payload)
+}
+
+func newEspHomeCachedFrame(frame *EspHomeFrame) *EspHomeCachedFrame {
+	return &EspHomeCachedFrame{Type: frame.Type, Payload: frame.Payload, Updated: time.Now()}
+}
+
+// EspHomeNodeCache hold the last known handshake replies, entities and states of a node
+type EspHomeNodeCache struct {
+	lock       sync.Mutex
+	Hello      *EspHomeCachedFrame   `json:"hello"`
+	Connect    *EspHomeCachedFrame   `json:"connect"`
+	DeviceInfo *EspHomeCachedFrame   `json:"device_info"`
+	Entities   []*EspHomeCachedFrame `json:"entities"`
+	States     []*EspHomeCachedFrame `json:"states"`
+	Commands   []*EspHomeCachedFrame `json:"commands"`
+	LastSeen   time.Time             `json:"last_seen"`
+	onChange   func()
+}
+
+func (n *EspHomeNodeCache) changed() {
+	n.lock.Lock()
+	onChange := n.onChange
+	n.lock.Unlock()
+	if onChange != nil {
+		onChange()
+	}
+}
+
+// IsComplete reports if the cache contains everything needed to answer a client handshake
+func (n *EspHomeNodeCache) IsComplete() bool {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return n.Hello != nil && n.Connect != nil && n.DeviceInfo != nil && n.Entities != nil
+}
+
+func (n *EspHomeNodeCache) GetLastSeen() time.Time {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return n.LastSeen
+}
+
+func (n *EspHomeNodeCache) Touch() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	n.LastSeen = time.Now()
+}
+
+func (n *EspHomeNodeCache) SetHello(frame *EspHomeFrame) {
+	n.lock.Lock()
+	n.Hello = newEspHomeCachedFrame(frame)
+	n.lock.Unlock()
+	n.changed()
+}
+
+func (n *EspHomeNodeCache) SetConnect(frame *EspHomeFrame) {
+	n.lock.Lock()
+	n.Connect = newEspHomeCachedFrame(frame)
+	n.lock.Unlock()
+	n.changed()
+}
+
+func (n *EspHomeNodeCache) SetDeviceInfo(frame *EspHomeFrame) {
+	n.lock.Lock()
+	n.DeviceInfo = newEspHomeCachedFrame(frame)
+	n.lock.Unlock()
+	n.changed()
+}
+
+func (n *EspHomeNodeCache) HandshakeFrame(msgType uint32) *EspHomeFrame {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	var cached *EspHomeCachedFrame
+	switch msgType {
+	case espHomeHelloRequest:
+		cached = n.Hello
+	case espHomeConnectRequest:
+		cached = n.Connect
+	case espHomeDeviceInfoRequest:
+		cached = n.DeviceInfo
+	}
+	if cached == nil {
+		return nil
+	}
+	return cached.Frame()
+}
+
+// SetEntities replace the entities list and return true if it differs from the previous one
+func (n *EspHomeNodeCache) SetEntities(frames []*EspHomeFrame) bool {
+	n.lock.Lock()
+	changed := len(frames) != len(n.Entities)
+	entities := make([]*EspHomeCachedFrame, len(frames))
+	for i, frame := range frames {
+		if !changed && (n.Entities[i].Type != frame.Type || string(n.Entities[i].Payload) != string(frame.Payload)) {
+			changed = true
+		}
+		entities[i] = newEspHomeCachedFrame(frame)
+	}
+	n.Entities = entities
+	if changed {
+		n.States = nil
+	}
+	n.lock.Unlock()
+	n.changed()
+	return changed
+}
+
+func (n *EspHomeNodeCache) EntitiesFrames() []*EspHomeFrame {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	frames := make([]*EspHomeFrame, len(n.Entities))
+	for i, entity := range n.Entities {
+		frames[i] = entity.Frame()
+	}
+	return frames
+}
+
+func (n *EspHomeNodeCache) SetState(frame *EspHomeFrame, key uint32) {
+	n.lock.Lock()
+	state := newEspHomeCachedFrame(frame)
+	found := false
+	for i, s := range n.States {
+		if s.Type == frame.Type {
+			if k, ok := s.Frame().StateKey(); ok && k == key {
+				n.States[i] = state
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		n.States = append(n.States, state)
+	}
+	n.lock.Unlock()
+	n.changed()
+}
+
+func (n *EspHomeNodeCache) StatesFrames() []*EspHomeFrame {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	frames := make([]*EspHomeFrame, len(n.States))
+	for i, state := range n.States {
+		frames[i] = state.Frame()
+	}
+	return frames
+}
+
+// StaleStatesFrames returns the cached states flagged as missing, used while the node is unreachable.
+// The states that can't carry the flag (switches, lights, covers...) are left out, the clients
+// would take them as live.
+func (n *EspHomeNodeCache) StaleStatesFrames() []*EspHomeFrame {
+	frames := make([]*EspHomeFrame, 0)
+	for _, frame := range n.StatesFrames() {
+		if stale, ok := frame.WithMissingState(); ok {
+			frames = append(frames, stale)
+		}
+	}
+	return frames
+}
+
+// QueueCommand store a client command to replay it when the node will be reachable again
+func (n *EspHomeNodeCache) QueueCommand(frame *EspHomeFrame) {
+	n.lock.Lock()
+	if len(n.Commands) >= apiCacheMaxQueuedCommands {
+		n.Commands = n.Commands[1:]
+	}
+	n.Commands = append(n.Commands, newEspHomeCachedFrame(frame))
+	n.lock.Unlock()
+	n.changed()
+}
+
+// PopCommands returns and remove the queued commands newer than maxAge
+func (n *EspHomeNodeCache) PopCommands(maxAge time.Duration) []*EspHomeFrame {
+	n.lock.Lock()
+	frames := make([]*EspHomeFrame, 0, len(n.Commands))
+	for _, command := range n.Commands {
+		if maxAge <= 0 || time.Since(command.Updated) <= maxAge {
+			frames = append(frames, command.Frame())
+		}
+	}
+	hadCommands := len(n.Commands) > 0
+	n.Commands = nil
+	n.lock.Unlock()
+	if hadCommands {
+		n.changed()
+	}
+	return frames
+}
+
+func (n *EspHomeNodeCache) Counters() (entities int, states int, commands int) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return len(n.Entities), len(n.States), len(n.Commands)
+}
+
+// EspHomeCache is the persistent collection of the nodes caches
+type EspHomeCache struct {
+	lock  sync.Mutex
+	Nodes map[MeshNodeId]*EspHomeNodeCache `json:"nodes"`
+	dirty bool
+}
+
+func (c *EspHomeCache) setDirty() {
+	c.lock.Lock()
+	c.dirty = true
+	c.lock.Unlock()
+}
+
+func (c *EspHomeCache) Node(address MeshNodeId) *EspHomeNodeCache {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	node, ok := c.Nodes[address]
+	if !ok {
+		node = &EspHomeNodeCache{}
+		c.Nodes[address] = node
+	}
+	node.lock.Lock()
+	node.onChange = c.setDirty
+	node.lock.Unlock()
+	return node
+}
+
+func (c *EspHomeCache) NodeIds() []MeshNodeId {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	ids := make([]MeshNodeId, 0, len(c.Nodes))
+	for id := range c.Nodes {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// SaveToFile writes the cache on disk if something changed since the last save
+func (c *EspHomeCache) SaveToFile(filename string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if !c.dirty {
+		return nil
+	}
+
+	for _, node := range c.Nodes {
+		node.lock.Lock()
+	}
+	data, err := json.Marshal(c)
+	for _, node := range c.Nodes {
+		node.lock.Unlock()
+	}
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(filename, data, 0644)
+	if err == nil {
+		c.dirty = false
+	}
+	return err
+}
+
+func NewEspHomeCache() *EspHomeCache {
+	return &EspHomeCache{Nodes: make(map[MeshNodeId]*EspHomeNodeCache)}
+}
+
+func NewEspHomeCacheFromFile(filename string) *EspHomeCache {
+	cache := NewEspHomeCache()
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return cache
+	}
+	err = json.Unmarshal(data, cache)
+	if err != nil {
+		logger.WithFields(logger.Fields{"file": filename, "err": err}).Warn("Invalid esphome cache file, starting with an empty one")
+		return NewEspHomeCache()
+	}
+	if cache.Nodes == nil {
+		cache.Nodes = make(map[MeshNodeId]*EspHomeNodeCache)
+	}
+	return cache
+}
@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"net"
-	"time"
 
 	"leguru.net/m/v2/logger"
 	"leguru.net/m/v2/utils"
@@ -17,29 +16,36 @@ type ApiConnection struct {
 	inAwaitSize int
 }
 
-func (client *ApiConnection) forward(lastbyte byte) {
-	client.inBuffer.WriteByte(lastbyte)
-	switch client.inState {
-	case esphomeapiWaitPacketHead:
-		if lastbyte == 0x00 {
-			client.inState = esphomeapiWaitPacketSize
-		} else {
-			client.inBuffer.Reset()
-		}
-	case esphomeapiWaitPacketSize:
-		client.inAwaitSize = int(lastbyte) + 3
-		client.inState = esphomeapiWaitPacketData
-	default:
-		if client.inBuffer.Len() == client.inAwaitSize {
-			client.inState = esphomeapiWaitPacketHead
-			logger.WithField("handle", client.meshprotocol.handle).
-				Trace(fmt.Sprintf("HA-->SE: %s", hex.EncodeToString(client.inBuffer.Bytes())))
-			err := client.meshprotocol.SendData(client.inBuffer.Bytes())
-			client.Stats.SentBytes(client.inBuffer.Len())
-			if err != nil {
-				logger.Log().Error(fmt.Sprintf("Error writng on socket: %s", err.Error()))
+// forward split the data read from the socket in ESPHome frames and send each complete frame to the node
+func (client *ApiConnection) forward(data []byte) {
+	for len(data) > 0 {
+		switch client.inState {
+		case esphomeapiWaitPacketHead:
+			if data[0] == 0x00 {
+				client.inBuffer.WriteByte(data[0])
+				client.inState = esphomeapiWaitPacketSize
+			}
+			data = data[1:]
+		case esphomeapiWaitPacketSize:
+			client.inBuffer.WriteByte(data[0])
+			client.inAwaitSize = int(data[0]) + 3
+			client.inState = esphomeapiWaitPacketData
+			data = data[1:]
+		default:
+			n := min(len(data), client.inAwaitSize-client.inBuffer.Len())
+			client.inBuffer.Write(data[:n])
+			data = data[n:]
+			if client.inBuffer.Len() == client.inAwaitSize {
+				client.inState = esphomeapiWaitPacketHead
+				logger.WithField("handle", client.meshprotocol.handle).
+					Trace(fmt.Sprintf("HA-->SE: %s", hex.EncodeToString(client.inBuffer.Bytes())))
+				err := client.meshprotocol.SendData(client.inBuffer.Bytes())
+				client.Stats.SentBytes(client.inBuffer.Len())
+				if err != nil {
+					logger.Log().Error(fmt.Sprintf("Error writng on socket: %s", err.Error()))
+				}
+				client.inBuffer.Reset()
 			}
-			client.inBuffer.Reset()
 		}
 	}
 }
@@ -60,6 +66,7 @@ func (client *ApiConnection) startHandshake(addr MeshNodeId, port int) error {
 
 func (client *ApiConnection) FinishHandshake(result bool) {
 	logger.WithField("res", result).Debug("finishHandshake")
+	handshakes.Done(client)
 	if !result {
 		logger.WithFields(logger.Fields{"addr": client.reqAddress, "port": client.reqPort, "err": nil}).
 			Warning("ApiConnection.finishHandshake failed")
@@ -73,10 +80,8 @@ func (client *ApiConnection) FinishHandshake(result bool) {
 
 func (client *ApiConnection) flushBuffer() {
 	if client.tmpBuffer.Len() > 0 {
-		_b := client.tmpBuffer.Bytes()
-		for i := 0; i < len(_b); i++ {
-			client.forward(_b[i])
-		}
+		client.forward(client.tmpBuffer.Bytes())
+		client.tmpBuffer.Reset()
 	}
 }
 
@@ -87,6 +92,7 @@ func (client *ApiConnection) SetClosedCallback(cb func(client NetworkConnection)
 func (client *ApiConnection) Close() {
 	client.socketOpen = false
 	client.socket.Close()
+	handshakes.Done(client)
 	utils.ForceDebug(client.debugThisNode, "Waiting for read go-routine to terminate...")
 	client.socketWaitGroup.Wait()
 	client.Stats.Stop()
@@ -94,39 +100,23 @@ func (client *ApiConnection) Close() {
 	client.clientClosed(client)
 }
 
-func (client *ApiConnection) CheckTimeout() {
-	for {
-		if !client.socketOpen {
-			break
-		}
-		if client.meshprotocol.connState == connPathConnectionStateInit || client.meshprotocol.connState == connPathConnectionStateHandshakeStarted {
-			if time.Since(client.timeout).Milliseconds() > 3000 {
-				logger.Error(fmt.Sprintf("Closing connection beacuse timeout after %dms in connPathConnectionStateInit for handle %d", time.Since(client.timeout).Milliseconds(), client.meshprotocol.handle))
-				client.Close()
-			}
-		}
-		time.Sleep(100 * time.Millisecond)
-	}
-
-	logger.Debug("ApiConnection.CheckTimeout exited")
-}
-
 func (client *ApiConnection) Read() {
 	var err error
 
+	var n int
+	var buffer = make([]byte, networkReadBufferSize)
 	for {
-		var buffer = make([]byte, 1)
-		_, err = client.socket.Read(buffer)
-		client.Stats.ReceivedBytes(1)
+		n, err = client.socket.Read(buffer)
+		client.Stats.ReceivedBytes(n)
 
 		if err == nil {
 			switch client.meshprotocol.connState {
 			case connPathConnectionStateHandshakeStarted:
 				// FIXME check for if buffer grown outside limits
-				client.tmpBuffer.WriteByte(buffer[0])
+				client.tmpBuffer.Write(buffer[:n])
 			case connPathConnectionStateActive:
 				// FIXME handle error
-				client.forward(buffer[0])
+				client.forward(buffer[:n])
 			default:
 				logger.WithField("state", client.meshprotocol.connState).
 					Error(fmt.Errorf("readed data while in wrong connection state %d", client.meshprotocol.connState))
@@ -173,8 +163,8 @@ func NewApiConnection(socket net.Conn, serial *SerialConnection, addr MeshNodeId
 	}
 
 	client.socketWaitGroup.Add(1)
+	handshakes.Watch(client)
 	go client.Read()
-	go client.CheckTimeout()
 
 	return client, nil
 }
@@ -6,10 +6,14 @@ import (
 	"fmt"
 
 	"github.com/go-restruct/restruct"
+	"golang.org/x/exp/slices"
 	"leguru.net/m/v2/graph"
 	"leguru.net/m/v2/logger"
 )
 
+// maxAlternativeRoutes is the number of alternative routes tried when a node doesn't answer on its best route
+const maxAlternativeRoutes = 2
+
 type MeshNodeId uint32
 
 type MeshProtocol byte
@@ -792,6 +796,33 @@ func FindBestProtocol(target MeshNodeId, network *graph.Network) MeshProtocol {
 	}
 }
 
+// alternativePaths returns the paths of the next best routes to the target, the route returned
+// by GetPath excluded. They are tried when the target can't be reached on its usual route.
+func alternativePaths(network *graph.Network, target MeshNodeId) [][]int64 {
+	if network == nil {
+		return nil
+	}
+	device, err := network.GetNodeDevice(int64(target))
+	if err != nil {
+		return nil
+	}
+	best, _, err := network.GetPath(device)
+	if err != nil {
+		return nil
+	}
+	routes, err := network.Routes(int64(target), graph.RouteOptions{K: maxAlternativeRoutes + 1})
+	if err != nil {
+		return nil
+	}
+	paths := make([][]int64, 0, maxAlternativeRoutes)
+	for _, route := range routes {
+		if len(paths) < maxAlternativeRoutes && !slices.Equal(route.Path, best) {
+			paths = append(paths, route.Path)
+		}
+	}
+	return paths
+}
+
 func FindBestProtocolOverride(target MeshNodeId, protocol MeshProtocol, network *graph.Network) MeshProtocol {
 	if protocol == AutoProtocol {
 		return FindBestProtocol(target, network)
@@ -843,28 +874,36 @@ func NewApiFrameFromStruct(v interface{}, protocol MeshProtocol, target MeshNode
 		if err != nil {
 			return nil, err
 		}
-		if len(path) == 1 {
-			return nil, errors.New("requested target is the local node. Use directProtocol instead")
-		}
-		// Removed the local node and the target node from the path
-		_path := make([]uint32, len(path)-2)
-		for i, p := range path[1 : len(path)-1] {
-			_path[i] = uint32(p)
-		}
-		// Initialize the multipath request with the path and the target
-		p := MultiPathRequest{Id: multipathRequest, Target: target, PathLen: uint8(len(_path)), Path: _path}
-		p.Payload, err = EncodeBuffer(v)
-		if err != nil {
-			return nil, err
-		}
-		// Encode the multipath request
-		err = f.EncodeFrame(p)
-		if err != nil {
-			return nil, err
-		}
+		return newMultipathFrame(v, target, path)
 	default:
 		return nil, errors.New("unknow protocol requested")
 	}
 
 	return f, nil
 }
+
+// newMultipathFrame encode a request to target following path, the path starts with the local node
+func newMultipathFrame(v interface{}, target MeshNodeId, path []int64) (*ApiFrame, error) {
+	if len(path) == 1 {
+		return nil, errors.New("requested target is the local node. Use directProtocol instead")
+	}
+	// Removed the local node and the target node from the path
+	_path := make([]uint32, len(path)-2)
+	for i, p := range path[1 : len(path)-1] {
+		_path[i] = uint32(p)
+	}
+	// Initialize the multipath request with the path and the target
+	var err error
+	p := MultiPathRequest{Id: multipathRequest, Target: target, PathLen: uint8(len(_path)), Path: _path}
+	p.Payload, err = EncodeBuffer(v)
+	if err != nil {
+		return nil, err
+	}
+	// Encode the multipath request
+	f := &ApiFrame{}
+	err = f.EncodeFrame(p)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
@@ -0,0 +1,717 @@
+package meshmesh
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/slices"
+	"google.golang.org/protobuf/encoding/protowire"
+	"leguru.net/m/v2/logger"
+	"leguru.net/m/v2/utils"
+)
+
+const apiProxyReadyTimeout = 15 * time.Second
+const apiProxyRetryInterval = 30 * time.Second
+const apiProxyClientWriteTimeout = 2 * time.Second
+const apiProxyClientQueueSize = 64
+
+type apiProxyState int
+
+const (
+	apiProxyStateIdle apiProxyState = iota
+	apiProxyStateConnecting
+	apiProxyStateHello
+	apiProxyStateConnect
+	apiProxyStateDeviceInfo
+	apiProxyStateListEntities
+	apiProxyStateReady
+)
+
+// ApiProxy keep a single ESPHome API session with a node and share it with all the clients
+// connected to the node server. Handshake and entities requests are answered from cache.
+// When a grace period is configured the cache is also used to serve the clients while the
+// node is unreachable and the commands are queued to be replayed when the node returns.
+type ApiProxy struct {
+	lock          sync.Mutex
+	serial        *SerialConnection
+	address       MeshNodeId
+	port          int
+	meshprotocol  *ConnPathConnection
+	Stats         *EspApiConnectionStats
+	state         apiProxyState
+	upstream      EspHomeFrameReader
+	readyTimer    *time.Timer
+	retryTimer    *time.Timer
+	cache         *EspHomeNodeCache
+	cacheGrace    time.Duration
+	entities      []*EspHomeFrame
+	subscriptions map[uint32]*EspHomeFrame
+	clients       []*ApiProxyClient
+	// connectRequest is the connect request sent to the node, the password of the first client.
+	// authenticated is set when the node accepts it, the other clients must send the same password.
+	connectRequest *EspHomeFrame
+	authenticated  bool
+}
+
+// ApiProxyClient is a downstream client of an ApiProxy
+type ApiProxyClient struct {
+	proxy    *ApiProxy
+	socket   net.Conn
+	reader   EspHomeFrameReader
+	out      chan []byte
+	pending  []*EspHomeFrame
+	requests map[uint32]bool
+	// connect is the connect request of the client, an empty password for the clients that skip it
+	connect       *EspHomeFrame
+	authenticated bool
+	stale         bool
+	closed        bool
+}
+
+// write queue the frames for the writer goroutine, the proxy lock is never held during the socket
+// writes. A client that can't keep up with the queue is disconnected.
+func (c *ApiProxyClient) write(frames ...*EspHomeFrame) error {
+	if c.closed {
+		return errors.New("client is closed")
+	}
+	var out []byte
+	for _, frame := range frames {
+		if frame == nil {
+			continue
+		}
+		out = append(out, frame.Encode()...)
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	select {
+	case c.out <- out:
+		return nil
+	default:
+		return errors.New("client write queue is full")
+	}
+}
+
+// writeLoop write the queued frames, the socket is closed after the last one
+func (c *ApiProxyClient) writeLoop() {
+	for out := range c.out {
+		c.socket.SetWriteDeadline(time.Now().Add(apiProxyClientWriteTimeout))
+		if _, err := c.socket.Write(out); err != nil {
+			// The read goroutine fails too and removes the client
+			c.socket.Close()
+		}
+	}
+	c.socket.Close()
+}
+
+// close stop the client once the queued frames are written
+func (c *ApiProxyClient) close() {
+	if c.closed {
+		return
+	}
+	c.closed = true
+	close(c.out)
+}
+
+func (c *ApiProxyClient) read() {
+	buffer := make([]byte, 1024)
+	for {
+		n, err := c.socket.Read(buffer)
+		if err != nil {
+			logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(c.proxy.address)), "err": err}).Debug("ApiProxyClient.read exit")
+			break
+		}
+
+		c.reader.Write(buffer[:n])
+		for {
+			frame, err := c.reader.Next()
+			if err != nil {
+				logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(c.proxy.address)), "err": err}).Warn("ApiProxyClient.read invalid frame")
+				break
+			}
+			if frame == nil {
+				break
+			}
+			c.proxy.handleClientFrame(c, frame)
+		}
+	}
+
+	c.proxy.RemoveClient(c)
+}
+
+func (p *ApiProxy) MeshProtocol() *ConnPathConnection {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.meshprotocol
+}
+
+func (p *ApiProxy) ClientsCount() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return len(p.clients)
+}
+
+func (p *ApiProxy) IsReady() bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.state == apiProxyStateReady
+}
+
+// IsStale reports if the proxy is serving its clients from the cache because the node is unreachable
+func (p *ApiProxy) IsStale() bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.state != apiProxyStateReady && len(p.clients) > 0 && p.canServeFromCache()
+}
+
+func (p *ApiProxy) Cache() *EspHomeNodeCache {
+	return p.cache
+}
+
+// canServeFromCache reports if the clients can be answered without waiting the node session
+func (p *ApiProxy) canServeFromCache() bool {
+	if p.state == apiProxyStateReady {
+		return true
+	}
+	if p.cacheGrace <= 0 || !p.cache.IsComplete() {
+		return false
+	}
+	return time.Since(p.cache.GetLastSeen()) < p.cacheGrace
+}
+
+func (p *ApiProxy) sendUpstream(frame *EspHomeFrame) {
+	if p.meshprotocol == nil {
+		return
+	}
+	data := frame.Encode()
+	err := p.meshprotocol.SendData(data)
+	if err != nil {
+		logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(p.address)), "err": err}).Error("ApiProxy.sendUpstream error")
+		return
+	}
+	p.Stats.SentBytes(len(data))
+}
+
+func (p *ApiProxy) connectUpstream() {
+	if p.state != apiProxyStateIdle {
+		return
+	}
+	if p.retryTimer != nil {
+		p.retryTimer.Stop()
+		p.retryTimer = nil
+	}
+
+	p.meshprotocol = NewConnPathConnection(p.serial)
+	p.upstream = EspHomeFrameReader{}
+	err := p.meshprotocol.OpenConnectionAsync(p.address, uint16(p.port))
+	if err != nil {
+		logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(p.address)), "err": err}).Error("ApiProxy can't open upstream connection")
+		p.meshprotocol = nil
+		p.upstreamFailed()
+		return
+	}
+
+	p.state = apiProxyStateConnecting
+	p.Stats.Start()
+	p.readyTimer = time.AfterFunc(apiProxyReadyTimeout, p.checkReadyTimeout)
+}
+
+func (p *ApiProxy) retryUpstream() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.retryTimer = nil
+	if len(p.clients) == 0 {
+		return
+	}
+	if !p.canServeFromCache() {
+		logger.WithField("node", utils.FmtNodeId(int64(p.address))).Warn("ApiProxy cache grace period expired, closing clients")
+		p.closeClients()
+		return
+	}
+	p.connectUpstream()
+}
+
+func (p *ApiProxy) checkReadyTimeout() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.state != apiProxyStateIdle && p.state != apiProxyStateReady {
+		logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(p.address)), "state": p.state}).Error("ApiProxy timeout waiting for upstream session")
+		p.closeUpstream()
+		p.upstreamFailed()
+	}
+}
+
+// upstreamFailed keep the clients served from cache during the grace period or disconnect them
+func (p *ApiProxy) upstreamFailed() {
+	if len(p.clients) > 0 && p.canServeFromCache() {
+		logger.WithField("node", utils.FmtNodeId(int64(p.address))).Warn("ApiProxy node unreachable, serving clients from cache")
+		for _, client := range p.clients {
+			if !client.stale && client.requests[espHomeSubscribeStatesRequest] {
+				// The states already sent are no more live
+				p.writeClient(client, p.cache.StaleStatesFrames()...)
+			}
+			client.stale = true
+			p.answerPending(client)
+		}
+		if p.retryTimer == nil {
+			p.retryTimer = time.AfterFunc(apiProxyRetryInterval, p.retryUpstream)
+		}
+		return
+	}
+	p.closeClients()
+}
+
+func (p *ApiProxy) closeUpstream() {
+	if p.readyTimer != nil {
+		p.readyTimer.Stop()
+		p.readyTimer = nil
+	}
+	if p.meshprotocol != nil {
+		if p.meshprotocol.connState != connPathConnectionStateInvalid {
+			p.meshprotocol.Disconnect()
+		}
+		p.meshprotocol = nil
+		p.Stats.Stop()
+	}
+	p.state = apiProxyStateIdle
+}
+
+func (p *ApiProxy) closeClients() {
+	for _, client := range p.clients {
+		client.close()
+	}
+	p.clients = nil
+	clear(p.subscriptions)
+	if p.retryTimer != nil {
+		p.retryTimer.Stop()
+		p.retryTimer = nil
+	}
+}
+
+// FinishHandshake is called when the connected path with the node is established
+func (p *ApiProxy) FinishHandshake(result bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if !result || p.state != apiProxyStateConnecting {
+		return
+	}
+	logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(p.address)), "handle": p.meshprotocol.handle}).Info("ApiProxy upstream connection open")
+	p.Stats.GotHandle(p.meshprotocol.handle)
+	p.state = apiProxyStateHello
+	p.sendUpstream(newEspHomeHelloRequest())
+}
+
+// ForwardData receive the data coming from the node
+func (p *ApiProxy) ForwardData(data []byte) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.Stats.ReceivedBytes(len(data))
+	p.cache.Touch()
+	p.upstream.Write(data)
+	for {
+		frame, err := p.upstream.Next()
+		if err != nil {
+			return err
+		}
+		if frame == nil {
+			break
+		}
+		p.handleUpstreamFrame(frame)
+	}
+	return nil
+}
+
+// Close is called when the upstream session is lost
+func (p *ApiProxy) Close() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.closeUpstream()
+	p.upstreamFailed()
+}
+
+// Shutdown terminates the upstream session and disconnect all clients
+func (p *ApiProxy) Shutdown() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.closeUpstream()
+	p.closeClients()
+}
+
+// broadcast send the frame to the clients that sent the given request or subscription
+func (p *ApiProxy) broadcast(frame *EspHomeFrame, request uint32) {
+	for _, client := range p.clients {
+		if client.requests[request] {
+			p.writeClient(client, frame)
+		}
+	}
+}
+
+func (p *ApiProxy) writeClient(client *ApiProxyClient, frames ...*EspHomeFrame) {
+	if err := client.write(frames...); err != nil {
+		logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(p.address)), "err": err}).Warn("ApiProxy client write error")
+		client.close()
+	}
+}
+
+// answerTime reply to the time requests of the node, the clients are not involved
+func (p *ApiProxy) answerTime() {
+	payload := protowire.AppendTag(nil, 1, protowire.Fixed32Type)
+	payload = protowire.AppendFixed32(payload, uint32(time.Now().Unix()))
+	p.sendUpstream(NewEspHomeFrame(espHomeGetTimeResponse, payload))
+}
+
+func (p *ApiProxy) handleUpstreamFrame(frame *EspHomeFrame) {
+	switch frame.Type {
+	case espHomePingRequest:
+		p.sendUpstream(NewEspHomeFrame(espHomePingResponse, nil))
+		return
+	case espHomePingResponse:
+		return
+	case espHomeDisconnectRequest:
+		logger.WithField("node", utils.FmtNodeId(int64(p.address))).Info("ApiProxy node requested disconnection")
+		p.sendUpstream(NewEspHomeFrame(espHomeDisconnectResponse, nil))
+		p.closeUpstream()
+		p.upstreamFailed()
+		return
+	case espHomeDisconnectResponse:
+		return
+	case espHomeGetTimeRequest:
+		p.answerTime()
+		return
+	}
+
+	switch p.state {
+	case apiProxyStateHello:
+		if frame.Type == espHomeHelloResponse {
+			p.cache.SetHello(frame)
+			p.state = apiProxyStateConnect
+			for _, client := range p.clients {
+				p.answerPendingHello(client)
+			}
+			// Without a connect request the node waits the one of the first client
+			if p.connectRequest != nil {
+				p.sendUpstream(p.connectRequest)
+			}
+			return
+		}
+	case apiProxyStateConnect:
+		if frame.Type == espHomeConnectResponse {
+			if invalid, _ := frame.VarintField(espHomeInvalidPasswordFieldNum); invalid != 0 {
+				p.passwordRefused()
+				return
+			}
+			p.authenticated = true
+			p.cache.SetConnect(frame)
+			p.state = apiProxyStateDeviceInfo
+			p.sendUpstream(NewEspHomeFrame(espHomeDeviceInfoRequest, nil))
+			return
+		}
+	case apiProxyStateDeviceInfo:
+		if frame.Type == espHomeDeviceInfoResponse {
+			p.cache.SetDeviceInfo(frame)
+			p.entities = nil
+			p.state = apiProxyStateListEntities
+			p.sendUpstream(NewEspHomeFrame(espHomeListEntitiesRequest, nil))
+			return
+		}
+	case apiProxyStateListEntities:
+		if frame.Type == espHomeListEntitiesDoneResponse {
+			p.setReady()
+		} else {
+			p.entities = append(p.entities, frame)
+		}
+		return
+	}
+
+	if isEspHomeStateResponse(frame.Type) {
+		if key, ok := frame.StateKey(); ok {
+			p.cache.SetState(frame, key)
+		}
+		p.broadcast(frame, espHomeSubscribeStatesRequest)
+		return
+	}
+
+	if request, ok := espHomeResponseRequests[frame.Type]; ok {
+		p.broadcast(frame, request)
+		return
+	}
+
+	// The client of a reply without a known request can't be told, it's dropped
+	logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(p.address)), "type": frame.Type}).Debug("ApiProxy dropped unknown reply")
+}
+
+// requestConnect keep the first connect request of the clients, it's sent to the node to check
+// the password as soon as the node session is ready for it.
+func (p *ApiProxy) requestConnect(frame *EspHomeFrame) {
+	if p.connectRequest != nil {
+		return
+	}
+	p.connectRequest = frame
+	if p.state == apiProxyStateConnect {
+		p.sendUpstream(frame)
+	}
+}
+
+// passwordRefused disconnect the clients with the password refused by the node, the upstream
+// session is restarted with the password of the next client if there is one.
+func (p *ApiProxy) passwordRefused() {
+	logger.WithField("node", utils.FmtNodeId(int64(p.address))).Warn("ApiProxy node refused the client password")
+	refused := p.connectRequest
+	p.connectRequest = nil
+	p.authenticated = false
+	p.closeUpstream()
+	for _, client := range p.clients {
+		if client.connect != nil && bytes.Equal(client.connect.Payload, refused.Payload) {
+			p.writeClient(client, newEspHomeInvalidPasswordResponse())
+			client.close()
+		}
+	}
+	for _, client := range p.clients {
+		if !client.closed && client.connect != nil {
+			p.requestConnect(client.connect)
+			p.connectUpstream()
+			return
+		}
+	}
+}
+
+// checkPassword reports if the password of the client is the one accepted by the node, the answer
+// must wait when the node has not checked any password yet.
+func (p *ApiProxy) checkPassword(connect *EspHomeFrame) (checked bool, valid bool) {
+	if !p.authenticated {
+		return false, false
+	}
+	return true, bytes.Equal(connect.Payload, p.connectRequest.Payload)
+}
+
+func (p *ApiProxy) setReady() {
+	logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(p.address)), "entities": len(p.entities)}).Info("ApiProxy upstream session ready")
+	if p.readyTimer != nil {
+		p.readyTimer.Stop()
+		p.readyTimer = nil
+	}
+	p.state = apiProxyStateReady
+
+	changed := p.cache.SetEntities(p.entities)
+	p.entities = nil
+	if changed {
+		// Clients served from an outdated cache must reload the entities list
+		remaining := make([]*ApiProxyClient, 0, len(p.clients))
+		for _, client := range p.clients {
+			if client.stale {
+				client.close()
+			} else {
+				remaining = append(remaining, client)
+			}
+		}
+		p.clients = remaining
+	}
+	for _, client := range p.clients {
+		client.stale = false
+	}
+
+	p.sendUpstream(NewEspHomeFrame(espHomeSubscribeStatesRequest, nil))
+	for _, subscription := range p.subscriptions {
+		p.sendUpstream(subscription)
+	}
+	for _, command := range p.cache.PopCommands(p.cacheGrace) {
+		logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(p.address)), "type": command.Type}).Info("ApiProxy replay queued command")
+		p.sendUpstream(command)
+	}
+
+	for _, client := range p.clients {
+		p.answerPending(client)
+	}
+}
+
+// answerPendingHello reply to the hello requests waiting for the node, the clients can then send
+// the connect request with their password
+func (p *ApiProxy) answerPendingHello(client *ApiProxyClient) {
+	for len(client.pending) > 0 && client.pending[0].Type == espHomeHelloRequest {
+		frame := client.pending[0]
+		client.pending = client.pending[1:]
+		p.answerClientFrame(client, frame)
+	}
+}
+
+func (p *ApiProxy) answerPending(client *ApiProxyClient) {
+	pending := client.pending
+	client.pending = nil
+	for _, frame := range pending {
+		p.answerClientFrame(client, frame)
+	}
+}
+
+func (p *ApiProxy) handleClientFrame(client *ApiProxyClient, frame *EspHomeFrame) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	switch frame.Type {
+	case espHomePingRequest:
+		client.write(NewEspHomeFrame(espHomePingResponse, nil))
+		return
+	case espHomePingResponse:
+		return
+	case espHomeDisconnectRequest:
+		client.write(NewEspHomeFrame(espHomeDisconnectResponse, nil))
+		client.close()
+		return
+	}
+
+	if client.connect == nil && frame.Type != espHomeHelloRequest {
+		client.connect = NewEspHomeFrame(espHomeConnectRequest, nil)
+		if frame.Type == espHomeConnectRequest {
+			client.connect = frame
+		}
+		p.requestConnect(client.connect)
+	}
+
+	if p.state != apiProxyStateReady {
+		p.connectUpstream()
+	}
+
+	if !p.canServeFromCache() {
+		client.pending = append(client.pending, frame)
+		return
+	}
+
+	if p.state != apiProxyStateReady {
+		client.stale = true
+	}
+	p.answerClientFrame(client, frame)
+}
+
+func (p *ApiProxy) answerClientFrame(client *ApiProxyClient, frame *EspHomeFrame) {
+	var err error
+	switch frame.Type {
+	case espHomeHelloRequest, espHomeDeviceInfoRequest:
+		err = client.write(p.cache.HandshakeFrame(frame.Type))
+	case espHomeConnectRequest:
+		err = p.answerConnect(client, frame)
+	default:
+		if p.authorize(client, frame) {
+			err = p.answerRequest(client, frame)
+		}
+	}
+
+	if err != nil {
+		logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(p.address)), "err": err}).Warn("ApiProxy client write error")
+		client.close()
+	}
+}
+
+// answerConnect reply from the cache to a connect request with the password accepted by the node
+func (p *ApiProxy) answerConnect(client *ApiProxyClient, frame *EspHomeFrame) error {
+	checked, valid := p.checkPassword(frame)
+	if !checked {
+		client.pending = append(client.pending, frame)
+		return nil
+	}
+	if !valid {
+		logger.WithField("node", utils.FmtNodeId(int64(p.address))).Warn("ApiProxy client sent an invalid password")
+		return client.write(newEspHomeInvalidPasswordResponse())
+	}
+	client.authenticated = true
+	return client.write(p.cache.HandshakeFrame(frame.Type))
+}
+
+// authorize reports if the request of the client can be answered, the requests of the clients that
+// skipped the connect request are answered if the node has no password
+func (p *ApiProxy) authorize(client *ApiProxyClient, frame *EspHomeFrame) bool {
+	if client.authenticated {
+		return true
+	}
+	checked, valid := p.checkPassword(client.connect)
+	if !checked {
+		client.pending = append(client.pending, frame)
+		return false
+	}
+	if !valid {
+		logger.WithField("node", utils.FmtNodeId(int64(p.address))).Warn("ApiProxy client not authenticated, closing")
+		client.close()
+		return false
+	}
+	client.authenticated = true
+	return true
+}
+
+func (p *ApiProxy) answerRequest(client *ApiProxyClient, frame *EspHomeFrame) error {
+	switch frame.Type {
+	case espHomeListEntitiesRequest:
+		frames := append(p.cache.EntitiesFrames(), NewEspHomeFrame(espHomeListEntitiesDoneResponse, nil))
+		return client.write(frames...)
+	case espHomeSubscribeStatesRequest:
+		client.requests[frame.Type] = true
+		if p.state == apiProxyStateReady {
+			return client.write(p.cache.StatesFrames()...)
+		}
+		return client.write(p.cache.StaleStatesFrames()...)
+	case espHomeSubscribeLogsRequest, espHomeSubscribeHassServices, espHomeSubscribeHassStates:
+		client.requests[frame.Type] = true
+		if _, ok := p.subscriptions[frame.Type]; !ok {
+			p.subscriptions[frame.Type] = frame
+			if p.state == apiProxyStateReady {
+				p.sendUpstream(frame)
+			}
+		}
+	default:
+		client.requests[frame.Type] = true
+		if p.state == apiProxyStateReady {
+			p.sendUpstream(frame)
+		} else if isEspHomeCommandRequest(frame.Type) {
+			logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(p.address)), "type": frame.Type}).Info("ApiProxy node unreachable, command queued")
+			p.cache.QueueCommand(frame)
+		}
+	}
+	return nil
+}
+
+// AddClient attach a new client socket to the proxy and start the upstream session if needed
+func (p *ApiProxy) AddClient(socket net.Conn) {
+	client := &ApiProxyClient{proxy: p, socket: socket, out: make(chan []byte, apiProxyClientQueueSize), requests: make(map[uint32]bool)}
+
+	p.lock.Lock()
+	p.clients = append(p.clients, client)
+	logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(p.address)), "clients": len(p.clients)}).Debug("ApiProxy added new client")
+	p.lock.Unlock()
+
+	go client.writeLoop()
+	go client.read()
+}
+
+// RemoveClient detach a client from the proxy, the upstream session is closed with the last client
+func (p *ApiProxy) RemoveClient(client *ApiProxyClient) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	client.close()
+	idx := slices.Index(p.clients, client)
+	if idx >= 0 {
+		p.clients = append(p.clients[:idx], p.clients[idx+1:]...)
+	}
+	logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(p.address)), "clients": len(p.clients)}).Debug("ApiProxy removed client")
+
+	if len(p.clients) == 0 {
+		if p.state != apiProxyStateIdle {
+			p.sendUpstream(NewEspHomeFrame(espHomeDisconnectRequest, nil))
+			p.closeUpstream()
+		}
+		p.closeClients()
+	}
+}
+
+func NewApiProxy(serial *SerialConnection, address MeshNodeId, port int, cache *EspHomeNodeCache, cacheGrace time.Duration) *ApiProxy {
+	return &ApiProxy{
+		serial:        serial,
+		address:       address,
+		port:          port,
+		state:         apiProxyStateIdle,
+		Stats:         _allStats.Stats(address),
+		cache:         cache,
+		cacheGrace:    cacheGrace,
+		subscriptions: make(map[uint32]*EspHomeFrame),
+	}
+}
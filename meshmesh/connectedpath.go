@@ -29,11 +29,14 @@ const (
 )
 
 type ConnPathConnection struct {
-	//address   MeshNodeId
+	address   MeshNodeId
+	port      uint16
 	connState uint8
 	serial    *SerialConnection
 	handle    uint16
 	sequence  uint16
+	// alternatives are the routes left to try when the connection is refused, nil until the first refusal
+	alternatives [][]int64
 }
 
 func ParseAddress(address string) (MeshNodeId, error) {
@@ -136,14 +139,40 @@ func (client *ConnPathConnection) OpenConnectionAsync(addr MeshNodeId, port uint
 		return errors.New("speak with local node is not yet supported")
 	}
 
+	client.address, client.port, client.alternatives = addr, port, nil
+	client.connState = connPathConnectionStateHandshakeStarted
+	return client.sendOpenConnection(_path)
+}
+
+// retryAlternativeRoute open the connection again on the next alternative route of the node,
+// it returns false when there are no more routes to try
+func (client *ConnPathConnection) retryAlternativeRoute() bool {
+	if client.alternatives == nil {
+		client.alternatives = append(alternativePaths(graph.GetMainNetwork(), client.address), nil)
+	}
+	path := client.alternatives[0]
+	if path == nil {
+		return false
+	}
+	client.alternatives = client.alternatives[1:]
+
+	logger.WithFields(logger.Fields{"addr": utils.FmtNodeId(int64(client.address)), "handle": client.handle, "path": utils.FmtPath2Str(path)}).
+		Warn("ConnPathConnection retry on an alternative route")
+	if err := client.sendOpenConnection(path); err != nil {
+		return false
+	}
+	return true
+}
+
+// sendOpenConnection ask the opening of the connection following path, the path starts with the local node
+func (client *ConnPathConnection) sendOpenConnection(_path []int64) error {
 	_path = _path[1:]
 	path := make([]int32, len(_path))
 	for i, item := range _path {
 		path[i] = int32(item)
 	}
 
-	client.connState = connPathConnectionStateHandshakeStarted
-	err = client.serial.SendApi(
+	err := client.serial.SendApi(
 		ConnectedPathApiRequest2{
 			Protocol: meshmeshProtocolConnectedPath,
 			Command:  connectedPathOpenConnectionRequest,
@@ -151,7 +180,7 @@ func (client *ConnPathConnection) OpenConnectionAsync(addr MeshNodeId, port uint
 			Dummy:    0,
 			Sequence: client.getNextSequence(),
 			DataSize: uint16(len(path)*4 + 3),
-			Port:     port,
+			Port:     client.port,
 			PathLen:  uint8(len(path)),
 			Path:     path,
 		},
@@ -187,6 +216,9 @@ func (client *ConnPathConnection) handleIncomingOpenConnAck(_ *ConnectedPathApiR
 
 func (client *ConnPathConnection) handleIncomingOpenConnNack(v *ConnectedPathApiReply) {
 	logger.WithFields(logger.Fields{"handle": v.Handle}).Error("nack during opening connection")
+	if client.connState == connPathConnectionStateHandshakeStarted && client.retryAlternativeRoute() {
+		return
+	}
 	client.connState = connPathConnectionStateInvalid
 }
 
@@ -1,10 +1,15 @@
 package meshmesh
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"math"
+	"os"
+	"sync"
 	"time"
 
+	"golang.org/x/exp/slices"
 	"leguru.net/m/v2/logger"
 	"leguru.net/m/v2/utils"
 
@@ -21,45 +26,253 @@ const (
 	DiscoveryProcedureStateDiscovering
 	DiscoveryProcedureStateDone
 	DiscoveryProcedureStateError
+	DiscoveryProcedureStatePaused
+	DiscoveryProcedureStateCancelled
 )
 
+func (s DiscoveryProcedureState) String() string {
+	switch s {
+	case DiscoveryProcedureStateIdle:
+		return "idle"
+	case DiscoveryProcedureStateRun:
+		return "running"
+	case DiscoveryProcedureStateDiscovering:
+		return "discovering"
+	case DiscoveryProcedureStateDone:
+		return "done"
+	case DiscoveryProcedureStateError:
+		return "error"
+	case DiscoveryProcedureStatePaused:
+		return "paused"
+	case DiscoveryProcedureStateCancelled:
+		return "cancelled"
+	}
+	return "unknown"
+}
+
+// DiscoveryProgress is a snapshot of the discovery procedure progress
+type DiscoveryProgress struct {
+	State           DiscoveryProcedureState
+	CurrentDeviceId int64
+	Repeat          int
+	Done            int
+	Total           int
+	Eta             time.Duration
+	Targets         []int64
+	Error           string
+}
+
+// discoveryProgressFile is the progress of a discovery written to disk after every discovered node
+type discoveryProgressFile struct {
+	Refresh   bool                            `json:"refresh"`
+	Targets   []int64                         `json:"targets,omitempty"`
+	Visited   []int64                         `json:"visited"`
+	Neighbors map[int64]map[int64]discWeights `json:"neighbors"`
+	Tags      map[int64]string                `json:"tags"`
+	Busy      time.Duration                   `json:"busy"`
+	Steps     int                             `json:"steps"`
+	Params    DiscoveryParams                 `json:"params"`
+}
+
 type DiscoveryProcedure struct {
+	lock            sync.Mutex
+	resumeCond      *sync.Cond
 	serial          *SerialConnection
 	network         *gra.Network
+	refresh         bool
 	currentDeviceId int64
 	Neighbors       map[int64]discWeights
 	state           DiscoveryProcedureState
 	repeat          int
+	running         bool
+	paused          bool
+	cancelled       bool
+	cancel          chan struct{}
+	restored        bool
+	err             error
+	// targets restrict the discovery to the listed nodes, nil discover the whole network
+	targets     []int64
+	targetIndex int
+	results     map[int64]map[int64]discWeights
+	visited     []int64
+	total       int
+	// busy is the time spent in the completed steps, used to estimate the remaining time
+	busy         time.Duration
+	steps        int
+	progressFile string
+	params       DiscoveryParams
+	// samples are the weights measured in each repetition of the current node
+	samples map[int64][]float64
+	// run identify the rssi samples of the repetitions of the current node in the history
+	run int64
 }
 
 func (d *DiscoveryProcedure) State() DiscoveryProcedureState {
+	d.lock.Lock()
+	defer d.lock.Unlock()
 	return d.state
 }
 
 func (d *DiscoveryProcedure) StateString() string {
-	switch d.state {
-	case DiscoveryProcedureStateIdle:
-		return "idle"
-	case DiscoveryProcedureStateRun:
-		return "running"
-	case DiscoveryProcedureStateDiscovering:
-		return "discovering"
-	case DiscoveryProcedureStateDone:
-		return "done"
-	case DiscoveryProcedureStateError:
-		return "error"
-	}
-	return "unknown"
+	return d.State().String()
+}
+
+func (d *DiscoveryProcedure) setState(state DiscoveryProcedureState) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.state = state
 }
 
 func (d *DiscoveryProcedure) CurrentDeviceId() int64 {
+	d.lock.Lock()
+	defer d.lock.Unlock()
 	return d.currentDeviceId
 }
 
 func (d *DiscoveryProcedure) CurrentRepeat() int {
+	d.lock.Lock()
+	defer d.lock.Unlock()
 	return d.repeat
 }
 
+func (d *DiscoveryProcedure) IsRunning() bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.running
+}
+
+func (d *DiscoveryProcedure) Params() DiscoveryParams {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.params
+}
+
+// SetParams change the parameters of a procedure not yet started
+func (d *DiscoveryProcedure) SetParams(params DiscoveryParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.running {
+		return errors.New("discovery procedure already running")
+	}
+	d.params = params
+	return nil
+}
+
+// Targets returns the nodes of a targeted discovery, nil for a full discovery
+func (d *DiscoveryProcedure) Targets() []int64 {
+	return d.targets
+}
+
+// NeighborsSnapshot returns a copy of the neighbors table of the current node
+func (d *DiscoveryProcedure) NeighborsSnapshot() map[int64]discWeights {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	neighbors := make(map[int64]discWeights, len(d.Neighbors))
+	for id, w := range d.Neighbors {
+		neighbors[id] = w
+	}
+	return neighbors
+}
+
+func (d *DiscoveryProcedure) Progress() DiscoveryProgress {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	progress := DiscoveryProgress{
+		State:           d.state,
+		CurrentDeviceId: d.currentDeviceId,
+		Repeat:          d.repeat,
+		Done:            len(d.visited),
+		Total:           max(d.total, len(d.visited)),
+		Targets:         d.targets,
+	}
+	if d.err != nil {
+		progress.Error = d.err.Error()
+	}
+	if d.steps > 0 && progress.Total > progress.Done {
+		remaining := (progress.Total-progress.Done)*d.params.Repetitions - d.repeat
+		progress.Eta = (d.busy / time.Duration(d.steps) * time.Duration(remaining)).Round(time.Second)
+	}
+	return progress
+}
+
+// Pause stop the procedure before the next step
+func (d *DiscoveryProcedure) Pause() error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if !d.running || d.cancelled {
+		return errors.New("discovery procedure is not running")
+	}
+	d.paused = true
+	d.state = DiscoveryProcedureStatePaused
+	return nil
+}
+
+// Resume continue a paused procedure, or restart one stopped by an error or restored from disk
+func (d *DiscoveryProcedure) Resume() error {
+	d.lock.Lock()
+	if d.running {
+		defer d.lock.Unlock()
+		if !d.paused {
+			return errors.New("discovery procedure is not paused")
+		}
+		d.paused = false
+		d.state = DiscoveryProcedureStateRun
+		d.resumeCond.Broadcast()
+		return nil
+	}
+	if d.state != DiscoveryProcedureStatePaused && d.state != DiscoveryProcedureStateError {
+		d.lock.Unlock()
+		return errors.New("discovery procedure can't be resumed")
+	}
+	d.restored = true
+	d.paused = false
+	d.lock.Unlock()
+	return StartDiscovery(d)
+}
+
+// Cancel abort the procedure discarding the progress
+func (d *DiscoveryProcedure) Cancel() error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if !d.running {
+		if d.state == DiscoveryProcedureStatePaused || d.state == DiscoveryProcedureStateError {
+			d.state = DiscoveryProcedureStateCancelled
+			d.removeProgress()
+			return nil
+		}
+		return errors.New("discovery procedure is not running")
+	}
+	if !d.cancelled {
+		d.cancelled = true
+		close(d.cancel)
+		d.resumeCond.Broadcast()
+	}
+	return nil
+}
+
+// checkpoint wait while the procedure is paused and returns false if it was cancelled
+func (d *DiscoveryProcedure) checkpoint() bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	for d.paused && !d.cancelled {
+		d.resumeCond.Wait()
+	}
+	return !d.cancelled
+}
+
+// sleep wait the given time or until the procedure is cancelled
+func (d *DiscoveryProcedure) sleep(duration time.Duration) error {
+	select {
+	case <-time.After(duration):
+		return nil
+	case <-d.cancel:
+		return errors.New("discovery procedure cancelled")
+	}
+}
+
 type discWeights struct {
 	Current float64
 	Next    float64
@@ -68,10 +281,15 @@ type discWeights struct {
 const esp32RssiMax = -40
 const esp32RssiMin = -80
 
+// Rssi2weight convert the rssi to an edge weight using the esp32 range
 func Rssi2weight(rssi int16) float64 {
+	return rssi2weightRange(rssi, esp32RssiMin, esp32RssiMax)
+}
+
+func rssi2weightRange(rssi int16, rssiMin int16, rssiMax int16) float64 {
 	percent := 0.0
 	if rssi <= 0 {
-		percent = float64(esp32RssiMin-rssi) / float64(esp32RssiMin-esp32RssiMax)
+		percent = float64(rssiMin-rssi) / float64(rssiMin-rssiMax)
 	} else if rssi > 44 {
 		percent = float64(rssi) / 45.0
 		rssi = 44
@@ -113,25 +331,22 @@ func _neighborsAdavance(w map[int64]discWeights) {
 }
 
 func neighborsToGraph(g *gra.Network, nodeId int64, w map[int64]discWeights) {
-	nodes := g.From(nodeId)
-
-	for nodes.Next() {
-		neighbor := nodes.Node().(gra.NodeDevice)
-		g.RemoveEdge(nodeId, neighbor.ID())
-	}
+	g.RemoveDiscoveredEdges(nodeId)
 
 	for id, d := range w {
 		logger.WithFields(logger.Fields{"to": utils.FmtNodeId(id), "weight": d, "exists": g.NodeIdExists(id)}).
 			Infof("[%s] Neighbor to graph", utils.FmtNodeId(nodeId))
-		g.ChangeEdgeWeight(nodeId, id, d.Next, d.Next)
+		if !g.SetDiscoveredEdgeWeight(nodeId, id, d.Next) {
+			logger.WithField("to", utils.FmtNodeId(id)).Debugf("[%s] Pinned link not changed", utils.FmtNodeId(nodeId))
+		}
 	}
 }
 
-func _updateNeighbor(w map[int64]discWeights, id int64, rssi1, rssi2 float64) error {
+func _updateNeighbor(w map[int64]discWeights, id int64, weight float64) error {
 	if _, exists := w[id]; exists {
-		w[id] = discWeights{Current: w[id].Current, Next: math.Min(rssi1, rssi2)}
+		w[id] = discWeights{Current: w[id].Current, Next: weight}
 	} else {
-		w[id] = discWeights{Current: 1.0, Next: math.Min(rssi1, rssi2)}
+		w[id] = discWeights{Current: 1.0, Next: weight}
 	}
 	return nil
 }
@@ -145,7 +360,7 @@ func _findNextNode(g *gra.Network) gra.NodeDevice {
 		if dev.Device().InUse() && !dev.Device().Discovered() {
 			path, weight, err := g.GetPath(dev)
 
-			if weight < found_weight {
+			if err == nil && weight < found_weight {
 				found_weight = weight
 				found_node = dev
 			}
@@ -157,14 +372,19 @@ func _findNextNode(g *gra.Network) gra.NodeDevice {
 }
 
 func (d *DiscoveryProcedure) InitStep() error {
-	d.state = DiscoveryProcedureStateRun
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if !d.paused {
+		d.state = DiscoveryProcedureStateRun
+	}
 
 	if d.network == nil {
 		d.network = gra.NewNetwork(int64(d.serial.LocalNode))
+		d.network.InheritLinks(gra.GetMainNetwork())
 	}
 
 	if d.currentDeviceId != 0 {
-		if d.repeat < maxRepetitions {
+		if d.repeat < d.params.Repetitions {
 			// Repeat same node
 			return nil
 		} else {
@@ -173,7 +393,13 @@ func (d *DiscoveryProcedure) InitStep() error {
 	}
 
 	if d.currentDeviceId == 0 {
-		d.currentDeviceId = _findNextNode(d.network).ID()
+		if d.targets == nil {
+			d.currentDeviceId = _findNextNode(d.network).ID()
+			d.total = len(d.visited) + _countUndiscovered(d.network)
+		} else if d.targetIndex < len(d.targets) {
+			d.currentDeviceId = d.targets[d.targetIndex]
+			d.targetIndex++
+		}
 	}
 
 	if d.currentDeviceId == 0 {
@@ -182,6 +408,8 @@ func (d *DiscoveryProcedure) InitStep() error {
 	}
 
 	d.Neighbors = make(map[int64]discWeights)
+	d.samples = make(map[int64][]float64)
+	d.run = time.Now().UnixNano()
 
 	node, err := d.network.GetNodeDevice(d.currentDeviceId)
 	if err != nil {
@@ -193,22 +421,43 @@ func (d *DiscoveryProcedure) InitStep() error {
 	return nil
 }
 
+func _countUndiscovered(g *gra.Network) int {
+	count := 0
+	nodes := g.Nodes()
+	for nodes.Next() {
+		dev := nodes.Node().(gra.NodeDevice)
+		if dev.Device().InUse() && !dev.Device().Discovered() {
+			count++
+		}
+	}
+	return count
+}
+
 func (d *DiscoveryProcedure) Step() error {
-	protocol := FindBestProtocol(MeshNodeId(d.currentDeviceId), d.network)
-	logger.Log().Printf("[%s] Start discover with protocol %d repetition %d", utils.FmtNodeId(d.currentDeviceId), protocol, d.repeat)
+	started := time.Now()
+	d.lock.Lock()
+	currentDeviceId, repeat, params, run := d.currentDeviceId, d.repeat, d.params, d.run
+	neighbors := make(map[int64]discWeights, len(d.Neighbors))
+	for id, w := range d.Neighbors {
+		neighbors[id] = w
+	}
+	d.lock.Unlock()
+
+	protocol := FindBestProtocol(MeshNodeId(currentDeviceId), d.network)
+	logger.Log().Printf("[%s] Start discover with protocol %d repetition %d", utils.FmtNodeId(currentDeviceId), protocol, repeat)
 
-	_, err := d.serial.SendReceiveApiProt(DiscResetTableApiRequest{}, protocol, MeshNodeId(d.currentDeviceId), d.network)
+	_, err := d.serial.SendReceiveApiProt(DiscResetTableApiRequest{}, protocol, MeshNodeId(currentDeviceId), d.network)
 	if err != nil {
 		return err
 	}
 
-	_, err = d.serial.SendReceiveApiProt(DiscStartDiscoverApiRequest{Mask: 0, Filter: 0, Slotnum: 100}, protocol, MeshNodeId(d.currentDeviceId), d.network)
+	_, err = d.serial.SendReceiveApiProt(DiscStartDiscoverApiRequest{Mask: params.Mask, Filter: params.Filter, Slotnum: params.Slots}, protocol, MeshNodeId(currentDeviceId), d.network)
 	if err != nil {
 		return err
 	}
 
 	// Get tag string from device and if the graph description is empty set the same as the tag on the device.
-	_reply, err := d.serial.SendReceiveApiProt(NodeGetTagApiRequest{}, protocol, MeshNodeId(d.currentDeviceId), d.network)
+	_reply, err := d.serial.SendReceiveApiProt(NodeGetTagApiRequest{}, protocol, MeshNodeId(currentDeviceId), d.network)
 	if err != nil {
 		return err
 	}
@@ -216,10 +465,10 @@ func (d *DiscoveryProcedure) Step() error {
 	if !ok {
 		return errors.New("strcuture is not a NodeGetTagApiReply")
 	} else {
-		logger.Log().Printf("[%s] Tag: %s", utils.FmtNodeId(d.currentDeviceId), tagReply.Tag)
+		logger.Log().Printf("[%s] Tag: %s", utils.FmtNodeId(currentDeviceId), tagReply.Tag)
 	}
 
-	_device, err := d.network.GetNodeDevice(d.currentDeviceId)
+	_device, err := d.network.GetNodeDevice(currentDeviceId)
 	if err != nil {
 		return err
 	}
@@ -228,9 +477,11 @@ func (d *DiscoveryProcedure) Step() error {
 		_device.Device().SetTag(utils.TruncateZeros(tagReply.Tag))
 	}
 
-	time.Sleep(5 * time.Second)
+	if err = d.sleep(params.Wait); err != nil {
+		return err
+	}
 
-	reply1, err := d.serial.SendReceiveApiProt(DiscTableSizeApiRequest{}, protocol, MeshNodeId(d.currentDeviceId), d.network)
+	reply1, err := d.serial.SendReceiveApiProt(DiscTableSizeApiRequest{}, protocol, MeshNodeId(currentDeviceId), d.network)
 	if err != nil {
 		return err
 	}
@@ -239,11 +490,14 @@ func (d *DiscoveryProcedure) Step() error {
 		return errors.New("comunication error")
 	}
 
-	_neighborsAdavance(d.Neighbors)
-	logger.Log().Printf("[%s] Discovered nodes: %d", utils.FmtNodeId(d.currentDeviceId), tableSize.Size)
+	_neighborsAdavance(neighbors)
+	// The weight profiles are selected by the node settings of the main network
+	main := gra.GetMainNetwork()
+	measured := make(map[int64]float64)
+	logger.Log().Printf("[%s] Discovered nodes: %d", utils.FmtNodeId(currentDeviceId), tableSize.Size)
 	for i := uint8(0); i < tableSize.Size; i++ {
 
-		reply1, err = d.serial.SendReceiveApiProt(DiscTableItemGetApiRequest{Index: i}, protocol, MeshNodeId(d.currentDeviceId), d.network)
+		reply1, err = d.serial.SendReceiveApiProt(DiscTableItemGetApiRequest{Index: i}, protocol, MeshNodeId(currentDeviceId), d.network)
 		if err != nil {
 			return err
 		}
@@ -253,8 +507,26 @@ func (d *DiscoveryProcedure) Step() error {
 		}
 
 		logger.Log().Printf("         %d: [%s] rssi1 %d rssi2 %d", i, utils.FmtNodeId(int64(tableItem.NodeId)), tableItem.Rssi1, tableItem.Rssi2)
-		_updateNeighbor(d.Neighbors, int64(tableItem.NodeId), Rssi2weight(tableItem.Rssi1), Rssi2weight(tableItem.Rssi2))
+		sample := RssiSample{Time: time.Now(), Rssi1: tableItem.Rssi1, Rssi2: tableItem.Rssi2, RssiMin: params.RssiMin, RssiMax: params.RssiMax,
+			Run: run, Aggregation: params.Aggregation}
+		measured[int64(tableItem.NodeId)] = sampleRssi2weight(main, currentDeviceId, int64(tableItem.NodeId), sample)
+		GetRssiHistory().Add(MeshNodeId(currentDeviceId), MeshNodeId(tableItem.NodeId), sample)
+	}
+
+	d.lock.Lock()
+	for id, weight := range measured {
+		d.samples[id] = append(d.samples[id], weight)
+	}
+	for id, samples := range d.samples {
+		if _, ok := measured[id]; !ok && params.Aggregation == DiscoveryAggregationLast {
+			continue
+		}
+		_updateNeighbor(neighbors, id, params.aggregate(samples))
 	}
+	d.Neighbors = neighbors
+	d.busy += time.Since(started)
+	d.steps++
+	d.lock.Unlock()
 	return err
 }
 
@@ -266,10 +538,12 @@ func (d *DiscoveryProcedure) Clear() {
 			node.Device().SetDiscovered(false)
 		}
 	}
-	d.state = DiscoveryProcedureStateIdle
+	d.setState(DiscoveryProcedureStateIdle)
 }
 
 func (d *DiscoveryProcedure) Save() error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
 	if d.currentDeviceId == 0 {
 		return errors.New("discovery is inactive")
 	}
@@ -282,28 +556,304 @@ func (d *DiscoveryProcedure) Save() error {
 	d.repeat++
 	node.Device().SetDiscovered(true)
 	neighborsToGraph(d.network, d.currentDeviceId, d.Neighbors)
+	d.results[d.currentDeviceId] = d.Neighbors
+	if d.repeat >= d.params.Repetitions && !slices.Contains(d.visited, d.currentDeviceId) {
+		d.visited = append(d.visited, d.currentDeviceId)
+		d.saveProgress()
+	}
 	return nil
 }
 
+// merge copy the neighbors of the discovered targets into the network leaving the other nodes untouched
+func (d *DiscoveryProcedure) merge(network *gra.Network) {
+	for id, neighbors := range d.results {
+		neighborsToGraph(network, id, neighbors)
+		node, err := network.GetNodeDevice(id)
+		if err != nil {
+			continue
+		}
+		node.Device().SetDiscovered(true)
+		if discovered, err := d.network.GetNodeDevice(id); err == nil && len(node.Device().Tag()) == 0 {
+			node.Device().SetTag(discovered.Device().Tag())
+		}
+	}
+	logger.WithField("nodes", len(d.results)).Info("Targeted discovery merged in network")
+}
+
+// saveProgress must be called with the lock held
+func (d *DiscoveryProcedure) saveProgress() {
+	if d.progressFile == "" {
+		return
+	}
+	progress := discoveryProgressFile{
+		Refresh:   d.refresh,
+		Targets:   d.targets,
+		Visited:   d.visited,
+		Neighbors: make(map[int64]map[int64]discWeights),
+		Tags:      make(map[int64]string),
+		Busy:      d.busy,
+		Steps:     d.steps,
+		Params:    d.params,
+	}
+	for _, id := range d.visited {
+		progress.Neighbors[id] = d.results[id]
+		if node, err := d.network.GetNodeDevice(id); err == nil {
+			progress.Tags[id] = node.Device().Tag()
+		}
+	}
+	data, err := json.MarshalIndent(progress, "", "  ")
+	if err == nil {
+		err = os.WriteFile(d.progressFile, data, 0644)
+	}
+	if err != nil {
+		logger.WithFields(logger.Fields{"file": d.progressFile, "err": err}).Error("Can't save discovery progress")
+	}
+}
+
+func (d *DiscoveryProcedure) removeProgress() {
+	if d.progressFile != "" {
+		os.Remove(d.progressFile)
+	}
+}
+
+// prepare reset the discovered flags of the nodes that the procedure will visit
+func (d *DiscoveryProcedure) prepare() {
+	if d.targets == nil {
+		d.Clear()
+	} else {
+		for _, id := range d.targets {
+			if node, err := d.network.GetNodeDevice(id); err == nil {
+				node.Device().SetDiscovered(false)
+			}
+		}
+		d.setState(DiscoveryProcedureStateIdle)
+	}
+}
+
 func (d *DiscoveryProcedure) Run() {
-	d.Clear()
-	for d.state != DiscoveryProcedureStateDone && d.state != DiscoveryProcedureStateError {
+	d.lock.Lock()
+	restored := d.restored
+	d.err = nil
+	d.lock.Unlock()
+	if !restored {
+		d.prepare()
+	}
+
+	for d.checkpoint() {
 		d.InitStep()
-		if d.state == DiscoveryProcedureStateRun {
+		state := d.State()
+		if state == DiscoveryProcedureStateDone {
+			break
+		}
+		if state == DiscoveryProcedureStateRun {
 			err := d.Step()
 			if err != nil {
+				d.lock.Lock()
+				if d.cancelled {
+					d.lock.Unlock()
+					break
+				}
 				d.state = DiscoveryProcedureStateError
+				d.err = err
+				d.lock.Unlock()
 				logger.Log().Println("Discovery procedure error", err)
+				break
 			} else {
 				d.Save()
 			}
 		}
 	}
 
-	gra.SetMainNetwork(d.network)
+	d.lock.Lock()
+	d.running = false
+	if d.cancelled {
+		d.state = DiscoveryProcedureStateCancelled
+		d.removeProgress()
+		d.lock.Unlock()
+		logger.WithField("nodes", len(d.visited)).Info("Discovery procedure cancelled")
+		return
+	}
+	if d.state == DiscoveryProcedureStateDone {
+		d.removeProgress()
+	}
+	dryRun := d.params.DryRun
+	d.lock.Unlock()
+
+	if dryRun {
+		proposed := d.network
+		if d.targets != nil {
+			proposed = gra.GetMainNetwork().CopyNetwork()
+			d.merge(proposed)
+		} else {
+			proposed.InheritDeviceSettings(gra.GetMainNetwork())
+		}
+		setDiscoveryProposal(newDiscoveryProposal(proposed, d.Params().WeightThreshold))
+		return
+	}
+
+	if d.targets == nil {
+		d.network.InheritDeviceSettings(gra.GetMainNetwork())
+		gra.SetMainNetwork(d.network)
+	} else {
+		d.merge(gra.GetMainNetwork())
+	}
 	gra.NotifyMainNetworkChanged()
 }
 
 func NewDiscoveryProcedure(serial *SerialConnection, network *gra.Network, nodeid int64) *DiscoveryProcedure {
-	return &DiscoveryProcedure{serial: serial, network: network, currentDeviceId: 0, state: DiscoveryProcedureStateIdle, repeat: 0}
+	d := &DiscoveryProcedure{serial: serial, network: network, refresh: network != nil, currentDeviceId: 0, state: DiscoveryProcedureStateIdle, repeat: 0}
+	d.resumeCond = sync.NewCond(&d.lock)
+	d.cancel = make(chan struct{})
+	d.results = make(map[int64]map[int64]discWeights)
+	d.params = DefaultDiscoveryParams()
+	return d
+}
+
+// NewTargetedDiscoveryProcedure rediscover only the listed nodes, with subtree also the nodes routed through them
+func NewTargetedDiscoveryProcedure(serial *SerialConnection, nodes []int64, subtree bool) (*DiscoveryProcedure, error) {
+	network := gra.GetMainNetwork().CopyNetwork()
+	targets := make([]int64, 0, len(nodes))
+	for _, id := range nodes {
+		ids := []int64{id}
+		if subtree {
+			var err error
+			ids, err = network.RoutingDescendants(id)
+			if err != nil {
+				return nil, err
+			}
+		} else if !network.NodeIdExists(id) {
+			return nil, fmt.Errorf("node %s not found in network graph", utils.FmtNodeId(id))
+		}
+		for _, target := range ids {
+			if !slices.Contains(targets, target) {
+				targets = append(targets, target)
+			}
+		}
+	}
+	if len(targets) == 0 {
+		return nil, errors.New("no nodes to discover")
+	}
+
+	d := NewDiscoveryProcedure(serial, network, 0)
+	d.targets = targets
+	d.total = len(targets)
+	return d, nil
+}
+
+// newDiscoveryProcedureFromProgress rebuild a procedure interrupted by a restart of the hub, the
+// visited nodes are applied again to the graph and the procedure is left paused.
+func newDiscoveryProcedureFromProgress(serial *SerialConnection, progress *discoveryProgressFile) *DiscoveryProcedure {
+	var network *gra.Network
+	if progress.Refresh || progress.Targets != nil {
+		network = gra.GetMainNetwork().CopyNetwork()
+	}
+	d := NewDiscoveryProcedure(serial, network, 0)
+	d.refresh = progress.Refresh
+	d.targets = progress.Targets
+	d.total = len(progress.Targets)
+	d.busy = progress.Busy
+	d.steps = progress.Steps
+	if progress.Params.Validate() == nil {
+		d.params = progress.Params
+	}
+	if d.network == nil {
+		d.network = gra.NewNetwork(int64(serial.LocalNode))
+		d.network.InheritLinks(gra.GetMainNetwork())
+	}
+	d.prepare()
+
+	for _, id := range progress.Visited {
+		neighbors, ok := progress.Neighbors[id]
+		if !ok {
+			continue
+		}
+		neighborsToGraph(d.network, id, neighbors)
+		if node, err := d.network.GetNodeDevice(id); err == nil {
+			node.Device().SetDiscovered(true)
+			if len(node.Device().Tag()) == 0 {
+				node.Device().SetTag(progress.Tags[id])
+			}
+		}
+		d.results[id] = neighbors
+		d.visited = append(d.visited, id)
+		if d.targets != nil {
+			d.targetIndex = max(d.targetIndex, slices.Index(d.targets, id)+1)
+		}
+	}
+	d.state = DiscoveryProcedureStatePaused
+	return d
+}
+
+var _discovery struct {
+	lock     sync.Mutex
+	current  *DiscoveryProcedure
+	filename string
+}
+
+// CurrentDiscovery returns the last started discovery procedure
+func CurrentDiscovery() *DiscoveryProcedure {
+	_discovery.lock.Lock()
+	defer _discovery.lock.Unlock()
+	return _discovery.current
+}
+
+// StartDiscovery run the procedure in background, only one discovery at time can be running
+func StartDiscovery(d *DiscoveryProcedure) error {
+	_discovery.lock.Lock()
+	defer _discovery.lock.Unlock()
+	if _discovery.current != nil && _discovery.current != d && _discovery.current.IsRunning() {
+		return errors.New("discovery procedure already running")
+	}
+	d.lock.Lock()
+	if d.running {
+		d.lock.Unlock()
+		return errors.New("discovery procedure already running")
+	}
+	d.running = true
+	d.progressFile = _discovery.filename
+	d.lock.Unlock()
+
+	_discovery.current = d
+	go d.Run()
+	return nil
+}
+
+// LoadDiscoveryProgress set the file where the discovery progress is persisted and restore
+// in paused state a procedure that was interrupted.
+func LoadDiscoveryProgress(serial *SerialConnection, filename string) {
+	_discovery.lock.Lock()
+	defer _discovery.lock.Unlock()
+	_discovery.filename = filename
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return
+	}
+	progress := discoveryProgressFile{}
+	if err = json.Unmarshal(data, &progress); err != nil {
+		logger.WithFields(logger.Fields{"file": filename, "err": err}).Warn("Invalid discovery progress file, ignoring it")
+		return
+	}
+
+	d := newDiscoveryProcedureFromProgress(serial, &progress)
+	d.progressFile = filename
+	_discovery.current = d
+	logger.WithFields(logger.Fields{"visited": len(d.visited), "targets": len(d.targets)}).Info("Restored interrupted discovery procedure, resume it to continue")
+}
+
+// ControlDiscovery pause, resume or cancel the current discovery procedure
+func ControlDiscovery(action string) error {
+	discovery := CurrentDiscovery()
+	if discovery == nil {
+		return errors.New("no discovery procedure started")
+	}
+	switch action {
+	case "pause":
+		return discovery.Pause()
+	case "resume":
+		return discovery.Resume()
+	case "cancel":
+		return discovery.Cancel()
+	}
+	return fmt.Errorf("unknown discovery action %s", action)
 }
@@ -0,0 +1,122 @@
+package meshmesh
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	DiscoveryAggregationLast   = "last"
+	DiscoveryAggregationMin    = "min"
+	DiscoveryAggregationMean   = "mean"
+	DiscoveryAggregationMedian = "median"
+)
+
+// DiscoveryParams tune a discovery run, trading speed for accuracy
+type DiscoveryParams struct {
+	Slots       uint8         `json:"slots"`
+	Mask        uint8         `json:"mask"`
+	Filter      uint8         `json:"filter"`
+	Wait        time.Duration `json:"wait"`
+	Repetitions int           `json:"repetitions"`
+	// Aggregation is how the weights measured in the repetitions are combined: last, min, mean or median
+	Aggregation string `json:"aggregation"`
+	RssiMin     int16  `json:"rssi_min"`
+	RssiMax     int16  `json:"rssi_max"`
+	// DryRun keep the result as a proposal to review instead of applying it to the main network
+	DryRun bool `json:"dry_run"`
+	// WeightThreshold is the minimum weight change reported in the proposal
+	WeightThreshold float64 `json:"weight_threshold"`
+}
+
+func (p DiscoveryParams) Validate() error {
+	if p.Slots == 0 {
+		return errors.New("slots must be greater than zero")
+	}
+	if p.Wait < 0 {
+		return errors.New("wait time can't be negative")
+	}
+	if p.Repetitions < 1 {
+		return errors.New("repetitions must be at least one")
+	}
+	switch p.Aggregation {
+	case DiscoveryAggregationLast, DiscoveryAggregationMin, DiscoveryAggregationMean, DiscoveryAggregationMedian:
+	default:
+		return fmt.Errorf("unknown aggregation method %s", p.Aggregation)
+	}
+	if p.RssiMin >= p.RssiMax {
+		return errors.New("rssi min must be lower than rssi max")
+	}
+	if p.WeightThreshold < 0 {
+		return errors.New("weight threshold can't be negative")
+	}
+	return nil
+}
+
+// aggregate combine the weights of the repetitions
+func (p DiscoveryParams) aggregate(samples []float64) float64 {
+	if len(samples) == 0 {
+		return 1.0
+	}
+	switch p.Aggregation {
+	case DiscoveryAggregationMin:
+		value := samples[0]
+		for _, sample := range samples[1:] {
+			value = math.Min(value, sample)
+		}
+		return value
+	case DiscoveryAggregationMean:
+		sum := 0.0
+		for _, sample := range samples {
+			sum += sample
+		}
+		return math.Round(sum/float64(len(samples))*100) / 100
+	case DiscoveryAggregationMedian:
+		sorted := append([]float64(nil), samples...)
+		sort.Float64s(sorted)
+		middle := len(sorted) / 2
+		if len(sorted)%2 == 0 {
+			return math.Round((sorted[middle-1]+sorted[middle])/2*100) / 100
+		}
+		return sorted[middle]
+	}
+	return samples[len(samples)-1]
+}
+
+func (p DiscoveryParams) rssi2weight(rssi int16) float64 {
+	return rssi2weightRange(rssi, p.RssiMin, p.RssiMax)
+}
+
+var _discoveryDefaults = struct {
+	lock   sync.Mutex
+	params DiscoveryParams
+}{params: DiscoveryParams{
+	Slots:           100,
+	Wait:            5 * time.Second,
+	Repetitions:     maxRepetitions,
+	Aggregation:     DiscoveryAggregationLast,
+	RssiMin:         esp32RssiMin,
+	RssiMax:         esp32RssiMax,
+	WeightThreshold: 0.05,
+}}
+
+// DefaultDiscoveryParams returns the parameters used when a run doesn't specify them
+func DefaultDiscoveryParams() DiscoveryParams {
+	_discoveryDefaults.lock.Lock()
+	defer _discoveryDefaults.lock.Unlock()
+	return _discoveryDefaults.params
+}
+
+func SetDefaultDiscoveryParams(params DiscoveryParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+	_discoveryDefaults.lock.Lock()
+	defer _discoveryDefaults.lock.Unlock()
+	_discoveryDefaults.params = params
+	return nil
+}
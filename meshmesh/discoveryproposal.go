@@ -0,0 +1,155 @@
+package meshmesh
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"leguru.net/m/v2/logger"
+
+	gra "leguru.net/m/v2/graph"
+)
+
+// DiscoveryProposal is the result of a dry run discovery waiting for the operator review
+type DiscoveryProposal struct {
+	lock      sync.Mutex
+	network   *gra.Network
+	items     []gra.DiffItem
+	applied   map[int]bool
+	stale     map[int]bool
+	threshold float64
+	Created   time.Time
+}
+
+func newDiscoveryProposal(network *gra.Network, threshold float64) *DiscoveryProposal {
+	return &DiscoveryProposal{
+		network:   network,
+		items:     gra.DiffNetworks(gra.GetMainNetwork(), network, threshold),
+		applied:   make(map[int]bool),
+		stale:     make(map[int]bool),
+		threshold: threshold,
+		Created:   time.Now(),
+	}
+}
+
+// Items returns the differences between the main network and the proposed one
+func (p *DiscoveryProposal) Items() []gra.DiffItem {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return append([]gra.DiffItem(nil), p.items...)
+}
+
+func (p *DiscoveryProposal) IsApplied(id int) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.applied[id]
+}
+
+// IsStale reports if the item was skipped because the main network changed after the discovery
+func (p *DiscoveryProposal) IsStale(id int) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.stale[id]
+}
+
+func (p *DiscoveryProposal) Network() *gra.Network {
+	return p.network
+}
+
+// Threshold returns the minimum weight change reported in the items
+func (p *DiscoveryProposal) Threshold() float64 {
+	return p.threshold
+}
+
+// Apply apply the listed items to the main network, the items no longer matching the main
+// network are skipped and marked as stale.
+func (p *DiscoveryProposal) Apply(ids []int) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	items := make([]gra.DiffItem, 0, len(ids))
+	for _, id := range ids {
+		if id < 1 || id > len(p.items) {
+			return fmt.Errorf("unknown proposal item %d", id)
+		}
+		if p.items[id-1].Kind == gra.DiffRouteChanged {
+			return fmt.Errorf("proposal item %d is a route change and can't be applied alone", id)
+		}
+		items = append(items, p.items[id-1])
+	}
+
+	stale := make([]int, 0)
+	_ = gra.UpdateMainNetwork(func(network *gra.Network) error {
+		for _, item := range items {
+			if p.applied[item.Id] {
+				continue
+			}
+			if !network.DiffItemIsCurrent(item) {
+				p.stale[item.Id] = true
+				stale = append(stale, item.Id)
+				continue
+			}
+			network.ApplyDiffItem(p.network, item)
+			p.applied[item.Id] = true
+		}
+		return nil
+	})
+	if len(stale) > 0 {
+		logger.WithField("items", stale).Warn("Discovery proposal items skipped, the main network changed after the discovery")
+	}
+	logger.WithField("items", len(items)-len(stale)).Info("Applied discovery proposal items to main network")
+	return nil
+}
+
+var _proposal struct {
+	lock     sync.Mutex
+	proposal *DiscoveryProposal
+}
+
+func setDiscoveryProposal(proposal *DiscoveryProposal) {
+	_proposal.lock.Lock()
+	defer _proposal.lock.Unlock()
+	_proposal.proposal = proposal
+	logger.WithField("items", len(proposal.items)).Info("Discovery proposal ready for review")
+}
+
+// CurrentDiscoveryProposal returns the proposal waiting for review, nil if there is none
+func CurrentDiscoveryProposal() *DiscoveryProposal {
+	_proposal.lock.Lock()
+	defer _proposal.lock.Unlock()
+	return _proposal.proposal
+}
+
+// AcceptDiscoveryProposal apply to the main network all the items of the proposal not yet applied,
+// the changes made to the main network after the discovery are kept.
+func AcceptDiscoveryProposal() error {
+	_proposal.lock.Lock()
+	defer _proposal.lock.Unlock()
+	if _proposal.proposal == nil {
+		return errors.New("no discovery proposal to accept")
+	}
+
+	ids := make([]int, 0)
+	for _, item := range _proposal.proposal.Items() {
+		if item.Kind != gra.DiffRouteChanged {
+			ids = append(ids, item.Id)
+		}
+	}
+	if err := _proposal.proposal.Apply(ids); err != nil {
+		return err
+	}
+	_proposal.proposal = nil
+	return nil
+}
+
+// RejectDiscoveryProposal discard the proposal, the items already applied are kept
+func RejectDiscoveryProposal() error {
+	_proposal.lock.Lock()
+	defer _proposal.lock.Unlock()
+	if _proposal.proposal == nil {
+		return errors.New("no discovery proposal to reject")
+	}
+	_proposal.proposal = nil
+	return nil
+}
@@ -2,7 +2,7 @@ package meshmesh
 
 import (
 	"bytes"
-	"fmt"
+	"errors"
 	"net"
 	"sync"
 	"time"
@@ -22,6 +22,9 @@ const (
 	esphomeapiWaitPacketData int = 3
 )
 
+// networkReadBufferSize is the max number of bytes read from a client socket with a single call
+const networkReadBufferSize = 4096
+
 const (
 	fixedApiRemotePort int = 6053
 	fixedOtaRemotePort int = 3232
@@ -45,7 +48,6 @@ type NetworkConnectionStruct struct {
 	reqAddress      MeshNodeId
 	reqPort         int
 	debugThisNode   bool
-	timeout         time.Time
 	clientClosed    func(client NetworkConnection)
 }
 
@@ -60,48 +62,154 @@ func NewNetworkConnectionStruct(socket net.Conn, serial *SerialConnection, addr
 		socket:       socket,
 		tmpBuffer:    bytes.NewBuffer([]byte{}),
 		inBuffer:     bytes.NewBuffer([]byte{}),
-		timeout:      time.Now(),
 		clientClosed: closedCb,
 		Stats:        _allStats.Stats(addr),
 	}
 }
 
 type ServerApi struct {
-	Address       MeshNodeId
-	Clients       []NetworkConnection
-	listener      net.Listener
-	listenAddress string
+	Address         MeshNodeId
+	clientsLock     sync.Mutex
+	Clients         []NetworkConnection
+	serial          *SerialConnection
+	listeners       []net.Listener
+	listenAddresses []string
+	remotePort      int
+	bindError       error
+	closed          bool
+	proxy           *ApiProxy
+	online          bool
+	onlineChanged   func(server *ServerApi, online bool)
+	acceptCheck     func(server *ServerApi, socket net.Conn) (net.Conn, error)
 }
 
+// GetListenAddress returns the first of the addresses where the server listen
 func (s *ServerApi) GetListenAddress() string {
-	return s.listenAddress
+	if len(s.listenAddresses) == 0 {
+		return ""
+	}
+	return s.listenAddresses[0]
+}
+
+func (s *ServerApi) ListenAddresses() []string {
+	return s.listenAddresses
+}
+
+func (s *ServerApi) RemotePort() int {
+	return s.remotePort
+}
+
+func (s *ServerApi) IsListening() bool {
+	for _, listener := range s.listeners {
+		if listener != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// BindError returns the error of the last failed listen attempt
+func (s *ServerApi) BindError() error {
+	return s.bindError
+}
+
+// IsProxy reports if the server share a single node connection between all its clients
+func (s *ServerApi) IsProxy() bool {
+	return s.proxy != nil
+}
+
+func (s *ServerApi) Proxy() *ApiProxy {
+	return s.proxy
+}
+
+func (s *ServerApi) IsOnline() bool {
+	return s.online
+}
+
+// setOnline track the reachability of the node as seen by the connection handshakes
+func (s *ServerApi) setOnline(online bool) {
+	if s.online == online {
+		return
+	}
+	s.online = online
+	if s.onlineChanged != nil {
+		s.onlineChanged(s, online)
+	}
+}
+
+func (s *ServerApi) ClientsCount() int {
+	if s.proxy != nil {
+		return s.proxy.ClientsCount()
+	}
+	s.clientsLock.Lock()
+	defer s.clientsLock.Unlock()
+	return len(s.Clients)
+}
+
+// clients returns a copy of the clients list, the clients remove themselves when closed
+func (s *ServerApi) clients() []NetworkConnection {
+	s.clientsLock.Lock()
+	defer s.clientsLock.Unlock()
+	return slices.Clone(s.Clients)
+}
+
+func (s *ServerApi) addClient(client NetworkConnection) {
+	s.clientsLock.Lock()
+	s.Clients = append(s.Clients, client)
+	count := len(s.Clients)
+	s.clientsLock.Unlock()
+	logger.WithFields(logger.Fields{"nodeId": utils.FmtNodeId(int64(s.Address)), "clients": count}).Debug("Added new client")
+}
+
+func (s *ServerApi) trackConnectedPathReply(client NetworkConnection, meshprotocol *ConnPathConnection, v *ConnectedPathApiReply) {
+	oldConnState := meshprotocol.connState
+	handleClientConnectedPathReply(client, meshprotocol, v)
+	if oldConnState != meshprotocol.connState {
+		if meshprotocol.connState == connPathConnectionStateActive {
+			s.setOnline(true)
+		} else if meshprotocol.connState == connPathConnectionStateInvalid && oldConnState == connPathConnectionStateHandshakeStarted {
+			s.setOnline(false)
+		}
+	}
+}
+
+func handleClientConnectedPathReply(client NetworkConnection, meshprotocol *ConnPathConnection, v *ConnectedPathApiReply) {
+	if v.Command == connectedPathSendDataRequest {
+		if len(v.Data) > 0 {
+			err := client.ForwardData(v.Data)
+			if err != nil {
+				logger.Printf("HandleConnectedPathReply: ForwardData error on handle %d.", v.Handle)
+				client.Close()
+			}
+		}
+	} else {
+		oldConnState := meshprotocol.connState
+		meshprotocol.HandleIncomingReply(v)
+		if oldConnState != meshprotocol.connState {
+			if meshprotocol.connState == connPathConnectionStateActive {
+				client.FinishHandshake(true)
+			}
+			if meshprotocol.connState == connPathConnectionStateInvalid {
+				client.Close()
+			}
+		}
+	}
 }
 
 func (s *ServerApi) HandleConnectedPathReply(v *ConnectedPathApiReply) bool {
+	if s.proxy != nil {
+		meshprotocol := s.proxy.MeshProtocol()
+		if meshprotocol != nil && meshprotocol.handle == v.Handle {
+			s.trackConnectedPathReply(s.proxy, meshprotocol, v)
+			return true
+		}
+	}
+
 	handled := false
-	for _, client := range s.Clients {
+	for _, client := range s.clients() {
 		if client.MeshProtocol().handle == v.Handle {
 			handled = true
-			if v.Command == connectedPathSendDataRequest {
-				if len(v.Data) > 0 {
-					err := client.ForwardData(v.Data)
-					if err != nil {
-						logger.Printf("HandleConnectedPathReply: ForwardData error on handle %d.", v.Handle)
-						client.Close()
-					}
-				}
-			} else {
-				oldConnState := client.MeshProtocol().connState
-				client.MeshProtocol().HandleIncomingReply(v)
-				if oldConnState != client.MeshProtocol().connState {
-					if client.MeshProtocol().connState == connPathConnectionStateActive {
-						client.FinishHandshake(true)
-					}
-					if client.MeshProtocol().connState == connPathConnectionStateInvalid {
-						client.Close()
-					}
-				}
-			}
+			s.trackConnectedPathReply(client, client.MeshProtocol(), v)
 		}
 	}
 	return handled
@@ -109,31 +217,47 @@ func (s *ServerApi) HandleConnectedPathReply(v *ConnectedPathApiReply) bool {
 
 func (s *ServerApi) ClientClosedCb(client NetworkConnection) {
 	// Remove client from clients list
+	s.clientsLock.Lock()
 	idx := slices.Index(s.Clients, client)
 	if idx >= 0 {
 		s.Clients = append(s.Clients[:idx], s.Clients[idx+1:]...)
 	}
+	s.clientsLock.Unlock()
 	logger.WithFields(logger.Fields{"handle": client.MeshProtocol().handle}).Info("Closed EspHomeApi connection")
 }
 
-func (s *ServerApi) ListenAndServe(serial *SerialConnection, remotePort int) {
+func (s *ServerApi) ListenAndServe(listener net.Listener, serial *SerialConnection, remotePort int) {
 	for {
-		socket, err := s.listener.Accept()
+		socket, err := listener.Accept()
 		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				logger.WithFields(logger.Fields{"nodeId": utils.FmtNodeId(int64(s.Address)), "bind": listener.Addr().String()}).Debug("EspHome listener closed")
+				return
+			}
 			logger.Error(err)
 			continue
 		}
 
-		logger.WithFields(logger.Fields{"nodeId": s.Address, "active": len(s.Clients)}).Debug("EspHome connection accepted")
+		if s.acceptCheck != nil {
+			accepted, err := s.acceptCheck(s, socket)
+			if err != nil {
+				socket.Close()
+				continue
+			}
+			socket = accepted
+		}
+
+		logger.WithFields(logger.Fields{"nodeId": s.Address, "active": s.ClientsCount()}).Debug("EspHome connection accepted")
 
-		if remotePort == fixedApiRemotePort {
+		if s.proxy != nil {
+			s.proxy.AddClient(socket)
+		} else if remotePort == fixedApiRemotePort {
 			client, err := NewApiConnection(socket, serial, s.Address, remotePort, s.ClientClosedCb)
 			if err != nil {
 				logger.Error(err)
 				socket.Close()
 			} else {
-				s.Clients = append(s.Clients, client)
-				logger.WithFields(logger.Fields{"nodeId": utils.FmtNodeId(int64(s.Address)), "clients": len(s.Clients)}).Debug("Added new client")
+				s.addClient(client)
 			}
 		} else {
 			client, err := NewOtaConnection(socket, serial, s.Address, remotePort, s.ClientClosedCb)
@@ -141,8 +265,7 @@ func (s *ServerApi) ListenAndServe(serial *SerialConnection, remotePort int) {
 				logger.Error(err)
 				socket.Close()
 			} else {
-				s.Clients = append(s.Clients, client)
-				logger.WithFields(logger.Fields{"nodeId": utils.FmtNodeId(int64(s.Address)), "clients": len(s.Clients)}).Debug("Added new client")
+				s.addClient(client)
 			}
 		}
 
@@ -150,38 +273,99 @@ func (s *ServerApi) ListenAndServe(serial *SerialConnection, remotePort int) {
 }
 
 func (s *ServerApi) CloseConnections() {
-	for _, client := range s.Clients {
+	for _, client := range s.clients() {
 		client.Close()
 	}
+	if s.proxy != nil {
+		s.proxy.Shutdown()
+	}
 }
 
-func (s *ServerApi) ShutDown() {
-	s.listener.Close()
+// listenNetwork select the network for a listen address, the IPv6 unspecified address is dual stack
+func listenNetwork(address string) string {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return "tcp"
+	}
+	ip := net.ParseIP(host)
+	switch {
+	case ip == nil || ip.Equal(net.IPv6unspecified):
+		return "tcp"
+	case ip.To4() != nil:
+		return "tcp4"
+	default:
+		return "tcp6"
+	}
 }
 
-func NewServerApi(serial *SerialConnection, address MeshNodeId, config *ServerApiConfig) (*ServerApi, error) {
-	var bindAddress string = config.BindAddress
-	if config.BindAddress == "" || config.BindAddress == "dynamic" {
-		bindAddress = utils.FmtNodeIdHass(int64(address))
+// Listen open the server listeners not yet open, the errors are kept to be reported until the next attempt
+func (s *ServerApi) Listen() error {
+	if s.closed {
+		return nil
+	}
+	if len(s.listeners) != len(s.listenAddresses) {
+		s.listeners = make([]net.Listener, len(s.listenAddresses))
 	}
 
+	var errs []error
+	for i, address := range s.listenAddresses {
+		if s.listeners[i] != nil {
+			continue
+		}
+		listener, err := net.Listen(listenNetwork(address), address)
+		if err != nil {
+			logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(s.Address)), "bind": address, "err": err}).Error("Can't listen on port for node connection")
+			errs = append(errs, err)
+			continue
+		}
+
+		logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(s.Address)), "bind": address}).Debug("Start listening on port for node connection")
+		s.listeners[i] = listener
+		go s.ListenAndServe(listener, s.serial, s.remotePort)
+	}
+	s.bindError = errors.Join(errs...)
+	return s.bindError
+}
+
+// ShutDown close the listeners and all the connections of the server
+func (s *ServerApi) ShutDown() {
+	s.closed = true
+	for i, listener := range s.listeners {
+		if listener != nil {
+			listener.Close()
+			s.listeners[i] = nil
+		}
+	}
+	s.CloseConnections()
+}
+
+func NewServerApi(serial *SerialConnection, address MeshNodeId, config *ServerApiConfig) (*ServerApi, error) {
 	bindPort := config.BindPort
 	if config.BindPort <= 0 {
 		bindPort = utils.HashString(utils.FmtNodeId(int64(address)), config.SizeOfPortsPool) + config.BasePortOffset
 	}
 
-	server := ServerApi{Address: address}
-	server.listenAddress = fmt.Sprintf("%s:%d", bindAddress, bindPort)
-	listener, err := net.Listen("tcp4", server.listenAddress)
-	if err != nil {
-		logger.Error(err)
-		return nil, err
+	server := ServerApi{Address: address, serial: serial, remotePort: config.RemotePort, online: true}
+	if config.ProxyMode && config.RemotePort == fixedApiRemotePort {
+		server.proxy = NewApiProxy(serial, address, config.RemotePort, config.Cache.Node(address), config.CacheGrace)
 	}
-
-	logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(address)), "bind": server.listenAddress}).Debug("Start listening on port for node connection")
-	server.listener = listener
-	go server.ListenAndServe(serial, config.RemotePort)
-	return &server, nil
+	if config.Ports != nil {
+		alloc, err := config.Ports.Allocation(address)
+		if err != nil {
+			server.bindError = err
+			return &server, err
+		}
+		server.listenAddresses = alloc.ListenAddresses(config.RemotePort)
+	} else {
+		bindAddresses, err := ResolveBindAddresses(config.BindAddress, config.IPv6Prefix, address)
+		if err != nil {
+			server.bindError = err
+			return &server, err
+		}
+		server.listenAddresses = joinHostsPort(bindAddresses, bindPort)
+	}
+	// The server is returned even if the listen fails so the error can be reported and retried
+	return &server, server.Listen()
 }
 
 func (m *MultiServerApi) handleUnhandledReply(v *ConnectedPathApiReply) {
@@ -191,7 +375,7 @@ func (m *MultiServerApi) handleUnhandledReply(v *ConnectedPathApiReply) {
 
 func (m *MultiServerApi) HandleConnectedPathReply(v *ConnectedPathApiReply) {
 	var handled bool = false
-	for _, server := range m.Servers {
+	for _, server := range m.ServersList() {
 		handled = server.HandleConnectedPathReply(v)
 		if handled {
 			break
@@ -202,6 +386,13 @@ func (m *MultiServerApi) HandleConnectedPathReply(v *ConnectedPathApiReply) {
 	}
 }
 
+// ServersList returns a copy of the current servers list
+func (m *MultiServerApi) ServersList() []*ServerApi {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return slices.Clone(m.Servers)
+}
+
 func (m *MultiServerApi) Stats() *EspApiStats {
 	return m.espApiStats
 }
@@ -210,106 +401,265 @@ func (m *MultiServerApi) PrintStats() {
 	m.espApiStats.PrintStats()
 }
 
-func (m *MultiServerApi) CloseConnection(addr MeshNodeId) {
-	for _, server := range m.Servers {
-		if server.Address == addr {
-			server.CloseConnections()
+// SaveCache writes the esphome entities cache on disk
+func (m *MultiServerApi) SaveCache(filename string) error {
+	return m.config.Cache.SaveToFile(filename)
+}
+
+func (m *MultiServerApi) Cache() *EspHomeCache {
+	return m.config.Cache
+}
+
+// advertise publish or withdraw the mDNS record of an API server following the node status
+func (m *MultiServerApi) advertise(server *ServerApi, node graph.NodeDevice) {
+	if m.mdns == nil || server.remotePort != fixedApiRemotePort {
+		return
+	}
+	if !node.Device().InUse() || !server.IsOnline() {
+		m.mdns.Withdraw(server.Address)
+		return
+	}
+	text := mdnsTextRecords(server.Address, m.config.Cache.Node(server.Address))
+	err := m.mdns.Advertise(server.Address, node.Device().Tag(), server.ListenAddresses(), text)
+	if err != nil {
+		logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(server.Address)), "err": err}).Warn("mDNS advertisement failed")
+	}
+}
+
+func (m *MultiServerApi) serverOnlineChanged(server *ServerApi, online bool) {
+	logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(server.Address)), "online": online}).Info("EspHome node reachability changed")
+	node, err := graph.GetMainNetwork().GetNodeDevice(int64(server.Address))
+	if err != nil {
+		if m.mdns != nil {
+			m.mdns.Withdraw(server.Address)
 		}
+		return
 	}
+	m.advertise(server, node)
 }
 
-func (m *MultiServerApi) MainNetworkChanged() {
-	nodes := graph.GetMainNetwork().Nodes()
+// checkClient apply the access rules and the clients limits to a new connection, the accepted
+// socket gives back its slot of the limits when closed
+func (m *MultiServerApi) checkClient(server *ServerApi, socket net.Conn) (net.Conn, error) {
+	release, err := m.config.Access.Check(server.Address, server.remotePort, socket.RemoteAddr())
+	if err != nil {
+		return nil, err
+	}
+	return &accessConn{Conn: socket, release: release}, nil
+}
+
+// accessConn is a client socket holding a slot of the clients limits
+type accessConn struct {
+	net.Conn
+	release func()
+}
+
+func (c *accessConn) Close() error {
+	err := c.Conn.Close()
+	c.release()
+	return err
+}
+
+func (m *MultiServerApi) Access() *AccessControl {
+	return m.config.Access
+}
+
+func (m *MultiServerApi) addServer(server *ServerApi, node graph.NodeDevice) {
+	server.onlineChanged = m.serverOnlineChanged
+	server.acceptCheck = m.checkClient
+	m.Servers = append(m.Servers, server)
+	m.advertise(server, node)
+}
+
+// startNodeServers creates the API and the OTA servers of a node
+func (m *MultiServerApi) startNodeServers(node graph.NodeDevice) {
+	configApi := m.config
+	configApi.RemotePort = fixedApiRemotePort
+	server, err := NewServerApi(m.serial, MeshNodeId(node.ID()), &configApi)
+	if err != nil {
+		log.Error(err)
+	}
+	m.addServer(server, node)
+
+	configOta := m.config
+	configOta.BindPort = fixedOtaRemotePort
+	configOta.RemotePort = fixedOtaRemotePort
+	serverOta, err := NewServerApi(m.serial, MeshNodeId(node.ID()), &configOta)
+	if err != nil {
+		log.Error(err)
+	}
+	m.addServer(serverOta, node)
+}
+
+func (m *MultiServerApi) stopServer(server *ServerApi) {
+	if m.mdns != nil && server.remotePort == fixedApiRemotePort {
+		m.mdns.Withdraw(server.Address)
+	}
+	server.ShutDown()
+}
+
+// reconcile starts the servers of the in use nodes, stops the servers of the removed or
+// disabled nodes and retries the listen of the servers that failed to bind. The in use nodes are
+// compared with the ones of the previous call, only the changed servers are touched.
+func (m *MultiServerApi) reconcile() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	inUse := make(map[MeshNodeId]graph.NodeDevice)
+	tags := make(map[MeshNodeId]string)
+	nodes := graph.GetMainNetworkSnapshot().Nodes()
 	for nodes.Next() {
 		node := nodes.Node().(graph.NodeDevice)
 		if node.Device().InUse() {
-			found := false
-			for _, server := range m.Servers {
-				if server.Address == MeshNodeId(node.ID()) {
-					found = true
-					break
-				}
-			}
-			if !found {
-				logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(node.ID()))}).Debug("MainNetworkChanged adding esphome connection to new node")
-				server, err := NewServerApi(m.serial, MeshNodeId(node.ID()), &m.config)
-				if err != nil {
-					log.Error(err)
-				} else {
-					m.Servers = append(m.Servers, server)
-				}
+			inUse[MeshNodeId(node.ID())] = node
+			tags[MeshNodeId(node.ID())] = node.Device().Tag()
+		}
+	}
+	previous := m.inUseTags
+	m.inUseTags = tags
+
+	// Nodes with a listen address changed in the ports table must restart their servers
+	moved := make(map[MeshNodeId]bool)
+	if m.config.Ports != nil {
+		for _, server := range m.Servers {
+			alloc, err := m.config.Ports.Allocation(server.Address)
+			if err == nil && !slices.Equal(alloc.ListenAddresses(server.remotePort), server.listenAddresses) {
+				moved[server.Address] = true
 			}
 		}
 	}
 
-	newServers := make([]*ServerApi, 0)
+	running := make(map[MeshNodeId]bool)
+	newServers := make([]*ServerApi, 0, len(m.Servers))
 	for _, server := range m.Servers {
-		found := false
-		nodes = graph.GetMainNetwork().Nodes()
-		for nodes.Next() {
-			node := nodes.Node().(graph.NodeDevice)
-			if server.Address == MeshNodeId(node.ID()) {
-				found = true
-				break
-			}
+		node, ok := inUse[server.Address]
+		if !ok || moved[server.Address] {
+			logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(server.Address)), "port": server.remotePort, "moved": moved[server.Address]}).Debug("Stopping esphome server of removed, disabled or moved node")
+			m.stopServer(server)
+			continue
 		}
-		if !found {
-			logger.WithFields(logger.Fields{"server": server.Address}).Debug("MainNetworkChanged deleting esphome connection to non existing node")
-			server.CloseConnections()
-		} else {
-			newServers = append(newServers, server)
+		running[server.Address] = true
+		if server.BindError() != nil {
+			server.Listen()
+		}
+		// The advertisement carries the tag of the node
+		if tag, ok := previous[server.Address]; !ok || tag != tags[server.Address] {
+			m.advertise(server, node)
 		}
+		newServers = append(newServers, server)
 	}
 	m.Servers = newServers
+
+	// Start the new servers in a stable order, the automatic ports depend on it
+	addresses := make([]MeshNodeId, 0, len(inUse))
+	for address := range inUse {
+		addresses = append(addresses, address)
+	}
+	slices.Sort(addresses)
+	for _, address := range addresses {
+		if !running[address] {
+			logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(address))}).Debug("Starting esphome servers of new node")
+			m.startNodeServers(inUse[address])
+		}
+	}
+}
+
+// ShutdownMdns withdraw all the mDNS advertisements
+func (m *MultiServerApi) ShutdownMdns() {
+	if m.mdns != nil {
+		m.mdns.Shutdown()
+	}
+}
+
+// PortAllocations returns the ports table used by the servers
+func (m *MultiServerApi) PortAllocations() []PortAllocation {
+	if m.config.Ports == nil {
+		return nil
+	}
+	return m.config.Ports.Allocations()
+}
+
+// SetPortAllocation override the ports of a node and restart its servers
+func (m *MultiServerApi) SetPortAllocation(address MeshNodeId, bindAddresses []string, apiPort int, otaPort int) (PortAllocation, error) {
+	if m.config.Ports == nil {
+		return PortAllocation{}, errors.New("ports table not enabled")
+	}
+	alloc, err := m.config.Ports.SetOverride(address, bindAddresses, apiPort, otaPort)
+	if err != nil {
+		return alloc, err
+	}
+	m.reconcile()
+	return alloc, nil
+}
+
+// ResetPortAllocation restore the automatic ports assignment of a node
+func (m *MultiServerApi) ResetPortAllocation(address MeshNodeId) (PortAllocation, error) {
+	if m.config.Ports == nil {
+		return PortAllocation{}, errors.New("ports table not enabled")
+	}
+	m.config.Ports.Reset(address)
+	alloc, err := m.config.Ports.Allocation(address)
+	if err != nil {
+		return alloc, err
+	}
+	m.reconcile()
+	return alloc, nil
+}
+
+func (m *MultiServerApi) CloseConnection(addr MeshNodeId) {
+	for _, server := range m.ServersList() {
+		if server.Address == addr {
+			server.CloseConnections()
+		}
+	}
+}
+
+func (m *MultiServerApi) MainNetworkChanged() {
+	m.reconcile()
 }
 
 type ServerApiConfig struct {
 	BindAddress     string
+	IPv6Prefix      string
 	BindPort        int
 	RemotePort      int
 	BasePortOffset  int
 	SizeOfPortsPool int
+	ProxyMode       bool
+	CacheGrace      time.Duration
+	Cache           *EspHomeCache
+	MdnsAdvertise   bool
+	Ports           *PortAllocationTable
+	Access          *AccessControl
 }
 
 type MultiServerApi struct {
+	lock        sync.Mutex
 	espApiStats *EspApiStats
 	serial      *SerialConnection
 	config      ServerApiConfig
+	mdns        *MdnsAdvertiser
 	Servers     []*ServerApi
+	// inUseTags are the tags of the in use nodes seen by the last reconcile
+	inUseTags map[MeshNodeId]string
 }
 
 func NewMultiServerApi(serial *SerialConnection, config ServerApiConfig) *MultiServerApi {
 	_allStats = NewEspApiStats()
+	if config.Cache == nil {
+		config.Cache = NewEspHomeCache()
+	}
+	if config.Access == nil {
+		config.Access = NewAccessControl()
+	}
 	multisrv := MultiServerApi{serial: serial, espApiStats: _allStats, config: config}
+	if config.MdnsAdvertise {
+		multisrv.mdns = NewMdnsAdvertiser()
+	}
 	SendClearConnections(serial)
 	multisrv.serial.ConnPathFn = multisrv.HandleConnectedPathReply
 
-	nodes := graph.GetMainNetwork().Nodes()
 	graph.AddMainNetworkChangedCallback(multisrv.MainNetworkChanged)
-
-	for nodes.Next() {
-		node := nodes.Node().(graph.NodeDevice)
-		if node.Device().InUse() {
-			configApi := config
-			configApi.RemotePort = fixedApiRemotePort
-
-			server, err := NewServerApi(serial, MeshNodeId(node.ID()), &configApi)
-			if err != nil {
-				log.Error(err)
-			} else {
-				multisrv.Servers = append(multisrv.Servers, server)
-			}
-
-			configOta := config
-			configOta.BindPort = fixedOtaRemotePort
-			configOta.RemotePort = fixedOtaRemotePort
-			serverOta, err := NewServerApi(serial, MeshNodeId(node.ID()), &configOta)
-			if err != nil {
-				log.Error(err)
-			} else {
-				multisrv.Servers = append(multisrv.Servers, serverOta)
-			}
-		}
-	}
+	multisrv.reconcile()
 	return &multisrv
 }
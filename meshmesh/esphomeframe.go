@@ -0,0 +1,248 @@
+package meshmesh
+
+import (
+	"bytes"
+	"errors"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// ESPHome native API message ids (see rpc/esphome/api.proto)
+const (
+	espHomeHelloRequest             uint32 = 1
+	espHomeHelloResponse            uint32 = 2
+	espHomeConnectRequest           uint32 = 3
+	espHomeConnectResponse          uint32 = 4
+	espHomeDisconnectRequest        uint32 = 5
+	espHomeDisconnectResponse       uint32 = 6
+	espHomePingRequest              uint32 = 7
+	espHomePingResponse             uint32 = 8
+	espHomeDeviceInfoRequest        uint32 = 9
+	espHomeDeviceInfoResponse       uint32 = 10
+	espHomeListEntitiesRequest      uint32 = 11
+	espHomeListEntitiesDoneResponse uint32 = 19
+	espHomeSubscribeStatesRequest   uint32 = 20
+	espHomeSubscribeLogsRequest     uint32 = 28
+	espHomeSubscribeHassServices    uint32 = 34
+	espHomeGetTimeRequest           uint32 = 36
+	espHomeGetTimeResponse          uint32 = 37
+	espHomeSubscribeHassStates      uint32 = 38
+)
+
+const (
+	espHomePlaintextFramePreamble    byte   = 0x00
+	espHomeMaxPlaintextFrameSize     int    = 64 * 1024
+	espHomeProxyClientInfo           string = "meshmeshgo proxy"
+	espHomeProxyApiVersionMajor      uint64 = 1
+	espHomeProxyApiVersionMinor      uint64 = 10
+	espHomeStateResponseKeyFieldNum         = 1
+	espHomeEntityResponseKeyFieldNum        = 2
+	espHomeInvalidPasswordFieldNum          = 1
+)
+
+// espHomeStateResponses are the server messages that carry the state of a single entity.
+var espHomeStateResponses = map[uint32]struct{}{
+	21: {}, 22: {}, 23: {}, 24: {}, 25: {}, 26: {}, 27: {}, 47: {}, 50: {}, 53: {}, 56: {}, 59: {}, 64: {},
+	95: {}, 98: {}, 101: {}, 104: {}, 110: {}, 113: {}, 117: {},
+}
+
+// espHomeResponseRequests maps the server messages that are not entity states to the client
+// request or subscription they answer.
+var espHomeResponseRequests = map[uint32]uint32{
+	29: espHomeSubscribeLogsRequest, 35: espHomeSubscribeHassServices, 39: espHomeSubscribeHassStates,
+	44: 45, 67: 66, 69: 68, 71: 70, 72: 70, 74: 73, 79: 78, 81: 80, 83: 75, 84: 78, 85: 68, 86: 68,
+	88: 68, 90: 89, 93: 66, 108: espHomeSubscribeStatesRequest, 120: 119, 122: 121, 125: 124,
+}
+
+// espHomeMissingStateFields are the missing_state field numbers of the state responses that have one.
+var espHomeMissingStateFields = map[uint32]protowire.Number{
+	21: 3, 25: 3, 27: 3, 50: 3, 53: 3, 98: 3, 101: 2, 104: 2, 113: 2, 117: 2,
+}
+
+func isEspHomeStateResponse(msgType uint32) bool {
+	_, ok := espHomeStateResponses[msgType]
+	return ok
+}
+
+// isEspHomeCommandRequest reports if a client message is a command directed to an entity.
+func isEspHomeCommandRequest(msgType uint32) bool {
+	switch msgType {
+	case 30, 31, 32, 33, 42, 48, 51, 54, 57, 60, 62, 65, 96, 99, 102, 105, 111, 114, 118:
+		return true
+	}
+	return false
+}
+
+// EspHomeFrame is a single message of the ESPHome plaintext API protocol
+type EspHomeFrame struct {
+	Type    uint32
+	Payload []byte
+}
+
+func (f *EspHomeFrame) Encode() []byte {
+	out := make([]byte, 0, len(f.Payload)+8)
+	out = append(out, espHomePlaintextFramePreamble)
+	out = protowire.AppendVarint(out, uint64(len(f.Payload)))
+	out = protowire.AppendVarint(out, uint64(f.Type))
+	out = append(out, f.Payload...)
+	return out
+}
+
+// Fixed32Field returns the value of the first fixed32 field with the given number in the frame payload.
+func (f *EspHomeFrame) Fixed32Field(num protowire.Number) (uint32, bool) {
+	b := f.Payload
+	for len(b) > 0 {
+		n, typ, l := protowire.ConsumeTag(b)
+		if l < 0 {
+			return 0, false
+		}
+		b = b[l:]
+		if n == num && typ == protowire.Fixed32Type {
+			v, l := protowire.ConsumeFixed32(b)
+			return v, l >= 0
+		}
+		l = protowire.ConsumeFieldValue(n, typ, b)
+		if l < 0 {
+			return 0, false
+		}
+		b = b[l:]
+	}
+	return 0, false
+}
+
+// StringField returns the value of the first string field with the given number in the frame payload.
+func (f *EspHomeFrame) StringField(num protowire.Number) (string, bool) {
+	b := f.Payload
+	for len(b) > 0 {
+		n, typ, l := protowire.ConsumeTag(b)
+		if l < 0 {
+			return "", false
+		}
+		b = b[l:]
+		if n == num && typ == protowire.BytesType {
+			v, l := protowire.ConsumeString(b)
+			return v, l >= 0
+		}
+		l = protowire.ConsumeFieldValue(n, typ, b)
+		if l < 0 {
+			return "", false
+		}
+		b = b[l:]
+	}
+	return "", false
+}
+
+// VarintField returns the value of the first varint field with the given number in the frame payload.
+func (f *EspHomeFrame) VarintField(num protowire.Number) (uint64, bool) {
+	b := f.Payload
+	for len(b) > 0 {
+		n, typ, l := protowire.ConsumeTag(b)
+		if l < 0 {
+			return 0, false
+		}
+		b = b[l:]
+		if n == num && typ == protowire.VarintType {
+			v, l := protowire.ConsumeVarint(b)
+			return v, l >= 0
+		}
+		l = protowire.ConsumeFieldValue(n, typ, b)
+		if l < 0 {
+			return 0, false
+		}
+		b = b[l:]
+	}
+	return 0, false
+}
+
+// StateKey returns the entity key of a state response frame.
+func (f *EspHomeFrame) StateKey() (uint32, bool) {
+	return f.Fixed32Field(espHomeStateResponseKeyFieldNum)
+}
+
+// EntityKey returns the entity key of a list entities response frame.
+func (f *EspHomeFrame) EntityKey() (uint32, bool) {
+	return f.Fixed32Field(espHomeEntityResponseKeyFieldNum)
+}
+
+// WithMissingState returns a copy of the state frame flagged as missing, false for the frames
+// without a missing_state field.
+func (f *EspHomeFrame) WithMissingState() (*EspHomeFrame, bool) {
+	num, ok := espHomeMissingStateFields[f.Type]
+	if !ok {
+		return nil, false
+	}
+	payload := append([]byte(nil), f.Payload...)
+	payload = protowire.AppendTag(payload, num, protowire.VarintType)
+	payload = protowire.AppendVarint(payload, 1)
+	return NewEspHomeFrame(f.Type, payload), true
+}
+
+func NewEspHomeFrame(msgType uint32, payload []byte) *EspHomeFrame {
+	return &EspHomeFrame{Type: msgType, Payload: payload}
+}
+
+func newEspHomeHelloRequest() *EspHomeFrame {
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.BytesType)
+	b = protowire.AppendString(b, espHomeProxyClientInfo)
+	b = protowire.AppendTag(b, 2, protowire.VarintType)
+	b = protowire.AppendVarint(b, espHomeProxyApiVersionMajor)
+	b = protowire.AppendTag(b, 3, protowire.VarintType)
+	b = protowire.AppendVarint(b, espHomeProxyApiVersionMinor)
+	return NewEspHomeFrame(espHomeHelloRequest, b)
+}
+
+// newEspHomeInvalidPasswordResponse is the connect response that refuse the password of a client
+func newEspHomeInvalidPasswordResponse() *EspHomeFrame {
+	b := protowire.AppendTag(nil, espHomeInvalidPasswordFieldNum, protowire.VarintType)
+	b = protowire.AppendVarint(b, 1)
+	return NewEspHomeFrame(espHomeConnectResponse, b)
+}
+
+// EspHomeFrameReader reassemble frames of the ESPHome plaintext protocol from a byte stream
+type EspHomeFrameReader struct {
+	buffer bytes.Buffer
+}
+
+func (r *EspHomeFrameReader) Write(data []byte) {
+	r.buffer.Write(data)
+}
+
+func (r *EspHomeFrameReader) Len() int {
+	return r.buffer.Len()
+}
+
+// Next returns the next complete frame, nil if more data is needed or an error if the stream is corrupted.
+func (r *EspHomeFrameReader) Next() (*EspHomeFrame, error) {
+	b := r.buffer.Bytes()
+	if len(b) == 0 {
+		return nil, nil
+	}
+	if b[0] != espHomePlaintextFramePreamble {
+		r.buffer.Reset()
+		return nil, errors.New("invalid esphome frame preamble")
+	}
+
+	size, n1 := protowire.ConsumeVarint(b[1:])
+	if n1 < 0 {
+		return nil, nil
+	}
+	if size > uint64(espHomeMaxPlaintextFrameSize) {
+		r.buffer.Reset()
+		return nil, errors.New("esphome frame too big")
+	}
+	msgType, n2 := protowire.ConsumeVarint(b[1+n1:])
+	if n2 < 0 {
+		return nil, nil
+	}
+
+	header := 1 + n1 + n2
+	if len(b) < header+int(size) {
+		return nil, nil
+	}
+
+	payload := make([]byte, size)
+	copy(payload, b[header:header+int(size)])
+	r.buffer.Next(header + int(size))
+	return NewEspHomeFrame(uint32(msgType), payload), nil
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sync/atomic"
 	"time"
 
 	gra "leguru.net/m/v2/graph"
@@ -28,6 +29,7 @@ type FirmwareUploadProcedure struct {
 	errFatal      error
 	errWarn       error
 	complete      bool
+	running       atomic.Bool
 }
 
 func (f *FirmwareUploadProcedure) checkMemoryMd5(md5 [16]byte, memoryAddress uint32, length uint32) (bool, bool, error) {
@@ -221,6 +223,15 @@ func (f *FirmwareUploadProcedure) IsComplete() bool {
 	return f.complete
 }
 
+func (f *FirmwareUploadProcedure) IsRunning() bool {
+	return f.running.Load()
+}
+
+// Error returns the error that stopped the procedure
+func (f *FirmwareUploadProcedure) Error() error {
+	return f.errFatal
+}
+
 func (f *FirmwareUploadProcedure) PrintStats() {
 	for !f.IsComplete() {
 		time.Sleep(100 * time.Millisecond)
@@ -232,7 +243,29 @@ func (f *FirmwareUploadProcedure) PrintStats() {
 	}
 }
 
-func (f *FirmwareUploadProcedure) Run(firmware []byte) {
+// Start reserve the node and run the upload in background, the procedure is already
+// running when Start returns without errors.
+func (f *FirmwareUploadProcedure) Start(firmware []byte) error {
+	if !f.running.CompareAndSwap(false, true) {
+		return errors.New("firmware upload procedure already running")
+	}
+
+	// An ESPHome OTA on the same node would corrupt the flash
+	owner, err := otaSessions.Acquire(f.nodeid, "hub")
+	if err != nil {
+		f.running.Store(false)
+		logger.WithField("err", err).Error("Firmware upload refused")
+		return err
+	}
+
+	go f.run(owner, firmware)
+	return nil
+}
+
+func (f *FirmwareUploadProcedure) run(owner *OtaLock, firmware []byte) {
+	defer f.running.Store(false)
+	defer otaSessions.Release(f.nodeid, owner)
+
 	f.errFatal = f.InitFromBytes(firmware)
 	if f.errFatal != nil {
 		return
@@ -0,0 +1,82 @@
+package meshmesh
+
+import (
+	"sync"
+	"time"
+
+	"leguru.net/m/v2/logger"
+)
+
+const networkHandshakeTimeout = 3 * time.Second
+
+// handshakeSupervisor close the connections that don't complete the handshake with the node in time.
+// A single timer armed on the nearest deadline replace a polling goroutine for each connection.
+type handshakeSupervisor struct {
+	lock    sync.Mutex
+	pending map[NetworkConnection]time.Time
+	timer   *time.Timer
+}
+
+var handshakes = &handshakeSupervisor{pending: make(map[NetworkConnection]time.Time)}
+
+// rearm must be called with the lock held
+func (s *handshakeSupervisor) rearm() {
+	var next time.Time
+	for _, deadline := range s.pending {
+		if next.IsZero() || deadline.Before(next) {
+			next = deadline
+		}
+	}
+	if next.IsZero() {
+		if s.timer != nil {
+			s.timer.Stop()
+		}
+		return
+	}
+	if s.timer == nil {
+		s.timer = time.AfterFunc(time.Until(next), s.expire)
+	} else {
+		s.timer.Reset(time.Until(next))
+	}
+}
+
+// Watch start the handshake timeout of a connection
+func (s *handshakeSupervisor) Watch(conn NetworkConnection) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.pending[conn] = time.Now().Add(networkHandshakeTimeout)
+	s.rearm()
+}
+
+// Done stop watching a connection that completed the handshake or was closed
+func (s *handshakeSupervisor) Done(conn NetworkConnection) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.pending[conn]; ok {
+		delete(s.pending, conn)
+		s.rearm()
+	}
+}
+
+func (s *handshakeSupervisor) expire() {
+	now := time.Now()
+	expired := make([]NetworkConnection, 0)
+
+	s.lock.Lock()
+	for conn, deadline := range s.pending {
+		if !deadline.After(now) {
+			delete(s.pending, conn)
+			state := conn.MeshProtocol().connState
+			if state == connPathConnectionStateInit || state == connPathConnectionStateHandshakeStarted {
+				expired = append(expired, conn)
+			}
+		}
+	}
+	s.rearm()
+	s.lock.Unlock()
+
+	for _, conn := range expired {
+		logger.WithFields(logger.Fields{"handle": conn.MeshProtocol().handle, "timeout": networkHandshakeTimeout}).Error("Closing connection because handshake timeout")
+		conn.Close()
+	}
+}
@@ -0,0 +1,185 @@
+package meshmesh
+
+import (
+	"container/list"
+	"fmt"
+	"net"
+	"runtime"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"leguru.net/m/v2/graph"
+	"leguru.net/m/v2/logger"
+)
+
+// setupHandshakeTest prepare a network of n nodes reachable from the coordinator, one for each
+// connection, and a serial connection that only queues the outgoing frames.
+func setupHandshakeTest(n int) *SerialConnection {
+	logger.SetLevel(logrus.ErrorLevel)
+	_allStats = NewEspApiStats()
+	network := graph.NewNetwork(1)
+	for i := 0; i < n; i++ {
+		network.ChangeEdgeWeight(1, int64(i+2), 1, 1)
+	}
+	graph.SetMainNetwork(network)
+	return &SerialConnection{Sessions: list.New(), NextHandle: 1}
+}
+
+// openHandshakeConnections open n connections waiting for the handshake, write a client hello on each
+// of them and close the client side. It returns when all the connections are closed.
+func openHandshakeConnections(tb testing.TB, serial *SerialConnection, n int) {
+	var closed sync.WaitGroup
+	closed.Add(n)
+	closedCb := func(NetworkConnection) { closed.Done() }
+
+	hello := NewEspHomeFrame(espHomeHelloRequest, []byte("meshmeshgo benchmark")).Encode()
+	peers := make([]net.Conn, n)
+	for i := range peers {
+		server, client := net.Pipe()
+		if _, err := NewApiConnection(server, serial, MeshNodeId(i+2), 6053, closedCb); err != nil {
+			tb.Fatal(err)
+		}
+		peers[i] = client
+	}
+	for _, peer := range peers {
+		if _, err := peer.Write(hello); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	for _, peer := range peers {
+		peer.Close()
+	}
+	closed.Wait()
+}
+
+func TestHandshakeSupervisorDone(t *testing.T) {
+	serial := setupHandshakeTest(50)
+	openHandshakeConnections(t, serial, 50)
+
+	handshakes.lock.Lock()
+	pending := len(handshakes.pending)
+	handshakes.lock.Unlock()
+	if pending != 0 {
+		t.Fatalf("%d connections still watched after close", pending)
+	}
+}
+
+func BenchmarkHandshake(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("conns=%d", n), func(b *testing.B) {
+			serial := setupHandshakeTest(n)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				openHandshakeConnections(b, serial, n)
+				serial.Sessions.Init()
+			}
+		})
+	}
+}
+
+// openActiveConnections open n connections and complete their handshake as if the nodes accepted them.
+// It returns the client side of the connections and a function that closes them and waits for the server side.
+func openActiveConnections(tb testing.TB, serial *SerialConnection, n int) ([]net.Conn, func()) {
+	var closed sync.WaitGroup
+	closed.Add(n)
+	closedCb := func(NetworkConnection) { closed.Done() }
+
+	peers := make([]net.Conn, n)
+	for i := range peers {
+		server, client := net.Pipe()
+		conn, err := NewApiConnection(server, serial, MeshNodeId(i+2), 6053, closedCb)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		conn.meshprotocol.HandleIncomingReply(&ConnectedPathApiReply{Command: connectedPathOpenConnectionAck, Handle: conn.meshprotocol.handle})
+		conn.FinishHandshake(true)
+		peers[i] = client
+	}
+	return peers, func() {
+		for _, peer := range peers {
+			peer.Close()
+		}
+		closed.Wait()
+	}
+}
+
+// queuedFrames returns the number of frames queued on the serial connection
+func queuedFrames(serial *SerialConnection) int {
+	serial.SessionsLock.Lock()
+	defer serial.SessionsLock.Unlock()
+	return serial.Sessions.Len()
+}
+
+// cpuTime returns the user and system CPU time used by the process
+func cpuTime(tb testing.TB) time.Duration {
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+		tb.Fatal(err)
+	}
+	return time.Duration(usage.Utime.Nano() + usage.Stime.Nano())
+}
+
+// reportResources report the CPU time per operation spent since start and the goroutines started after base
+func reportResources(b *testing.B, start time.Duration, base int) {
+	b.ReportMetric(float64(cpuTime(b)-start)/float64(b.N), "cpu-ns/op")
+	b.ReportMetric(float64(runtime.NumGoroutine()-base), "goroutines")
+}
+
+func BenchmarkIdleConnections(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("conns=%d", n), func(b *testing.B) {
+			serial := setupHandshakeTest(n)
+			base := runtime.NumGoroutine()
+			_, closeAll := openActiveConnections(b, serial, n)
+			defer closeAll()
+
+			b.ResetTimer()
+			start := cpuTime(b)
+			for i := 0; i < b.N; i++ {
+				time.Sleep(time.Millisecond)
+			}
+			reportResources(b, start, base)
+		})
+	}
+}
+
+func BenchmarkStreamingConnections(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("conns=%d", n), func(b *testing.B) {
+			serial := setupHandshakeTest(n)
+			base := runtime.NumGoroutine()
+			peers, closeAll := openActiveConnections(b, serial, n)
+			defer closeAll()
+
+			frame := NewEspHomeFrame(espHomeHelloRequest, make([]byte, 100)).Encode()
+			queued := queuedFrames(serial)
+			b.SetBytes(int64(len(frame) * n))
+			b.ResetTimer()
+			start := cpuTime(b)
+			for i := 0; i < b.N; i++ {
+				var wg sync.WaitGroup
+				wg.Add(n)
+				for _, peer := range peers {
+					go func(peer net.Conn) {
+						defer wg.Done()
+						if _, err := peer.Write(frame); err != nil {
+							b.Error(err)
+						}
+					}(peer)
+				}
+				wg.Wait()
+				// Every frame is complete when it is queued on the serial
+				queued += n
+				for queuedFrames(serial) < queued {
+					runtime.Gosched()
+				}
+			}
+			reportResources(b, start, base)
+			b.StopTimer()
+			serial.Sessions.Init()
+		})
+	}
+}
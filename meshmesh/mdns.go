@@ -0,0 +1,190 @@
+package meshmesh
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/grandcat/zeroconf"
+	"golang.org/x/exp/slices"
+	"leguru.net/m/v2/logger"
+	"leguru.net/m/v2/utils"
+)
+
+const (
+	mdnsEspHomeService = "_esphomelib._tcp"
+	mdnsDomain         = "local."
+)
+
+// DeviceInfoResponse fields used to fill the TXT records
+const (
+	espHomeDeviceInfoMacFieldNum          = 3
+	espHomeDeviceInfoVersionFieldNum      = 4
+	espHomeDeviceInfoModelFieldNum        = 6
+	espHomeDeviceInfoFriendlyNameFieldNum = 13
+)
+
+type mdnsRecord struct {
+	name   string
+	tag    string
+	bind   []string
+	text   []string
+	server *zeroconf.Server
+}
+
+func (r *mdnsRecord) equal(tag string, bind []string, text []string) bool {
+	return r.tag == tag && slices.Equal(r.bind, bind) && slices.Equal(r.text, text)
+}
+
+// MdnsAdvertiser publish an ESPHome mDNS service for every node with an API server
+type MdnsAdvertiser struct {
+	lock    sync.Mutex
+	records map[MeshNodeId]*mdnsRecord
+}
+
+// mdnsInstanceName convert a node tag in a valid host label
+func mdnsInstanceName(address MeshNodeId, tag string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(tag)) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '-' || r == '_' || r == ' ' || r == '.':
+			b.WriteRune('-')
+		}
+	}
+	name := strings.Trim(b.String(), "-")
+	if name == "" {
+		name = fmt.Sprintf("meshmesh-%06x", uint32(address))
+	}
+	return name
+}
+
+// mdnsTextRecords build the TXT records from the cached device info of the node
+func mdnsTextRecords(address MeshNodeId, cache *EspHomeNodeCache) []string {
+	text := []string{"network=meshmesh", "meshmesh_id=" + utils.FmtNodeId(int64(address))}
+	if cache == nil {
+		return text
+	}
+	info := cache.HandshakeFrame(espHomeDeviceInfoRequest)
+	if info == nil {
+		return text
+	}
+	if mac, ok := info.StringField(espHomeDeviceInfoMacFieldNum); ok && mac != "" {
+		text = append(text, "mac="+strings.ToLower(strings.ReplaceAll(mac, ":", "")))
+	}
+	if version, ok := info.StringField(espHomeDeviceInfoVersionFieldNum); ok && version != "" {
+		text = append(text, "version="+version)
+	}
+	if model, ok := info.StringField(espHomeDeviceInfoModelFieldNum); ok && model != "" {
+		text = append(text, "board="+model)
+	}
+	if friendlyName, ok := info.StringField(espHomeDeviceInfoFriendlyNameFieldNum); ok && friendlyName != "" {
+		text = append(text, "friendly_name="+friendlyName)
+	}
+	return text
+}
+
+func (a *MdnsAdvertiser) nameInUse(address MeshNodeId, name string) bool {
+	for id, record := range a.records {
+		if id != address && record.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Advertise publish the mDNS record of a node server, the record is replaced only if something changed
+func (a *MdnsAdvertiser) Advertise(address MeshNodeId, tag string, listenAddresses []string, text []string) error {
+	if len(listenAddresses) == 0 {
+		return errors.New("no listen address")
+	}
+	port := 0
+	ips := make([]string, 0, len(listenAddresses))
+	anyAddress := false
+	for _, listenAddress := range listenAddresses {
+		host, portStr, err := net.SplitHostPort(listenAddress)
+		if err != nil {
+			return err
+		}
+		port, err = strconv.Atoi(portStr)
+		if err != nil {
+			return err
+		}
+		ip := net.ParseIP(host)
+		if ip == nil || ip.IsUnspecified() {
+			anyAddress = true
+		} else {
+			ips = append(ips, host)
+		}
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if record, ok := a.records[address]; ok {
+		if record.equal(tag, listenAddresses, text) {
+			return nil
+		}
+		a.withdraw(address)
+	}
+
+	name := mdnsInstanceName(address, tag)
+	if a.nameInUse(address, name) {
+		name = fmt.Sprintf("%s-%06x", name, uint32(address))
+	}
+
+	var server *zeroconf.Server
+	var err error
+	if anyAddress {
+		server, err = zeroconf.Register(name, mdnsEspHomeService, mdnsDomain, port, text, nil)
+	} else {
+		server, err = zeroconf.RegisterProxy(name, mdnsEspHomeService, mdnsDomain, port, name, ips, text, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	a.records[address] = &mdnsRecord{name: name, tag: tag, bind: slices.Clone(listenAddresses), text: text, server: server}
+	logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(address)), "name": name, "bind": listenAddresses}).Debug("Advertising node with mDNS")
+	return nil
+}
+
+func (a *MdnsAdvertiser) withdraw(address MeshNodeId) {
+	record, ok := a.records[address]
+	if !ok {
+		return
+	}
+	record.server.Shutdown()
+	delete(a.records, address)
+	logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(address)), "name": record.name}).Debug("Withdrawn mDNS advertisement of node")
+}
+
+// Withdraw remove the mDNS record of a node
+func (a *MdnsAdvertiser) Withdraw(address MeshNodeId) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.withdraw(address)
+}
+
+func (a *MdnsAdvertiser) IsAdvertised(address MeshNodeId) bool {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	_, ok := a.records[address]
+	return ok
+}
+
+func (a *MdnsAdvertiser) Shutdown() {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	for address := range a.records {
+		a.withdraw(address)
+	}
+}
+
+func NewMdnsAdvertiser() *MdnsAdvertiser {
+	return &MdnsAdvertiser{records: make(map[MeshNodeId]*mdnsRecord)}
+}
@@ -14,11 +14,14 @@ import (
 	"go.bug.st/serial"
 	"leguru.net/m/v2/graph"
 	"leguru.net/m/v2/logger"
+	"leguru.net/m/v2/utils"
 )
 
 const defaultSessionMaxTimeoutMs = 500
 const maxSerialInputBuffer = 8192
 
+var errReplyTimeout = errors.New("reply timeout")
+
 type SerialSession struct {
 	Request      *ApiFrame
 	Reply        *ApiFrame
@@ -394,13 +397,17 @@ func (serialConn *SerialConnection) sendReceiveApiProt(session *SerialSession) (
 	}
 
 	if session.Reply == nil {
-		return nil, errors.New("reply timeout")
+		return nil, errReplyTimeout
 	} else {
 		return session.Reply.Decode()
 	}
 }
 
 func (serialConn *SerialConnection) SendReceiveApiProt(cmd any, protocol MeshProtocol, target MeshNodeId, network *graph.Network) (interface{}, error) {
+	return serialConn.SendReceiveApiProtTimeout(cmd, protocol, target, network, defaultSessionMaxTimeoutMs)
+}
+
+func (serialConn *SerialConnection) SendReceiveApiProtTimeout(cmd interface{}, protocol MeshProtocol, target MeshNodeId, network *graph.Network, timeoutMs int64) (interface{}, error) {
 	if target == 0 {
 		protocol = DirectProtocol
 	}
@@ -409,30 +416,50 @@ func (serialConn *SerialConnection) SendReceiveApiProt(cmd any, protocol MeshPro
 		return nil, err
 	}
 
-	session, err := NewSerialSession(frame)
-	if err != nil {
-		return nil, err
+	reply, err := serialConn.sendReceiveFrame(frame, target, timeoutMs)
+	if !errors.Is(err, errReplyTimeout) || protocol != MultipathProtocol {
+		return reply, err
 	}
 
-	return serialConn.sendReceiveApiProt(session)
-}
-
-func (serialConn *SerialConnection) SendReceiveApiProtTimeout(cmd interface{}, protocol MeshProtocol, target MeshNodeId, network *graph.Network, timeoutMs int64) (interface{}, error) {
-	if target == 0 {
-		protocol = DirectProtocol
-	}
-	frame, err := NewApiFrameFromStruct(cmd, protocol, target, network)
-	if err != nil {
-		return nil, err
+	// The node doesn't answer on its best route, try the alternative ones
+	for _, path := range alternativePaths(network, target) {
+		var ferr error
+		if len(path) == 2 {
+			frame, ferr = NewApiFrameFromStruct(cmd, UnicastProtocol, target, nil)
+		} else {
+			frame, ferr = newMultipathFrame(cmd, target, path)
+		}
+		if ferr != nil {
+			continue
+		}
+		reply, err = serialConn.sendReceiveFrame(frame, target, timeoutMs)
+		if !errors.Is(err, errReplyTimeout) {
+			if err == nil {
+				logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(target)), "path": utils.FmtPath2Str(path)}).Info("Node reached on an alternative route")
+			}
+			return reply, err
+		}
 	}
+	return nil, err
+}
 
+func (serialConn *SerialConnection) sendReceiveFrame(frame *ApiFrame, target MeshNodeId, timeoutMs int64) (interface{}, error) {
 	session, err := NewSerialSession(frame)
 	if err != nil {
 		return nil, err
 	}
 
 	session.MaxTimeoutMs = timeoutMs
-	return serialConn.sendReceiveApiProt(session)
+	return serialConn.sendReceiveApiProtSeen(session, target)
+}
+
+// sendReceiveApiProtSeen update the last seen time of the target when it replies
+func (serialConn *SerialConnection) sendReceiveApiProtSeen(session *SerialSession, target MeshNodeId) (interface{}, error) {
+	reply, err := serialConn.sendReceiveApiProt(session)
+	if err == nil && target != 0 {
+		graph.MarkNodeSeen(int64(target), time.Now())
+	}
+	return reply, err
 }
 
 func (serialConn *SerialConnection) SendReceiveApi(cmd interface{}) (interface{}, error) {
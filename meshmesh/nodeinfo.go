@@ -0,0 +1,30 @@
+package meshmesh
+
+import (
+	gra "leguru.net/m/v2/graph"
+)
+
+func (r NodeConfigApiReply) NodeConfig() gra.NodeConfig {
+	return gra.NodeConfig{
+		Channel:      r.Channel,
+		TxPower:      r.TxPower,
+		Groups:       r.Groups,
+		BindedServer: r.BindedServer,
+		Flags:        r.Flags,
+	}
+}
+
+// UpdateNodeInfo store the firmware revision and the configuration read from a node in the main
+// network, the change is notified only when they differ from the stored ones.
+func UpdateNodeInfo(node MeshNodeId, revision string, config gra.NodeConfig) {
+	dev, err := gra.GetMainNetwork().GetNodeDevice(int64(node))
+	if err != nil {
+		return
+	}
+	if stored, ok := dev.Device().Config(); ok && stored == config && dev.Device().Firmware() == revision {
+		return
+	}
+	dev.Device().SetFirmware(revision)
+	dev.Device().SetConfig(config)
+	gra.NotifyMainNetworkChanged()
+}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"net"
-	"os"
 	"time"
 
 	"leguru.net/m/v2/logger"
@@ -15,6 +14,7 @@ import (
 
 type OtaConnection struct {
 	NetworkConnectionStruct
+	session *OtaSession
 }
 
 func (client *OtaConnection) startHandshake(addr MeshNodeId, port int) error {
@@ -33,6 +33,7 @@ func (client *OtaConnection) startHandshake(addr MeshNodeId, port int) error {
 
 func (client *OtaConnection) FinishHandshake(result bool) {
 	logger.WithField("res", result).Debug("OtaConnection.FinishHandshake")
+	handshakes.Done(client)
 	if !result {
 		logger.WithFields(logger.Fields{"addr": client.reqAddress, "port": client.reqPort, "err": nil}).
 			Warning("OtaConnection.FinishHandshake failed")
@@ -77,72 +78,48 @@ func (client *OtaConnection) SetClosedCallback(cb func(client NetworkConnection)
 func (client *OtaConnection) Close() {
 	client.socketOpen = false
 	client.socket.Close()
+	handshakes.Done(client)
 	utils.ForceDebug(client.debugThisNode, "Waiting for read go-routine to terminate...")
 	client.socketWaitGroup.Wait()
 	client.Stats.Stop()
 	client.meshprotocol.Disconnect()
+	client.session.Close()
 	client.clientClosed(client)
 }
 
-func (client *OtaConnection) CheckTimeout() {
-	for {
-		if !client.socketOpen {
-			break
-		}
-		if client.meshprotocol.connState == connPathConnectionStateInit || client.meshprotocol.connState == connPathConnectionStateHandshakeStarted {
-			if time.Since(client.timeout).Milliseconds() > 3000 {
-				logger.Error(fmt.Sprintf("Closing connection beacuse timeout after %dms in connPathConnectionStateInit for handle %d", time.Since(client.timeout).Milliseconds(), client.meshprotocol.handle))
-				client.Close()
-			}
-		}
-		time.Sleep(100 * time.Millisecond)
-	}
-
-	logger.Debug("ApiConnection.CheckTimeout exited")
-}
-
 func (client *OtaConnection) Read() {
 	var n int
 	var err error
 
+	var buffer = make([]byte, networkReadBufferSize)
 	for {
-		var buffer = make([]byte, 1)
-		client.socket.SetReadDeadline(time.Now().Add(10 * time.Millisecond))
-
 		n, err = client.socket.Read(buffer)
 		client.Stats.ReceivedBytes(n)
 
-		/*if n > 0 {
-			logger.WithFields(logger.Fields{"handle": client.socket.RemoteAddr().String(), "n": n, "bytes": utils.EncodeToHexEllipsis(buffer, 10)}).Debug("OtaConnection.Read")
-		}*/
-
-		if err == io.EOF {
-			logger.WithFields(logger.Fields{"handle": client.socket.RemoteAddr().String()}).Warn("OtaConnection.Read connection closed by peer")
-			break
-		}
-
-		if err != nil {
-			if !errors.Is(err, os.ErrDeadlineExceeded) {
-				logger.WithFields(logger.Fields{"handle": client.socket.RemoteAddr().String(), "err": err}).Error("OtaConnection.Read error")
-				break
-			}
-		}
-
 		if n > 0 {
+			client.session.ClientData(buffer[:n])
 			switch client.meshprotocol.connState {
 			case connPathConnectionStateHandshakeStarted:
 				// FIXME check for if buffer grown outside limits
-				client.tmpBuffer.WriteByte(buffer[0])
+				client.tmpBuffer.Write(buffer[:n])
 			case connPathConnectionStateActive:
-				// FIXME handle error
-				client.inBuffer.WriteByte(buffer[0])
+				// A read returns all the data available, send it now
+				client.inBuffer.Write(buffer[:n])
+				client.flushBuffer(client.inBuffer)
 			default:
 				logger.WithField("state", client.meshprotocol.connState).
 					Error(fmt.Errorf("readed data while in wrong connection state %d", client.meshprotocol.connState))
 			}
-		} else {
-			// No timeout if we don't have new data to send, send it now
-			client.flushBuffer(client.inBuffer)
+		}
+
+		if err == io.EOF {
+			logger.WithFields(logger.Fields{"handle": client.socket.RemoteAddr().String()}).Warn("OtaConnection.Read connection closed by peer")
+			break
+		}
+
+		if err != nil {
+			logger.WithFields(logger.Fields{"handle": client.socket.RemoteAddr().String(), "err": err}).Error("OtaConnection.Read error")
+			break
 		}
 	}
 
@@ -159,6 +136,7 @@ func (client *OtaConnection) ForwardData(data []byte) error {
 		"len":    len(data),
 		"data":   utils.EncodeToHexEllipsis(data, 10),
 	}).Trace("SE-->HA")
+	client.session.DeviceData(data)
 	n, err := client.socket.Write(data)
 	if err != nil {
 		return err
@@ -172,18 +150,26 @@ func (client *OtaConnection) ForwardData(data []byte) error {
 }
 
 func NewOtaConnection(connection net.Conn, serial *SerialConnection, addr MeshNodeId, port int, closedCb func(NetworkConnection)) (*OtaConnection, error) {
+	// Only one update at time can be running on a node
+	session, err := otaSessions.Start(addr, connection.RemoteAddr().String())
+	if err != nil {
+		return nil, err
+	}
+
 	client := &OtaConnection{
 		NetworkConnectionStruct: NewNetworkConnectionStruct(connection, serial, addr, port, closedCb),
+		session:                 session,
 	}
 
-	err := client.startHandshake(addr, port)
+	err = client.startHandshake(addr, port)
 	if err != nil {
+		session.Close()
 		return nil, err
 	}
 
 	client.socketWaitGroup.Add(1)
+	handshakes.Watch(client)
 	go client.Read()
-	go client.CheckTimeout()
 
 	return client, nil
 }
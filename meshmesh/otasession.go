@@ -0,0 +1,463 @@
+package meshmesh
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	"leguru.net/m/v2/logger"
+	"leguru.net/m/v2/utils"
+)
+
+// ESPHome OTA protocol responses (see esphome/components/ota/ota_backend.h)
+const (
+	espOtaResponseOk                  byte = 0x00
+	espOtaResponseRequestAuth         byte = 0x01
+	espOtaResponseRequestSha256Auth   byte = 0x02
+	espOtaResponseHeaderOk            byte = 0x40
+	espOtaResponseAuthOk              byte = 0x41
+	espOtaResponseUpdatePrepareOk     byte = 0x42
+	espOtaResponseBinMd5Ok            byte = 0x43
+	espOtaResponseReceiveOk           byte = 0x44
+	espOtaResponseUpdateEndOk         byte = 0x45
+	espOtaResponseSupportsCompression byte = 0x46
+	espOtaResponseChunkOk             byte = 0x47
+	espOtaResponseErrorFirst          byte = 0x80
+)
+
+var espOtaMagic = []byte{0x6C, 0x26, 0xF7, 0x5C, 0x45}
+
+var espOtaErrors = map[byte]string{
+	0x80: "invalid magic",
+	0x81: "update prepare failed",
+	0x82: "invalid authentication",
+	0x83: "error writing flash",
+	0x84: "update end failed",
+	0x85: "invalid bootstrapping",
+	0x86: "wrong current flash config",
+	0x87: "wrong new flash config",
+	0x88: "esp8266 not enough space",
+	0x89: "esp32 not enough space",
+	0x8A: "no update partition",
+	0x8B: "md5 mismatch",
+	0x8C: "rp2040 not enough space",
+	0xFF: "unknown error",
+}
+
+const otaSessionStallTimeout = 10 * time.Second
+const otaSessionsHistory = 32
+
+type OtaSessionState int
+
+const (
+	OtaSessionStateHandshake OtaSessionState = iota
+	OtaSessionStateAuth
+	OtaSessionStateTransfer
+	OtaSessionStateFinishing
+	OtaSessionStateSuccess
+	OtaSessionStateFailed
+)
+
+func (s OtaSessionState) String() string {
+	switch s {
+	case OtaSessionStateHandshake:
+		return "handshake"
+	case OtaSessionStateAuth:
+		return "auth"
+	case OtaSessionStateTransfer:
+		return "transfer"
+	case OtaSessionStateFinishing:
+		return "finishing"
+	case OtaSessionStateSuccess:
+		return "success"
+	case OtaSessionStateFailed:
+		return "failed"
+	}
+	return "unknown"
+}
+
+// Steps of the device side of the protocol
+const (
+	otaDeviceWaitMagicOk = iota
+	otaDeviceWaitVersion
+	otaDeviceWaitFeatures
+	otaDeviceWaitAuth
+	otaDeviceWaitNonce
+	otaDeviceWaitAuthOk
+	otaDeviceWaitPrepare
+	otaDeviceWaitMd5
+	otaDeviceWaitReceive
+	otaDeviceWaitEnd
+	otaDeviceDone
+)
+
+// Steps of the client side of the protocol
+const (
+	otaClientMagic = iota
+	otaClientFeatures
+	otaClientAuth
+	otaClientSize
+	otaClientMd5
+	otaClientData
+	otaClientDone
+)
+
+// OtaSessionInfo is a snapshot of an OTA session
+type OtaSessionInfo struct {
+	Id          int
+	Node        MeshNodeId
+	Source      string
+	Client      string
+	State       OtaSessionState
+	Version     byte
+	Compression bool
+	Auth        bool
+	Size        uint32
+	Md5         string
+	Sent        uint32
+	Started     time.Time
+	LastData    time.Time
+	Finished    time.Time
+	Error       string
+}
+
+func (i *OtaSessionInfo) Percent() float64 {
+	if i.Size == 0 {
+		return 0
+	}
+	return float64(i.Sent) / float64(i.Size)
+}
+
+func (i *OtaSessionInfo) IsActive() bool {
+	return i.State != OtaSessionStateSuccess && i.State != OtaSessionStateFailed
+}
+
+// IsStalled reports if an active session did not exchange data for a while
+func (i *OtaSessionInfo) IsStalled() bool {
+	return i.IsActive() && time.Since(i.LastData) > otaSessionStallTimeout
+}
+
+// OtaSession passively follow the ESPHome OTA protocol exchanged between a client and a node
+type OtaSession struct {
+	lock        sync.Mutex
+	info        OtaSessionInfo
+	client      bytes.Buffer
+	clientStep  int
+	clientSkip  int
+	deviceStep  int
+	deviceSkip  int
+	unsupported bool
+	changed     bool
+	owner       *OtaLock
+	closed      bool
+}
+
+func (s *OtaSession) Info() OtaSessionInfo {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.info
+}
+
+func (s *OtaSession) fail(reason string) {
+	if !s.info.IsActive() {
+		return
+	}
+	s.info.State = OtaSessionStateFailed
+	s.info.Error = reason
+	s.info.Finished = time.Now()
+	s.changed = true
+}
+
+func (s *OtaSession) succeed() {
+	s.info.State = OtaSessionStateSuccess
+	s.info.Finished = time.Now()
+	s.changed = true
+}
+
+// unlock release the session and notify the listeners if something relevant changed
+func (s *OtaSession) unlock() {
+	info, changed := s.info, s.changed
+	s.changed = false
+	s.lock.Unlock()
+	if changed {
+		otaSessions.notify(info)
+	}
+}
+
+// ClientData follow the bytes sent by the client to the node
+func (s *OtaSession) ClientData(data []byte) {
+	s.lock.Lock()
+	defer s.unlock()
+	if s.unsupported || !s.info.IsActive() {
+		return
+	}
+	s.info.LastData = time.Now()
+
+	for len(data) > 0 {
+		if s.clientStep == otaClientData {
+			percent := int(s.info.Percent() * 100)
+			n := min(uint32(len(data)), s.info.Size-s.info.Sent)
+			s.info.Sent += n
+			data = data[n:]
+			if s.info.Sent >= s.info.Size {
+				s.clientStep = otaClientDone
+				s.info.State = OtaSessionStateFinishing
+			}
+			// Progress is notified at every percent step
+			if percent != int(s.info.Percent()*100) || s.clientStep == otaClientDone {
+				s.changed = true
+			}
+			continue
+		}
+		if s.clientStep == otaClientDone {
+			return
+		}
+
+		s.client.WriteByte(data[0])
+		data = data[1:]
+
+		switch s.clientStep {
+		case otaClientMagic:
+			if s.client.Len() == len(espOtaMagic) {
+				if !bytes.Equal(s.client.Bytes(), espOtaMagic) {
+					s.unsupported = true
+					logger.WithField("node", utils.FmtNodeId(int64(s.info.Node))).Warn("Unknown OTA protocol, progress will not be reported")
+					return
+				}
+				s.client.Reset()
+				s.clientStep = otaClientFeatures
+			}
+		case otaClientFeatures:
+			s.client.Reset()
+			s.clientStep = otaClientAuth
+		case otaClientAuth:
+			// The auth reply length is known only after the device request
+			if s.clientSkip > 0 {
+				s.clientSkip--
+				s.client.Reset()
+				break
+			}
+			s.clientStep = otaClientSize
+			fallthrough
+		case otaClientSize:
+			if s.client.Len() == 4 {
+				s.info.Size = binary.BigEndian.Uint32(s.client.Bytes())
+				s.client.Reset()
+				s.clientStep = otaClientMd5
+			}
+		case otaClientMd5:
+			if s.client.Len() == 32 {
+				s.info.Md5 = s.client.String()
+				s.client.Reset()
+				s.clientStep = otaClientData
+				s.info.State = OtaSessionStateTransfer
+				logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(s.info.Node)), "size": s.info.Size, "md5": s.info.Md5}).Info("OTA transfer started")
+				s.changed = true
+				if s.info.Size == 0 {
+					s.clientStep = otaClientDone
+				}
+			}
+		}
+	}
+}
+
+// DeviceData follow the bytes sent by the node to the client
+func (s *OtaSession) DeviceData(data []byte) {
+	s.lock.Lock()
+	defer s.unlock()
+	if s.unsupported || !s.info.IsActive() {
+		return
+	}
+	s.info.LastData = time.Now()
+
+	for _, b := range data {
+		if s.deviceStep == otaDeviceWaitNonce {
+			s.deviceSkip--
+			if s.deviceSkip <= 0 {
+				s.deviceStep = otaDeviceWaitAuthOk
+			}
+			continue
+		}
+		if s.deviceStep != otaDeviceWaitVersion && b >= espOtaResponseErrorFirst {
+			reason, ok := espOtaErrors[b]
+			if !ok {
+				reason = fmt.Sprintf("error 0x%02X", b)
+			}
+			logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(s.info.Node)), "err": reason}).Warn("OTA failed on node")
+			s.fail(reason)
+			return
+		}
+
+		switch s.deviceStep {
+		case otaDeviceWaitMagicOk:
+			if b == espOtaResponseOk {
+				s.deviceStep = otaDeviceWaitVersion
+			}
+		case otaDeviceWaitVersion:
+			s.info.Version = b
+			s.deviceStep = otaDeviceWaitFeatures
+		case otaDeviceWaitFeatures:
+			s.info.Compression = b == espOtaResponseSupportsCompression
+			s.deviceStep = otaDeviceWaitAuth
+			s.info.State = OtaSessionStateAuth
+			s.changed = true
+		case otaDeviceWaitAuth:
+			switch b {
+			case espOtaResponseRequestAuth:
+				s.info.Auth = true
+				s.deviceStep, s.deviceSkip, s.clientSkip = otaDeviceWaitNonce, 32, 64
+			case espOtaResponseRequestSha256Auth:
+				s.info.Auth = true
+				s.deviceStep, s.deviceSkip, s.clientSkip = otaDeviceWaitNonce, 64, 128
+			default:
+				s.deviceStep = otaDeviceWaitPrepare
+			}
+		case otaDeviceWaitAuthOk:
+			if b == espOtaResponseAuthOk {
+				s.deviceStep = otaDeviceWaitPrepare
+			}
+		case otaDeviceWaitPrepare:
+			if b == espOtaResponseUpdatePrepareOk {
+				s.deviceStep = otaDeviceWaitMd5
+			}
+		case otaDeviceWaitMd5:
+			if b == espOtaResponseBinMd5Ok {
+				s.deviceStep = otaDeviceWaitReceive
+			}
+		case otaDeviceWaitReceive:
+			if b == espOtaResponseReceiveOk {
+				s.deviceStep = otaDeviceWaitEnd
+				s.info.State = OtaSessionStateFinishing
+			}
+		case otaDeviceWaitEnd:
+			if b == espOtaResponseUpdateEndOk {
+				s.deviceStep = otaDeviceDone
+				logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(s.info.Node)), "size": s.info.Size}).Info("OTA completed on node")
+				s.succeed()
+			}
+		}
+	}
+}
+
+// Close terminate the session, if the update was not completed it is marked as failed
+func (s *OtaSession) Close() {
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		return
+	}
+	s.closed = true
+	node, owner := s.info.Node, s.owner
+	if s.unsupported {
+		s.fail("unknown protocol")
+	} else {
+		s.fail("connection closed")
+	}
+	s.unlock()
+	otaSessions.Release(node, owner)
+}
+
+// OtaSessions keep the OTA sessions history and the per node update lock shared by the
+// ESPHome OTA connections and the hub firmware upload procedure.
+type OtaSessions struct {
+	lock      sync.Mutex
+	nextId    int
+	sessions  []*OtaSession
+	busy      map[MeshNodeId]*OtaLock
+	callbacks []func(info OtaSessionInfo)
+}
+
+// OtaLock is the reservation of the update of a node, only its holder can release it
+type OtaLock struct {
+	Source string
+}
+
+var otaSessions = &OtaSessions{busy: make(map[MeshNodeId]*OtaLock)}
+
+// GetOtaSessions returns the global OTA sessions registry
+func GetOtaSessions() *OtaSessions {
+	return otaSessions
+}
+
+// AddCallback register a function called at every progress or state change of a session
+func (o *OtaSessions) AddCallback(cb func(info OtaSessionInfo)) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.callbacks = append(o.callbacks, cb)
+}
+
+func (o *OtaSessions) notify(info OtaSessionInfo) {
+	o.lock.Lock()
+	callbacks := o.callbacks
+	o.lock.Unlock()
+	for _, cb := range callbacks {
+		cb(info)
+	}
+}
+
+// Acquire reserve the update of a node for the given source, the returned lock is needed to release it
+func (o *OtaSessions) Acquire(node MeshNodeId, source string) (*OtaLock, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	if owner, ok := o.busy[node]; ok {
+		return nil, fmt.Errorf("update of node %s already in progress from %s", utils.FmtNodeId(int64(node)), owner.Source)
+	}
+	owner := &OtaLock{Source: source}
+	o.busy[node] = owner
+	return owner, nil
+}
+
+// Release free the node reservation if it is still held by owner
+func (o *OtaSessions) Release(node MeshNodeId, owner *OtaLock) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	if o.busy[node] == owner {
+		delete(o.busy, node)
+	}
+}
+
+// Start reserve the node and create a new session to follow an ESPHome OTA
+func (o *OtaSessions) Start(node MeshNodeId, client string) (*OtaSession, error) {
+	owner, err := o.Acquire(node, "esphome")
+	if err != nil {
+		return nil, err
+	}
+
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.nextId++
+	now := time.Now()
+	session := &OtaSession{info: OtaSessionInfo{Id: o.nextId, Node: node, Source: "esphome", Client: client, Started: now, LastData: now}, owner: owner}
+	o.sessions = append(o.sessions, session)
+	if len(o.sessions) > otaSessionsHistory {
+		o.sessions = o.sessions[1:]
+	}
+	return session, nil
+}
+
+// Sessions returns a snapshot of the recent sessions
+func (o *OtaSessions) Sessions() []OtaSessionInfo {
+	o.lock.Lock()
+	sessions := make([]*OtaSession, len(o.sessions))
+	copy(sessions, o.sessions)
+	o.lock.Unlock()
+
+	infos := make([]OtaSessionInfo, len(sessions))
+	for i, session := range sessions {
+		infos[i] = session.Info()
+	}
+	return infos
+}
+
+// Busy returns who is updating the node, if any
+func (o *OtaSessions) Busy(node MeshNodeId) (string, bool) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	owner, ok := o.busy[node]
+	if !ok {
+		return "", false
+	}
+	return owner.Source, true
+}
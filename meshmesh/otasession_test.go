@@ -0,0 +1,163 @@
+package meshmesh
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"leguru.net/m/v2/logger"
+)
+
+// otaStep is a chunk of the OTA stream, sent by the client or by the node
+type otaStep struct {
+	client bool
+	data   []byte
+}
+
+const otaTestMd5 = "0123456789abcdef0123456789abcdef"
+
+// otaHandshake returns the stream up to the start of the transfer of size bytes, with a md5 authentication if auth is set
+func otaHandshake(size uint32, auth bool, authResult byte) []otaStep {
+	steps := []otaStep{
+		{client: true, data: append(append([]byte{}, espOtaMagic...), 0x01)},
+		{data: []byte{espOtaResponseOk, 0x02, espOtaResponseSupportsCompression}},
+	}
+	if auth {
+		steps = append(steps,
+			otaStep{data: append([]byte{espOtaResponseRequestAuth}, bytes.Repeat([]byte{'n'}, 32)...)},
+			otaStep{client: true, data: bytes.Repeat([]byte{'c'}, 64)},
+			otaStep{data: []byte{authResult}},
+		)
+	} else {
+		steps = append(steps, otaStep{data: []byte{espOtaResponseAuthOk}})
+	}
+	header := binary.BigEndian.AppendUint32(nil, size)
+	return append(steps,
+		otaStep{client: true, data: append(header, otaTestMd5...)},
+		otaStep{data: []byte{espOtaResponseUpdatePrepareOk, espOtaResponseBinMd5Ok}},
+	)
+}
+
+func TestOtaSessionStream(t *testing.T) {
+	logger.SetLevel(logrus.ErrorLevel)
+	firmware := bytes.Repeat([]byte{0xAA}, 1000)
+
+	tests := []struct {
+		name  string
+		steps []otaStep
+		state OtaSessionState
+		err   string
+		auth  bool
+		size  uint32
+		sent  uint32
+	}{
+		{
+			name: "success",
+			steps: append(otaHandshake(1000, false, 0),
+				otaStep{client: true, data: firmware[:300]},
+				otaStep{client: true, data: firmware[300:]},
+				otaStep{data: []byte{espOtaResponseReceiveOk, espOtaResponseUpdateEndOk}},
+			),
+			state: OtaSessionStateSuccess, size: 1000, sent: 1000,
+		},
+		{
+			name: "success with auth",
+			steps: append(otaHandshake(1000, true, espOtaResponseAuthOk),
+				otaStep{client: true, data: firmware},
+				otaStep{data: []byte{espOtaResponseReceiveOk, espOtaResponseUpdateEndOk}},
+			),
+			state: OtaSessionStateSuccess, auth: true, size: 1000, sent: 1000,
+		},
+		{
+			name:  "auth failure",
+			steps: otaHandshake(1000, true, 0x82)[:5],
+			state: OtaSessionStateFailed, err: "invalid authentication", auth: true,
+		},
+		{
+			name: "truncated transfer",
+			steps: append(otaHandshake(1000, false, 0),
+				otaStep{client: true, data: firmware[:400]},
+			),
+			state: OtaSessionStateFailed, err: "connection closed", size: 1000, sent: 400,
+		},
+		{
+			name: "error result",
+			steps: append(otaHandshake(1000, false, 0),
+				otaStep{client: true, data: firmware},
+				otaStep{data: []byte{0x8B}},
+			),
+			state: OtaSessionStateFailed, err: "md5 mismatch", size: 1000, sent: 1000,
+		},
+		{
+			name:  "unknown protocol",
+			steps: []otaStep{{client: true, data: []byte("GET / HTTP/1.1\r\n")}},
+			state: OtaSessionStateFailed, err: "unknown protocol",
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session, err := otaSessions.Start(MeshNodeId(0x100+i), "test")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, step := range tt.steps {
+				if step.client {
+					session.ClientData(step.data)
+				} else {
+					session.DeviceData(step.data)
+				}
+			}
+			session.Close()
+
+			info := session.Info()
+			if info.State != tt.state || info.Error != tt.err {
+				t.Fatalf("state %s %q, want %s %q", info.State, info.Error, tt.state, tt.err)
+			}
+			if info.Auth != tt.auth || info.Size != tt.size || info.Sent != tt.sent {
+				t.Fatalf("auth %v size %d sent %d, want %v %d %d", info.Auth, info.Size, info.Sent, tt.auth, tt.size, tt.sent)
+			}
+			if tt.size > 0 && info.Md5 != otaTestMd5 {
+				t.Fatalf("md5 %q, want %q", info.Md5, otaTestMd5)
+			}
+			if _, busy := otaSessions.Busy(info.Node); busy {
+				t.Fatal("node still reserved after the session closed")
+			}
+		})
+	}
+}
+
+func TestOtaSessionExcludesFirmwareUpload(t *testing.T) {
+	logger.SetLevel(logrus.ErrorLevel)
+	node := MeshNodeId(0x200)
+
+	session, err := otaSessions.Start(node, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	upload := NewFirmwareUploadProcedure(nil, nil, node)
+	if err := upload.Start([]byte{0x00}); err == nil {
+		t.Fatal("firmware upload started during an OTA on the same node")
+	}
+	if upload.IsRunning() {
+		t.Fatal("refused firmware upload left running")
+	}
+	if source, _ := otaSessions.Busy(node); source != "esphome" {
+		t.Fatalf("node reserved by %q, want esphome", source)
+	}
+
+	// The hub reservation blocks the ESPHome OTA in the same way
+	session.Close()
+	owner, err := otaSessions.Acquire(node, "hub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := otaSessions.Start(node, "test"); err == nil {
+		t.Fatal("OTA started during a firmware upload on the same node")
+	}
+	otaSessions.Release(node, owner)
+	if _, busy := otaSessions.Busy(node); busy {
+		t.Fatal("node still reserved after the upload released it")
+	}
+}
@@ -0,0 +1,282 @@
+package meshmesh
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/exp/slices"
+	"leguru.net/m/v2/logger"
+	"leguru.net/m/v2/utils"
+)
+
+const portAllocationMaxPort = 65535
+
+// PortAllocation is the bind address and ports assigned to the servers of a node
+type PortAllocation struct {
+	Node          MeshNodeId `json:"node"`
+	BindAddresses []string   `json:"bind_addresses"`
+	ApiPort       int        `json:"api_port"`
+	OtaPort       int        `json:"ota_port"`
+	Manual        bool       `json:"manual"`
+}
+
+// ListenAddresses returns the host:port addresses of the API or OTA server of the node
+func (a *PortAllocation) ListenAddresses(remotePort int) []string {
+	port := a.ApiPort
+	if remotePort == fixedOtaRemotePort {
+		port = a.OtaPort
+	}
+	return joinHostsPort(a.BindAddresses, port)
+}
+
+func joinHostsPort(hosts []string, port int) []string {
+	addresses := make([]string, len(hosts))
+	for i, host := range hosts {
+		addresses[i] = net.JoinHostPort(host, strconv.Itoa(port))
+	}
+	return addresses
+}
+
+// ResolveBindAddresses expand a comma separated list of bind addresses for a node. The "dynamic"
+// keyword maps the node id to a loopback IPv4 address and "dynamic6" to an address of ipv6Prefix.
+func ResolveBindAddresses(bindAddress string, ipv6Prefix string, address MeshNodeId) ([]string, error) {
+	if bindAddress == "" {
+		bindAddress = "dynamic"
+	}
+	hosts := make([]string, 0)
+	for _, host := range strings.Split(bindAddress, ",") {
+		host = strings.Trim(strings.TrimSpace(host), "[]")
+		switch host {
+		case "":
+			continue
+		case "dynamic":
+			host = utils.FmtNodeIdHass(int64(address))
+		case "dynamic6":
+			var err error
+			host, err = utils.FmtNodeIdHassIPv6(ipv6Prefix, int64(address))
+			if err != nil {
+				return nil, err
+			}
+		default:
+			if net.ParseIP(host) == nil {
+				return nil, fmt.Errorf("invalid bind address %s", host)
+			}
+		}
+		if !slices.Contains(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+	if len(hosts) == 0 {
+		return nil, errors.New("no bind address")
+	}
+	return hosts, nil
+}
+
+// PortAllocationTable keep the ports assigned to the nodes servers stable across restarts.
+// The first assignment is derived from the node id and the collisions are resolved probing
+// the next free port of the pool.
+type PortAllocationTable struct {
+	lock           sync.Mutex
+	Nodes          map[MeshNodeId]*PortAllocation `json:"nodes"`
+	filename       string
+	bindAddress    string
+	ipv6Prefix     string
+	bindPort       int
+	basePortOffset int
+	sizeOfPool     int
+}
+
+// bindAddressOverlap reports if two listeners on the same port would conflict
+func bindAddressOverlap(a string, b string) bool {
+	ipa := net.ParseIP(a)
+	ipb := net.ParseIP(b)
+	if ipa == nil || ipb == nil || ipa.IsUnspecified() || ipb.IsUnspecified() {
+		return true
+	}
+	return ipa.Equal(ipb)
+}
+
+func bindAddressesOverlap(a []string, b []string) bool {
+	for _, hostA := range a {
+		for _, hostB := range b {
+			if bindAddressOverlap(hostA, hostB) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (t *PortAllocationTable) portInUse(address MeshNodeId, bindAddresses []string, port int) bool {
+	for id, alloc := range t.Nodes {
+		if id == address || !bindAddressesOverlap(alloc.BindAddresses, bindAddresses) {
+			continue
+		}
+		if alloc.ApiPort == port || alloc.OtaPort == port {
+			return true
+		}
+	}
+	return false
+}
+
+// findFreePort probe the candidates ports starting from the preferred one
+func (t *PortAllocationTable) findFreePort(address MeshNodeId, bindAddresses []string, preferred int, exclude int) (int, error) {
+	poolStart, poolSize := t.basePortOffset, t.sizeOfPool
+	if preferred < poolStart || preferred >= poolStart+poolSize {
+		poolStart, poolSize = preferred, portAllocationMaxPort-preferred+1
+	}
+	for i := 0; i < poolSize; i++ {
+		port := poolStart + (preferred-poolStart+i)%poolSize
+		if port != exclude && !t.portInUse(address, bindAddresses, port) {
+			return port, nil
+		}
+	}
+	for port := poolStart + poolSize; port <= portAllocationMaxPort; port++ {
+		if port != exclude && !t.portInUse(address, bindAddresses, port) {
+			return port, nil
+		}
+	}
+	return 0, errors.New("no free port available")
+}
+
+func (t *PortAllocationTable) assign(address MeshNodeId) (*PortAllocation, error) {
+	bindAddresses, err := ResolveBindAddresses(t.bindAddress, t.ipv6Prefix, address)
+	if err != nil {
+		return nil, err
+	}
+
+	apiPort := t.bindPort
+	if apiPort <= 0 {
+		apiPort = utils.HashString(utils.FmtNodeId(int64(address)), t.sizeOfPool) + t.basePortOffset
+	}
+	apiPort, err = t.findFreePort(address, bindAddresses, apiPort, 0)
+	if err != nil {
+		return nil, err
+	}
+	otaPort, err := t.findFreePort(address, bindAddresses, fixedOtaRemotePort, apiPort)
+	if err != nil {
+		return nil, err
+	}
+	return &PortAllocation{Node: address, BindAddresses: bindAddresses, ApiPort: apiPort, OtaPort: otaPort}, nil
+}
+
+// Allocation returns the allocation of a node, assigning a new one on the first request
+func (t *PortAllocationTable) Allocation(address MeshNodeId) (PortAllocation, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if alloc, ok := t.Nodes[address]; ok {
+		return *alloc, nil
+	}
+
+	alloc, err := t.assign(address)
+	if err != nil {
+		return PortAllocation{}, err
+	}
+	t.Nodes[address] = alloc
+	logger.WithFields(logger.Fields{"node": utils.FmtNodeId(int64(address)), "bind": alloc.BindAddresses, "api": alloc.ApiPort, "ota": alloc.OtaPort}).Info("Assigned esphome ports to node")
+	t.save()
+	return *alloc, nil
+}
+
+// SetOverride manually assign the bind addresses and the ports of a node
+func (t *PortAllocationTable) SetOverride(address MeshNodeId, bindAddresses []string, apiPort int, otaPort int) (PortAllocation, error) {
+	if apiPort <= 0 || apiPort > portAllocationMaxPort || otaPort <= 0 || otaPort > portAllocationMaxPort {
+		return PortAllocation{}, errors.New("port out of range")
+	}
+	if apiPort == otaPort {
+		return PortAllocation{}, errors.New("api and ota ports must be different")
+	}
+	bindAddresses, err := ResolveBindAddresses(strings.Join(bindAddresses, ","), t.ipv6Prefix, address)
+	if err != nil {
+		return PortAllocation{}, err
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.portInUse(address, bindAddresses, apiPort) {
+		return PortAllocation{}, fmt.Errorf("port %d already assigned to another node", apiPort)
+	}
+	if t.portInUse(address, bindAddresses, otaPort) {
+		return PortAllocation{}, fmt.Errorf("port %d already assigned to another node", otaPort)
+	}
+
+	alloc := &PortAllocation{Node: address, BindAddresses: bindAddresses, ApiPort: apiPort, OtaPort: otaPort, Manual: true}
+	t.Nodes[address] = alloc
+	t.save()
+	return *alloc, nil
+}
+
+// Reset drop the allocation of a node, a new automatic one is assigned on the next request
+func (t *PortAllocationTable) Reset(address MeshNodeId) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	delete(t.Nodes, address)
+	t.save()
+}
+
+// Allocations returns a copy of the table sorted by node id
+func (t *PortAllocationTable) Allocations() []PortAllocation {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	allocs := make([]PortAllocation, 0, len(t.Nodes))
+	for _, alloc := range t.Nodes {
+		allocs = append(allocs, *alloc)
+	}
+	sort.Slice(allocs, func(i, j int) bool { return allocs[i].Node < allocs[j].Node })
+	return allocs
+}
+
+func (t *PortAllocationTable) save() {
+	if t.filename == "" {
+		return
+	}
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err == nil {
+		err = os.WriteFile(t.filename, data, 0644)
+	}
+	if err != nil {
+		logger.WithFields(logger.Fields{"file": t.filename, "err": err}).Error("Can't save esphome ports table")
+	}
+}
+
+func NewPortAllocationTable(config *ServerApiConfig) *PortAllocationTable {
+	return &PortAllocationTable{
+		Nodes:          make(map[MeshNodeId]*PortAllocation),
+		bindAddress:    config.BindAddress,
+		ipv6Prefix:     config.IPv6Prefix,
+		bindPort:       config.BindPort,
+		basePortOffset: config.BasePortOffset,
+		sizeOfPool:     max(config.SizeOfPortsPool, 1),
+	}
+}
+
+// NewPortAllocationTableFromFile load the table from file, the table is written back at every change
+func NewPortAllocationTableFromFile(filename string, config *ServerApiConfig) *PortAllocationTable {
+	table := NewPortAllocationTable(config)
+	table.filename = filename
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return table
+	}
+	err = json.Unmarshal(data, table)
+	if err != nil {
+		logger.WithFields(logger.Fields{"file": filename, "err": err}).Warn("Invalid esphome ports table file, starting with an empty one")
+		table.Nodes = make(map[MeshNodeId]*PortAllocation)
+	}
+	if table.Nodes == nil {
+		table.Nodes = make(map[MeshNodeId]*PortAllocation)
+	}
+	for address, alloc := range table.Nodes {
+		if len(alloc.BindAddresses) == 0 {
+			delete(table.Nodes, address)
+		}
+	}
+	return table
+}
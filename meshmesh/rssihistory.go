@@ -0,0 +1,240 @@
+package meshmesh
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"leguru.net/m/v2/logger"
+)
+
+const rssiHistoryMaxSamples = 1024
+const rssiHistoryMaxAge = 90 * 24 * time.Hour
+
+// A link is degrading when its rssi lose more than rssiTrendMaxSlope dBm per day or when the
+// recent samples are rssiTrendMaxDrop dBm lower than the older ones.
+const rssiTrendMinSamples = 4
+const rssiTrendMaxSlope = -1.0
+const rssiTrendMaxDrop = 6.0
+
+// RssiSample is a raw measure of a link, Rssi1 and Rssi2 are the two directions reported by the discovery:
+// Rssi1 is measured by the node that ran the discovery and Rssi2 by its neighbor. RssiMin and RssiMax
+// are the rssi range of the discovery run, used for the nodes without a weight profile. The samples
+// of the repetitions of the same discovery share Run, Aggregation is how that discovery combined them.
+type RssiSample struct {
+	Time        time.Time `json:"t"`
+	Rssi1       int16     `json:"r1"`
+	Rssi2       int16     `json:"r2"`
+	RssiMin     int16     `json:"min,omitempty"`
+	RssiMax     int16     `json:"max,omitempty"`
+	Run         int64     `json:"run,omitempty"`
+	Aggregation string    `json:"agg,omitempty"`
+}
+
+// Rssi returns the weaker direction of the sample
+func (s RssiSample) Rssi() int16 {
+	return min(s.Rssi1, s.Rssi2)
+}
+
+// RssiTrend summarize the history of a link in a time window
+type RssiTrend struct {
+	From    MeshNodeId
+	To      MeshNodeId
+	Samples int
+	First   time.Time
+	Last    time.Time
+	Latest  RssiSample
+	Mean    float64
+	// Slope is the rssi change in dBm per day
+	Slope float64
+	// Drop is the difference between the mean of the older and the newer half of the samples
+	Drop      float64
+	Degrading bool
+}
+
+// RssiHistory keep the raw rssi samples of every link across the discoveries
+type RssiHistory struct {
+	lock     sync.Mutex
+	filename string
+	dirty    bool
+	Links    map[MeshNodeId]map[MeshNodeId][]RssiSample `json:"links"`
+}
+
+func NewRssiHistory() *RssiHistory {
+	return &RssiHistory{Links: make(map[MeshNodeId]map[MeshNodeId][]RssiSample)}
+}
+
+// NewRssiHistoryFromFile load the history from file, it's written back by Save
+func NewRssiHistoryFromFile(filename string) *RssiHistory {
+	history := NewRssiHistory()
+	history.filename = filename
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return history
+	}
+	err = json.Unmarshal(data, history)
+	if err != nil {
+		logger.WithFields(logger.Fields{"file": filename, "err": err}).Warn("Invalid rssi history file, starting with an empty one")
+	}
+	if history.Links == nil {
+		history.Links = make(map[MeshNodeId]map[MeshNodeId][]RssiSample)
+	}
+	return history
+}
+
+// Save write the history to file if it changed since the last save
+func (h *RssiHistory) Save() error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.filename == "" || !h.dirty {
+		return nil
+	}
+	data, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(h.filename, data, 0644)
+	if err == nil {
+		h.dirty = false
+	}
+	return err
+}
+
+// Add append a sample to the link dropping the ones too old or exceeding the per link limit
+func (h *RssiHistory) Add(from MeshNodeId, to MeshNodeId, sample RssiSample) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	links, ok := h.Links[from]
+	if !ok {
+		links = make(map[MeshNodeId][]RssiSample)
+		h.Links[from] = links
+	}
+	samples := append(links[to], sample)
+	first := 0
+	for first < len(samples) && sample.Time.Sub(samples[first].Time) > rssiHistoryMaxAge {
+		first++
+	}
+	first = max(first, len(samples)-rssiHistoryMaxSamples)
+	links[to] = samples[first:]
+	h.dirty = true
+}
+
+// Samples returns the samples of a link taken after since
+func (h *RssiHistory) Samples(from MeshNodeId, to MeshNodeId, since time.Time) []RssiSample {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	samples := h.Links[from][to]
+	first := sort.Search(len(samples), func(i int) bool { return !samples[i].Time.Before(since) })
+	return append([]RssiSample(nil), samples[first:]...)
+}
+
+// Last returns the most recent sample of a link
+func (h *RssiHistory) Last(from MeshNodeId, to MeshNodeId) (RssiSample, bool) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	samples := h.Links[from][to]
+	if len(samples) == 0 {
+		return RssiSample{}, false
+	}
+	return samples[len(samples)-1], true
+}
+
+// LastRun returns the samples of a link taken by its most recent discovery, only the last
+// sample for the samples stored without the run
+func (h *RssiHistory) LastRun(from MeshNodeId, to MeshNodeId) []RssiSample {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	samples := h.Links[from][to]
+	if len(samples) == 0 {
+		return nil
+	}
+	first := len(samples) - 1
+	run := samples[first].Run
+	for run != 0 && first > 0 && samples[first-1].Run == run {
+		first--
+	}
+	return append([]RssiSample(nil), samples[first:]...)
+}
+
+// Trend compute the trend of a link in the samples taken after since
+func (h *RssiHistory) Trend(from MeshNodeId, to MeshNodeId, since time.Time) RssiTrend {
+	return rssiTrend(from, to, h.Samples(from, to, since))
+}
+
+// Degrading returns the trend of the links whose quality is getting worse, worst first
+func (h *RssiHistory) Degrading(since time.Time) []RssiTrend {
+	h.lock.Lock()
+	keys := make([][2]MeshNodeId, 0)
+	for from, links := range h.Links {
+		for to := range links {
+			keys = append(keys, [2]MeshNodeId{from, to})
+		}
+	}
+	h.lock.Unlock()
+
+	trends := make([]RssiTrend, 0)
+	for _, key := range keys {
+		trend := h.Trend(key[0], key[1], since)
+		if trend.Degrading {
+			trends = append(trends, trend)
+		}
+	}
+	sort.Slice(trends, func(i, j int) bool { return trends[i].Slope < trends[j].Slope })
+	return trends
+}
+
+func rssiTrend(from MeshNodeId, to MeshNodeId, samples []RssiSample) RssiTrend {
+	trend := RssiTrend{From: from, To: to, Samples: len(samples)}
+	if len(samples) == 0 {
+		return trend
+	}
+	trend.First = samples[0].Time
+	trend.Last = samples[len(samples)-1].Time
+	trend.Latest = samples[len(samples)-1]
+
+	// Least squares regression of the rssi over the days since the first sample
+	var sumX, sumY, sumXY, sumXX float64
+	for _, sample := range samples {
+		x := sample.Time.Sub(trend.First).Hours() / 24
+		y := float64(sample.Rssi())
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	n := float64(len(samples))
+	trend.Mean = sumY / n
+	if den := n*sumXX - sumX*sumX; den > 0 {
+		trend.Slope = (n*sumXY - sumX*sumY) / den
+	}
+
+	half := len(samples) / 2
+	if half > 0 {
+		var older, newer float64
+		for _, sample := range samples[:half] {
+			older += float64(sample.Rssi())
+		}
+		for _, sample := range samples[half:] {
+			newer += float64(sample.Rssi())
+		}
+		trend.Drop = older/float64(half) - newer/float64(len(samples)-half)
+	}
+
+	trend.Degrading = len(samples) >= rssiTrendMinSamples && trend.Last.Sub(trend.First) > time.Hour &&
+		(trend.Slope <= rssiTrendMaxSlope || trend.Drop >= rssiTrendMaxDrop)
+	return trend
+}
+
+var _rssiHistory = NewRssiHistory()
+
+// SetRssiHistory replace the global rssi history
+func SetRssiHistory(history *RssiHistory) {
+	_rssiHistory = history
+}
+
+// GetRssiHistory returns the global rssi history
+func GetRssiHistory() *RssiHistory {
+	return _rssiHistory
+}
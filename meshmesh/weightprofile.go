@@ -0,0 +1,274 @@
+package meshmesh
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"sync"
+
+	"leguru.net/m/v2/logger"
+
+	gra "leguru.net/m/v2/graph"
+)
+
+const (
+	WeightModelLinear   = "linear"
+	WeightModelLogistic = "logistic"
+	WeightModelTable    = "table"
+)
+
+// WeightPoint is a point of a table weight model
+type WeightPoint struct {
+	Rssi   int16   `json:"rssi"`
+	Weight float64 `json:"weight"`
+}
+
+// WeightProfile convert the rssi measured by a chip into an edge weight between 0 (best) and 1 (worst)
+type WeightProfile struct {
+	Name  string `json:"name"`
+	Model string `json:"model"`
+	// Chips are the chip types using this profile when the node doesn't select one
+	Chips []string `json:"chips,omitempty"`
+	// Linear model: weight goes from 1 at RssiMin to 0 at RssiMax
+	RssiMin int16 `json:"rssi_min,omitempty"`
+	RssiMax int16 `json:"rssi_max,omitempty"`
+	// Logistic model: weight is 0.5 at Midpoint, Steepness is the slope in 1/dBm
+	Midpoint  float64 `json:"midpoint,omitempty"`
+	Steepness float64 `json:"steepness,omitempty"`
+	// Table model: weight is interpolated between the points
+	Table []WeightPoint `json:"table,omitempty"`
+}
+
+func (p WeightProfile) Validate() error {
+	if p.Name == "" {
+		return errors.New("profile name can't be empty")
+	}
+	switch p.Model {
+	case WeightModelLinear:
+		if p.RssiMin >= p.RssiMax {
+			return errors.New("rssi min must be lower than rssi max")
+		}
+	case WeightModelLogistic:
+		if p.Steepness <= 0 {
+			return errors.New("steepness must be greater than zero")
+		}
+	case WeightModelTable:
+		if len(p.Table) < 2 {
+			return errors.New("table needs at least two points")
+		}
+		for i := 1; i < len(p.Table); i++ {
+			if p.Table[i].Rssi <= p.Table[i-1].Rssi {
+				return errors.New("table points must be sorted by increasing rssi")
+			}
+		}
+		for _, point := range p.Table {
+			if point.Weight < 0 || point.Weight > 1 {
+				return errors.New("table weights must be between 0 and 1")
+			}
+		}
+	default:
+		return fmt.Errorf("unknown weight model %s", p.Model)
+	}
+	return nil
+}
+
+// Weight returns the edge weight of the rssi
+func (p WeightProfile) Weight(rssi int16) float64 {
+	switch p.Model {
+	case WeightModelLogistic:
+		weight := 1.0 / (1.0 + math.Exp(p.Steepness*(float64(rssi)-p.Midpoint)))
+		return math.Round(weight*100) / 100
+	case WeightModelTable:
+		if rssi <= p.Table[0].Rssi {
+			return p.Table[0].Weight
+		}
+		for i := 1; i < len(p.Table); i++ {
+			if rssi <= p.Table[i].Rssi {
+				a, b := p.Table[i-1], p.Table[i]
+				weight := a.Weight + (b.Weight-a.Weight)*float64(rssi-a.Rssi)/float64(b.Rssi-a.Rssi)
+				return math.Round(weight*100) / 100
+			}
+		}
+		return p.Table[len(p.Table)-1].Weight
+	}
+	return rssi2weightRange(rssi, p.RssiMin, p.RssiMax)
+}
+
+// WeightProfiles keep the weight profiles, the nodes without a profile use the discovery rssi range
+type WeightProfiles struct {
+	lock     sync.Mutex
+	filename string
+	Profiles map[string]WeightProfile `json:"profiles"`
+}
+
+var weightProfiles = &WeightProfiles{Profiles: make(map[string]WeightProfile)}
+
+// GetWeightProfiles returns the global weight profiles registry
+func GetWeightProfiles() *WeightProfiles {
+	return weightProfiles
+}
+
+// LoadWeightProfiles load the global profiles from file, the file is written back at every change
+func LoadWeightProfiles(filename string) {
+	weightProfiles.lock.Lock()
+	defer weightProfiles.lock.Unlock()
+	weightProfiles.filename = filename
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return
+	}
+	profiles := WeightProfiles{}
+	err = json.Unmarshal(data, &profiles)
+	if err != nil {
+		logger.WithFields(logger.Fields{"file": filename, "err": err}).Warn("Invalid weight profiles file, starting with no profiles")
+		return
+	}
+	for name, profile := range profiles.Profiles {
+		if err := profile.Validate(); err != nil || profile.Name != name {
+			logger.WithFields(logger.Fields{"profile": name, "err": err}).Warn("Invalid weight profile skipped")
+			continue
+		}
+		weightProfiles.Profiles[name] = profile
+	}
+}
+
+func (w *WeightProfiles) save() {
+	if w.filename == "" {
+		return
+	}
+	data, err := json.MarshalIndent(w, "", "  ")
+	if err == nil {
+		err = os.WriteFile(w.filename, data, 0644)
+	}
+	if err != nil {
+		logger.WithFields(logger.Fields{"file": w.filename, "err": err}).Error("Can't save weight profiles")
+	}
+}
+
+// List returns the profiles sorted by name
+func (w *WeightProfiles) List() []WeightProfile {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	list := make([]WeightProfile, 0, len(w.Profiles))
+	for _, profile := range w.Profiles {
+		list = append(list, profile)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+func (w *WeightProfiles) Get(name string) (WeightProfile, bool) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	profile, ok := w.Profiles[name]
+	return profile, ok
+}
+
+// Set add or replace a profile, a chip can be assigned to a single profile
+func (w *WeightProfiles) Set(profile WeightProfile) error {
+	if err := profile.Validate(); err != nil {
+		return err
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	for _, other := range w.Profiles {
+		if other.Name == profile.Name {
+			continue
+		}
+		for _, chip := range profile.Chips {
+			for _, otherChip := range other.Chips {
+				if chip == otherChip {
+					return fmt.Errorf("chip %s already assigned to profile %s", chip, other.Name)
+				}
+			}
+		}
+	}
+	w.Profiles[profile.Name] = profile
+	w.save()
+	return nil
+}
+
+func (w *WeightProfiles) Delete(name string) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if _, ok := w.Profiles[name]; !ok {
+		return fmt.Errorf("unknown weight profile %s", name)
+	}
+	delete(w.Profiles, name)
+	w.save()
+	return nil
+}
+
+// resolve returns the profile selected by the node or by its chip type
+func (w *WeightProfiles) resolve(dev gra.NodeDevice) (WeightProfile, bool) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if profile, ok := w.Profiles[dev.Device().WeightProfile()]; ok {
+		return profile, true
+	}
+	if chip := dev.Device().Chip(); chip != "" {
+		for _, profile := range w.Profiles {
+			for _, profileChip := range profile.Chips {
+				if profileChip == chip {
+					return profile, true
+				}
+			}
+		}
+	}
+	return WeightProfile{}, false
+}
+
+// nodeRssi2weight convert the rssi measured by a node using its profile, the nodes without a
+// profile use the rssi range of the discovery parameters.
+func nodeRssi2weight(network *gra.Network, nodeId int64, rssi int16, params DiscoveryParams) float64 {
+	if dev, err := network.GetNodeDevice(nodeId); err == nil {
+		if profile, ok := weightProfiles.resolve(dev); ok {
+			return profile.Weight(rssi)
+		}
+	}
+	return params.rssi2weight(rssi)
+}
+
+// sampleRssi2weight convert a link sample to an edge weight, each direction is converted with the
+// profile of the node that measured it. The samples without a range use the default discovery range.
+func sampleRssi2weight(network *gra.Network, from int64, to int64, sample RssiSample) float64 {
+	params := DefaultDiscoveryParams()
+	if sample.RssiMin < sample.RssiMax {
+		params.RssiMin, params.RssiMax = sample.RssiMin, sample.RssiMax
+	}
+	return math.Min(nodeRssi2weight(network, from, sample.Rssi1, params), nodeRssi2weight(network, to, sample.Rssi2, params))
+}
+
+// RecomputeWeights update the weight of the links of the network from the raw rssi stored in the
+// history: the samples of the last discovery of each link are combined with the aggregation used
+// by that discovery. Pinned links are left untouched. Returns the number of changed links.
+func RecomputeWeights(network *gra.Network, history *RssiHistory) int {
+	changed := 0
+	edges := network.WeightedEdges()
+	updates := make(map[[2]int64]float64)
+	for edges.Next() {
+		edge := edges.WeightedEdge()
+		from, to := edge.From().ID(), edge.To().ID()
+		samples := history.LastRun(MeshNodeId(from), MeshNodeId(to))
+		if len(samples) == 0 {
+			continue
+		}
+		weights := make([]float64, len(samples))
+		for i, sample := range samples {
+			weights[i] = sampleRssi2weight(network, from, to, sample)
+		}
+		weight := DiscoveryParams{Aggregation: samples[len(samples)-1].Aggregation}.aggregate(weights)
+		if weight != edge.Weight() {
+			updates[[2]int64{from, to}] = weight
+		}
+	}
+	for key, weight := range updates {
+		if network.SetDiscoveredEdgeWeight(key[0], key[1], weight) {
+			changed++
+		}
+	}
+	return changed
+}
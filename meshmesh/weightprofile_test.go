@@ -0,0 +1,38 @@
+package meshmesh
+
+import (
+	"testing"
+	"time"
+
+	"leguru.net/m/v2/graph"
+)
+
+func TestRecomputeWeightsAggregateLastRun(t *testing.T) {
+	network := graph.NewNetwork(1)
+	network.ChangeEdgeWeight(1, 2, 1, 1)
+	network.ChangeEdgeWeight(1, 3, 1, 1)
+
+	now := time.Now()
+	sample := func(rssi int16, run int64, aggregation string) RssiSample {
+		return RssiSample{Time: now, Rssi1: rssi, Rssi2: rssi, RssiMin: -100, RssiMax: 0, Run: run, Aggregation: aggregation}
+	}
+	history := NewRssiHistory()
+	// An older run is ignored, the last one is combined with its own aggregation
+	history.Add(1, 2, sample(-10, 1, DiscoveryAggregationMean))
+	history.Add(1, 2, sample(-40, 2, DiscoveryAggregationMin))
+	history.Add(1, 2, sample(-80, 2, DiscoveryAggregationMin))
+	history.Add(1, 2, sample(-60, 2, DiscoveryAggregationMin))
+	// Samples stored without the run use only the last one
+	history.Add(1, 3, RssiSample{Time: now, Rssi1: -20, Rssi2: -20, RssiMin: -100, RssiMax: 0})
+	history.Add(1, 3, RssiSample{Time: now, Rssi1: -50, Rssi2: -50, RssiMin: -100, RssiMax: 0})
+
+	if changed := RecomputeWeights(network, history); changed != 2 {
+		t.Fatalf("changed %d links, want 2", changed)
+	}
+	if weight, _ := network.Weight(1, 2); weight != rssi2weightRange(-40, -100, 0) {
+		t.Fatalf("weight 1->2 %f, want the min of the last run %f", weight, rssi2weightRange(-40, -100, 0))
+	}
+	if weight, _ := network.Weight(1, 3); weight != rssi2weightRange(-50, -100, 0) {
+		t.Fatalf("weight 1->3 %f, want the last sample %f", weight, rssi2weightRange(-50, -100, 0))
+	}
+}
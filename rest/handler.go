@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"sync"
 
 	"github.com/gin-gonic/gin"
 	"leguru.net/m/v2/graph"
@@ -12,7 +13,7 @@ import (
 
 type Handler struct {
 	serialConn              *mm.SerialConnection
-	discoveryProcedure      *mm.DiscoveryProcedure
+	firmwareLock            sync.Mutex
 	firmwareUploadProcedure *mm.FirmwareUploadProcedure
 	esphomeServers          *mm.MultiServerApi
 }
@@ -35,16 +36,26 @@ func smartInteger(v any) int64 {
 }
 
 func (h *Handler) uploadFirmware(nodeId int64, firmware []byte) error {
-	if h.firmwareUploadProcedure != nil && h.firmwareUploadProcedure.IsComplete() {
+	h.firmwareLock.Lock()
+	defer h.firmwareLock.Unlock()
+	if h.firmwareUploadProcedure != nil && h.firmwareUploadProcedure.IsRunning() {
 		return errors.New("firmware upload procedure already running")
 	}
 
-	h.firmwareUploadProcedure = mm.NewFirmwareUploadProcedure(h.serialConn, graph.GetMainNetwork(), mm.MeshNodeId(nodeId))
-	go h.firmwareUploadProcedure.Run(firmware)
-
+	procedure := mm.NewFirmwareUploadProcedure(h.serialConn, graph.GetMainNetwork(), mm.MeshNodeId(nodeId))
+	if err := procedure.Start(firmware); err != nil {
+		return err
+	}
+	h.firmwareUploadProcedure = procedure
 	return nil
 }
 
+func (h *Handler) firmwareProcedure() *mm.FirmwareUploadProcedure {
+	h.firmwareLock.Lock()
+	defer h.firmwareLock.Unlock()
+	return h.firmwareUploadProcedure
+}
+
 func routeFrontend(c *gin.Context) {
 	c.Status(http.StatusFound)
 	c.Writer.Header().Set("Location", "/manager")
@@ -53,7 +64,6 @@ func routeFrontend(c *gin.Context) {
 func NewHandler(serialConn *mm.SerialConnection, esphomeServers *mm.MultiServerApi) *Handler {
 	return &Handler{
 		serialConn:              serialConn,
-		discoveryProcedure:      nil,
 		firmwareUploadProcedure: nil,
 		esphomeServers:          esphomeServers,
 	}
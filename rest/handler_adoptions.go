@@ -0,0 +1,131 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	mm "leguru.net/m/v2/meshmesh"
+	"leguru.net/m/v2/utils"
+)
+
+func fillAdoptionStruct(adoption mm.Adoption) MeshAdoption {
+	neighbors := make([]MeshAdoptionNeighbor, len(adoption.Neighbors))
+	for i, neighbor := range adoption.Neighbors {
+		neighbors[i] = MeshAdoptionNeighbor{ID: utils.FmtNodeId(int64(neighbor.Id)), Rssi: neighbor.Rssi}
+	}
+	return MeshAdoption{
+		ID:        uint(adoption.Node),
+		Node:      utils.FmtNodeId(int64(adoption.Node)),
+		Server:    utils.FmtNodeId(int64(adoption.Server)),
+		Neighbors: neighbors,
+		State:     adoption.State.String(),
+		Tag:       adoption.Tag,
+		Revision:  adoption.Revision,
+		Channel:   int(adoption.Channel),
+		TxPower:   int(adoption.TxPower),
+		Groups:    int(adoption.Groups),
+		Binded:    int(adoption.Binded),
+		Flags:     int(adoption.Flags),
+		Error:     adoption.Error,
+		Created:   adoption.Created.Format(time.RFC3339),
+		Updated:   adoption.Updated.Format(time.RFC3339),
+	}
+}
+
+// @Id getAdoptions
+// @Summary Get the nodes associated to the mesh and their adoption state
+// @Tags    Adoptions
+// @Accept  json
+// @Produce json
+// @Success 200 {array} MeshAdoption
+// @Failure 400 {object} string
+// @Router /api/adoptions [get]
+func (h *Handler) getAdoptions(c *gin.Context) {
+	adoptions := mm.GetAdoptions().List()
+	sort.Slice(adoptions, func(i, j int) bool { return adoptions[i].Created.After(adoptions[j].Created) })
+	jsonAdoptions := make([]MeshAdoption, 0, len(adoptions))
+	for _, adoption := range adoptions {
+		jsonAdoptions = append(jsonAdoptions, fillAdoptionStruct(adoption))
+	}
+
+	c.Header("Content-Range", fmt.Sprintf("%d-%d/%d", 0, len(jsonAdoptions), len(jsonAdoptions)))
+	c.JSON(http.StatusOK, jsonAdoptions)
+}
+
+// @Id getOneAdoption
+// @Summary Get the adoption state of a node
+// @Tags    Adoptions
+// @Accept  json
+// @Produce json
+// @Param   id path int true "Node ID"
+// @Success 200 {object} MeshAdoption
+// @Failure 400 {object} string
+// @Failure 404 {object} string
+// @Router /api/adoptions/{id} [get]
+func (h *Handler) getOneAdoption(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	adoption, ok := mm.GetAdoptions().Get(mm.MeshNodeId(id))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Adoption not found"})
+		return
+	}
+	c.JSON(http.StatusOK, fillAdoptionStruct(adoption))
+}
+
+// @Id approveAdoption
+// @Summary Approve the adoption of a pending node or retry a failed one
+// @Tags    Adoptions
+// @Accept  json
+// @Produce json
+// @Param   id path int true "Node ID"
+// @Success 200 {object} MeshAdoption
+// @Failure 400 {object} string
+// @Router /api/adoptions/{id} [post]
+func (h *Handler) approveAdoption(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	err = mm.GetAdoptions().Approve(mm.MeshNodeId(id))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	h.getOneAdoption(c)
+}
+
+// @Id rejectAdoption
+// @Summary Reject the adoption of a pending node
+// @Tags    Adoptions
+// @Accept  json
+// @Produce json
+// @Param   id path int true "Node ID"
+// @Success 200 {object} MeshAdoption
+// @Failure 400 {object} string
+// @Router /api/adoptions/{id} [delete]
+func (h *Handler) rejectAdoption(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	err = mm.GetAdoptions().Reject(mm.MeshNodeId(id))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	h.getOneAdoption(c)
+}
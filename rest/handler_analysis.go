@@ -0,0 +1,89 @@
+package rest
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"leguru.net/m/v2/graph"
+	"leguru.net/m/v2/utils"
+)
+
+// @Id getAnalysis
+// @Summary Get the topology analysis of the network
+// @Tags    Analysis
+// @Accept  json
+// @Produce json
+// @Success 200 {object} MeshAnalysis
+// @Router /api/analysis [get]
+func (h *Handler) getAnalysis(c *gin.Context) {
+	analysis := graph.GetMainNetworkSnapshot().Analyze()
+
+	jsonAnalysis := MeshAnalysis{
+		Nodes:       make([]MeshNodeAnalysis, 0, len(analysis.Nodes)),
+		Bridges:     make([]MeshBridgeAnalysis, 0, len(analysis.Bridges)),
+		Unreachable: formatNodeIds(analysis.Unreachable),
+	}
+	for _, node := range analysis.Nodes {
+		jsonAnalysis.Nodes = append(jsonAnalysis.Nodes, MeshNodeAnalysis{
+			ID:           uint(node.Node),
+			Node:         utils.FmtNodeId(node.Node),
+			Hops:         node.Hops,
+			Betweenness:  node.Betweenness,
+			Relayed:      node.Relayed,
+			Articulation: node.Articulation,
+			Dependents:   formatNodeIds(node.Dependents),
+			SinglePath:   node.SinglePath,
+		})
+	}
+	for _, bridge := range analysis.Bridges {
+		jsonAnalysis.Bridges = append(jsonAnalysis.Bridges, MeshBridgeAnalysis{
+			ID:         uint(bridge.From) + uint(bridge.To)<<24,
+			From:       utils.FmtNodeId(bridge.From),
+			To:         utils.FmtNodeId(bridge.To),
+			Dependents: formatNodeIds(bridge.Dependents),
+		})
+	}
+
+	c.JSON(http.StatusOK, jsonAnalysis)
+}
+
+// @Id getFailureImpact
+// @Summary Get the nodes that lose connectivity or change route if a node fails
+// @Tags    Analysis
+// @Accept  json
+// @Produce json
+// @Param   id path string true "Node ID"
+// @Success 200 {object} MeshFailureImpact
+// @Failure 400 {object} string
+// @Router /api/analysis/failure/{id} [get]
+func (h *Handler) getFailureImpact(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	impact, err := graph.GetMainNetworkSnapshot().FailureImpact(int64(id))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	jsonImpact := MeshFailureImpact{
+		ID:       uint(impact.Node),
+		Node:     utils.FmtNodeId(impact.Node),
+		Lost:     formatNodeIds(impact.Lost),
+		Rerouted: make([]MeshRouteChange, 0, len(impact.Rerouted)),
+	}
+	for _, change := range impact.Rerouted {
+		jsonImpact.Rerouted = append(jsonImpact.Rerouted, MeshRouteChange{
+			Node:    utils.FmtNodeId(change.Node),
+			OldPath: utils.FmtPath2Str(change.OldPath),
+			NewPath: utils.FmtPath2Str(change.NewPath),
+		})
+	}
+
+	c.JSON(http.StatusOK, jsonImpact)
+}
@@ -3,6 +3,7 @@ package rest
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"leguru.net/m/v2/graph"
@@ -20,7 +21,8 @@ import (
 // @Failure 400 {object} string
 // @Router /api/discovery/state [get]
 func (h *Handler) getDiscoveryProcedureState(c *gin.Context) {
-	if h.discoveryProcedure == nil {
+	discovery := mm.CurrentDiscovery()
+	if discovery == nil {
 		discoveryState := MeshDiscoveryState{
 			ID:        0,
 			Status:    "idle",
@@ -29,11 +31,18 @@ func (h *Handler) getDiscoveryProcedureState(c *gin.Context) {
 		}
 		c.JSON(http.StatusOK, discoveryState)
 	} else {
+		progress := discovery.Progress()
 		discoveryState := MeshDiscoveryState{
-			ID:        0,
-			Status:    h.discoveryProcedure.StateString(),
-			CurrentId: utils.FmtNodeId(h.discoveryProcedure.CurrentDeviceId()),
-			Repeat:    h.discoveryProcedure.CurrentRepeat(),
+			ID:          0,
+			Status:      progress.State.String(),
+			CurrentId:   utils.FmtNodeId(progress.CurrentDeviceId),
+			Repeat:      progress.Repeat,
+			Repetitions: discovery.Params().Repetitions,
+			Targets:     formatNodeIds(progress.Targets),
+			Done:        progress.Done,
+			Total:       progress.Total,
+			Eta:         int(progress.Eta.Seconds()),
+			Error:       progress.Error,
 		}
 		c.JSON(http.StatusOK, discoveryState)
 	}
@@ -47,26 +56,107 @@ func (h *Handler) ctrlDiscoveryProcedure(c *gin.Context) {
 		return
 	}
 
-	var network *graph.Network = nil
-	if req.Mode == "refresh" {
-		network = graph.GetMainNetwork().CopyNetwork()
+	switch req.Mode {
+	case "pause", "resume", "cancel":
+		err = mm.ControlDiscovery(req.Mode)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+		h.getDiscoveryProcedureState(c)
+		return
 	}
 
-	if h.discoveryProcedure == nil {
-		h.discoveryProcedure = mm.NewDiscoveryProcedure(h.serialConn, network, graph.GetMainNetwork().LocalDeviceId())
+	if discovery := mm.CurrentDiscovery(); discovery != nil && (discovery.IsRunning() || discovery.State() == mm.DiscoveryProcedureStatePaused) {
+		h.getDiscoveryProcedureState(c)
+		return
+	}
+
+	var discovery *mm.DiscoveryProcedure
+	if len(req.Nodes) > 0 {
+		nodes := make([]int64, len(req.Nodes))
+		for i, id := range req.Nodes {
+			nodes[i] = int64(id)
+		}
+		discovery, err = mm.NewTargetedDiscoveryProcedure(h.serialConn, nodes, req.Subtree)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
 	} else {
-		if h.discoveryProcedure.State() == mm.DiscoveryProcedureStateDone || h.discoveryProcedure.State() == mm.DiscoveryProcedureStateError {
-			h.discoveryProcedure = mm.NewDiscoveryProcedure(h.serialConn, network, graph.GetMainNetwork().LocalDeviceId())
+		var network *graph.Network = nil
+		if req.Mode == "refresh" {
+			network = graph.GetMainNetwork().CopyNetwork()
 		}
+		discovery = mm.NewDiscoveryProcedure(h.serialConn, network, graph.GetMainNetwork().LocalDeviceId())
+	}
+
+	params, err := discoveryParams(&req)
+	if err == nil {
+		err = discovery.SetParams(params)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
 	}
 
-	if h.discoveryProcedure.State() != mm.DiscoveryProcedureStateRun {
-		go h.discoveryProcedure.Run()
+	if err = mm.StartDiscovery(discovery); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
 	}
 
 	h.getDiscoveryProcedureState(c)
 }
 
+// discoveryParams returns the discovery parameters of the request applied over the defaults
+func discoveryParams(r *CtrlDiscoveryRequest) (mm.DiscoveryParams, error) {
+	params := mm.DefaultDiscoveryParams()
+	for _, value := range []*int{r.Slots, r.Mask, r.Filter} {
+		if value != nil && (*value < 0 || *value > 255) {
+			return params, fmt.Errorf("value %d out of range", *value)
+		}
+	}
+	if r.Slots != nil {
+		params.Slots = uint8(*r.Slots)
+	}
+	if r.Mask != nil {
+		params.Mask = uint8(*r.Mask)
+	}
+	if r.Filter != nil {
+		params.Filter = uint8(*r.Filter)
+	}
+	if r.Wait != nil {
+		params.Wait = time.Duration(*r.Wait * float64(time.Second))
+	}
+	if r.Repetitions != nil {
+		params.Repetitions = *r.Repetitions
+	}
+	if r.Aggregation != "" {
+		params.Aggregation = r.Aggregation
+	}
+	if r.RssiMin != nil {
+		params.RssiMin = int16(*r.RssiMin)
+	}
+	if r.RssiMax != nil {
+		params.RssiMax = int16(*r.RssiMax)
+	}
+	if r.DryRun != nil {
+		params.DryRun = *r.DryRun
+	}
+	if r.WeightThreshold != nil {
+		params.WeightThreshold = *r.WeightThreshold
+	}
+	return params, params.Validate()
+}
+
+func formatNodeIds(ids []int64) []string {
+	nodes := make([]string, len(ids))
+	for i, id := range ids {
+		nodes[i] = utils.FmtNodeId(id)
+	}
+	return nodes
+}
+
 // @Id getNeighbors
 // @Summary Get neighbors
 // @Tags    Discovery
@@ -76,14 +166,15 @@ func (h *Handler) ctrlDiscoveryProcedure(c *gin.Context) {
 // @Failure 400 {object} string
 // @Router /api/discovery/neighbors [get]
 func (h *Handler) getNeighbors(c *gin.Context) {
-	if h.discoveryProcedure == nil || h.discoveryProcedure.Neighbors == nil {
+	discovery := mm.CurrentDiscovery()
+	if discovery == nil {
 		c.Header("Content-Range", "0-0/0")
 		c.JSON(http.StatusOK, []MeshNeighbor{})
 		return
 	}
 
 	jsonNeighbors := []MeshNeighbor{}
-	for k, neighbor := range h.discoveryProcedure.Neighbors {
+	for k, neighbor := range discovery.NeighborsSnapshot() {
 		jsonNeighbors = append(jsonNeighbors, MeshNeighbor{
 			ID:      uint(k),
 			Node:    utils.FmtNodeId(k),
@@ -95,3 +186,96 @@ func (h *Handler) getNeighbors(c *gin.Context) {
 	c.Header("Content-Range", fmt.Sprintf("%d-%d/%d", 0, len(jsonNeighbors), len(jsonNeighbors)))
 	c.JSON(http.StatusOK, jsonNeighbors)
 }
+
+func fmtOptionalNodeId(id int64, kind graph.DiffKind, kinds ...graph.DiffKind) string {
+	for _, k := range kinds {
+		if k == kind {
+			return utils.FmtNodeId(id)
+		}
+	}
+	return ""
+}
+
+// @Id getDiscoveryProposal
+// @Summary Get the changes proposed by a dry run discovery
+// @Tags    Discovery
+// @Accept  json
+// @Produce json
+// @Success 200 {array} MeshDiscoveryDiffItem
+// @Failure 400 {object} string
+// @Router /api/neighbors/discovery/proposal [get]
+func (h *Handler) getDiscoveryProposal(c *gin.Context) {
+	proposal := mm.CurrentDiscoveryProposal()
+	if proposal == nil {
+		c.Header("Content-Range", "0-0/0")
+		c.JSON(http.StatusOK, []MeshDiscoveryDiffItem{})
+		return
+	}
+
+	items := proposal.Items()
+	jsonItems := make([]MeshDiscoveryDiffItem, 0, len(items))
+	for _, item := range items {
+		jsonItems = append(jsonItems, MeshDiscoveryDiffItem{
+			ID:        item.Id,
+			Kind:      string(item.Kind),
+			Node:      fmtOptionalNodeId(item.Node, item.Kind, graph.DiffNodeAdded, graph.DiffNodeRemoved, graph.DiffRouteChanged),
+			From:      fmtOptionalNodeId(item.From, item.Kind, graph.DiffEdgeAdded, graph.DiffEdgeRemoved, graph.DiffEdgeChanged),
+			To:        fmtOptionalNodeId(item.To, item.Kind, graph.DiffEdgeAdded, graph.DiffEdgeRemoved, graph.DiffEdgeChanged),
+			OldWeight: item.OldWeight,
+			NewWeight: item.NewWeight,
+			OldPath:   utils.FmtPath2Str(item.OldPath),
+			NewPath:   utils.FmtPath2Str(item.NewPath),
+			Applied:   proposal.IsApplied(item.Id),
+			Stale:     proposal.IsStale(item.Id),
+		})
+	}
+	c.Header("Content-Range", fmt.Sprintf("%d-%d/%d", 0, len(jsonItems), len(jsonItems)))
+	c.JSON(http.StatusOK, jsonItems)
+}
+
+// @Id applyDiscoveryProposal
+// @Summary Apply the proposal of a dry run discovery, entirely or per item
+// @Tags    Discovery
+// @Accept  json
+// @Produce json
+// @Param   body body ApplyDiscoveryProposalRequest true "Items to apply"
+// @Success 200 {array} MeshDiscoveryDiffItem
+// @Failure 400 {object} string
+// @Router /api/neighbors/discovery/proposal [post]
+func (h *Handler) applyDiscoveryProposal(c *gin.Context) {
+	req := ApplyDiscoveryProposalRequest{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if len(req.Items) == 0 {
+		err = mm.AcceptDiscoveryProposal()
+	} else if proposal := mm.CurrentDiscoveryProposal(); proposal == nil {
+		err = fmt.Errorf("no discovery proposal to apply")
+	} else {
+		err = proposal.Apply(req.Items)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	h.getDiscoveryProposal(c)
+}
+
+// @Id rejectDiscoveryProposal
+// @Summary Discard the proposal of a dry run discovery
+// @Tags    Discovery
+// @Accept  json
+// @Produce json
+// @Success 200 {object} string
+// @Failure 400 {object} string
+// @Router /api/neighbors/discovery/proposal [delete]
+func (h *Handler) rejectDiscoveryProposal(c *gin.Context) {
+	if err := mm.RejectDiscoveryProposal(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "proposal rejected"})
+}
@@ -1,12 +1,18 @@
 package rest
 
 import (
+	"encoding/csv"
 	"fmt"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"leguru.net/m/v2/graph"
 	"leguru.net/m/v2/logger"
+	mm "leguru.net/m/v2/meshmesh"
 	"leguru.net/m/v2/utils"
 )
 
@@ -20,11 +26,22 @@ import (
 // @Router /api/esphome/servers [get]
 func (h *Handler) getEsphomeServers(c *gin.Context) {
 	jsonServers := make([]EsphomeServer, 0)
-	for _, server := range h.esphomeServers.Servers {
+	for _, server := range h.esphomeServers.ServersList() {
+		bindError := ""
+		if server.BindError() != nil {
+			bindError = server.BindError().Error()
+		}
 		jsonServers = append(jsonServers, EsphomeServer{
-			ID:      uint(server.Address),
-			Address: server.GetListenAddress(),
-			Clients: len(server.Clients),
+			ID:         uint(server.Address),
+			Node:       utils.FmtNodeId(int64(server.Address)),
+			Address:    server.GetListenAddress(),
+			Addresses:  server.ListenAddresses(),
+			RemotePort: server.RemotePort(),
+			Listening:  server.IsListening(),
+			BindError:  bindError,
+			Clients:    server.ClientsCount(),
+			Proxy:      server.IsProxy(),
+			Online:     server.IsOnline(),
 		})
 	}
 	c.Header("Content-Range", fmt.Sprintf("%d-%d/%d", 0, len(jsonServers), len(jsonServers)))
@@ -71,3 +88,326 @@ func (h *Handler) getEsphomeConnections(c *gin.Context) {
 	c.Header("Content-Range", fmt.Sprintf("%d-%d/%d", 0, len(jsonClients), len(jsonClients)))
 	c.JSON(http.StatusOK, jsonClients)
 }
+
+// @Id getEsphomeCache
+// @Summary Get esphome entities cache
+// @Tags    Esphome
+// @Accept  json
+// @Produce json
+// @Success 200 {array} EsphomeNodeCache
+// @Failure 400 {object} string
+// @Router /api/esphome/cache [get]
+func (h *Handler) getEsphomeCache(c *gin.Context) {
+	stale := make(map[mm.MeshNodeId]bool)
+	for _, server := range h.esphomeServers.ServersList() {
+		if server.IsProxy() {
+			stale[server.Address] = server.Proxy().IsStale()
+		}
+	}
+
+	network := graph.GetMainNetworkSnapshot()
+	cache := h.esphomeServers.Cache()
+	jsonCache := make([]EsphomeNodeCache, 0)
+	for _, nodeId := range cache.NodeIds() {
+		node := cache.Node(nodeId)
+		entities, states, commands := node.Counters()
+		lastSeen := node.GetLastSeen()
+
+		var tag string
+		if dev, err := network.GetNodeDevice(int64(nodeId)); err == nil {
+			tag = dev.Device().Tag()
+		}
+
+		jsonCache = append(jsonCache, EsphomeNodeCache{
+			ID:       uint(nodeId),
+			Node:     utils.FmtNodeId(int64(nodeId)),
+			Tag:      tag,
+			Entities: entities,
+			States:   states,
+			Commands: commands,
+			LastSeen: lastSeen.Format(time.RFC3339),
+			Age:      time.Since(lastSeen).Round(time.Second).String(),
+			Stale:    stale[nodeId],
+		})
+	}
+
+	sort.Slice(jsonCache, func(i, j int) bool { return jsonCache[i].ID < jsonCache[j].ID })
+	c.Header("Content-Range", fmt.Sprintf("%d-%d/%d", 0, len(jsonCache), len(jsonCache)))
+	c.JSON(http.StatusOK, jsonCache)
+}
+
+// @Id getEsphomeOtaSessions
+// @Summary Get the recent esphome OTA sessions
+// @Tags    Esphome
+// @Accept  json
+// @Produce json
+// @Success 200 {array} EsphomeOtaSession
+// @Failure 400 {object} string
+// @Router /api/esphome/ota [get]
+func (h *Handler) getEsphomeOtaSessions(c *gin.Context) {
+	sessions := mm.GetOtaSessions().Sessions()
+	jsonSessions := make([]EsphomeOtaSession, 0, len(sessions))
+	for i := len(sessions) - 1; i >= 0; i-- {
+		session := sessions[i]
+		var finished string
+		if !session.Finished.IsZero() {
+			finished = session.Finished.Format(time.RFC3339)
+		}
+		jsonSessions = append(jsonSessions, EsphomeOtaSession{
+			ID:          session.Id,
+			Node:        utils.FmtNodeId(int64(session.Node)),
+			Source:      session.Source,
+			Client:      session.Client,
+			State:       session.State.String(),
+			Version:     int(session.Version),
+			Compression: session.Compression,
+			Auth:        session.Auth,
+			Size:        session.Size,
+			Md5:         session.Md5,
+			Sent:        session.Sent,
+			Percent:     session.Percent(),
+			Stalled:     session.IsStalled(),
+			Started:     session.Started.Format(time.RFC3339),
+			Finished:    finished,
+			Error:       session.Error,
+		})
+	}
+
+	c.Header("Content-Range", fmt.Sprintf("%d-%d/%d", 0, len(jsonSessions), len(jsonSessions)))
+	c.JSON(http.StatusOK, jsonSessions)
+}
+
+func (h *Handler) fillPortAllocation(alloc mm.PortAllocation, network *graph.Network) EsphomePortAllocation {
+	var tag string
+	if dev, err := network.GetNodeDevice(int64(alloc.Node)); err == nil {
+		tag = dev.Device().Tag()
+	}
+	return EsphomePortAllocation{
+		ID:            uint(alloc.Node),
+		Node:          utils.FmtNodeId(int64(alloc.Node)),
+		Tag:           tag,
+		BindAddresses: alloc.BindAddresses,
+		ApiPort:       alloc.ApiPort,
+		OtaPort:       alloc.OtaPort,
+		Manual:        alloc.Manual,
+	}
+}
+
+// @Id getEsphomePorts
+// @Summary Get esphome servers ports table
+// @Tags    Esphome
+// @Accept  json
+// @Produce json
+// @Produce text/csv
+// @Param   format query string false "Export format (json or csv)"
+// @Success 200 {array} EsphomePortAllocation
+// @Failure 400 {object} string
+// @Router /api/esphome/ports [get]
+func (h *Handler) getEsphomePorts(c *gin.Context) {
+	network := graph.GetMainNetworkSnapshot()
+	jsonPorts := make([]EsphomePortAllocation, 0)
+	for _, alloc := range h.esphomeServers.PortAllocations() {
+		jsonPorts = append(jsonPorts, h.fillPortAllocation(alloc, network))
+	}
+
+	if c.Query("format") == "csv" {
+		var b strings.Builder
+		w := csv.NewWriter(&b)
+		w.Write([]string{"node", "tag", "bind_addresses", "api_port", "ota_port", "manual"})
+		for _, p := range jsonPorts {
+			w.Write([]string{p.Node, p.Tag, strings.Join(p.BindAddresses, " "), strconv.Itoa(p.ApiPort), strconv.Itoa(p.OtaPort), strconv.FormatBool(p.Manual)})
+		}
+		w.Flush()
+		c.Header("Content-Disposition", "attachment; filename=esphomeports.csv")
+		c.Data(http.StatusOK, "text/csv", []byte(b.String()))
+		return
+	}
+
+	c.Header("Content-Range", fmt.Sprintf("%d-%d/%d", 0, len(jsonPorts), len(jsonPorts)))
+	c.JSON(http.StatusOK, jsonPorts)
+}
+
+// @Id updateEsphomePorts
+// @Summary Override the esphome servers ports of a node
+// @Tags    Esphome
+// @Accept  json
+// @Produce json
+// @Param   id path string true "Node ID"
+// @Param   ports body UpdatePortAllocationRequest true "Ports allocation"
+// @Success 200 {object} EsphomePortAllocation
+// @Failure 400 {object} string
+// @Router /api/esphome/ports/{id} [put]
+func (h *Handler) updateEsphomePorts(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	req := UpdatePortAllocationRequest{}
+	err = c.ShouldBindJSON(&req)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	alloc, err := h.esphomeServers.SetPortAllocation(mm.MeshNodeId(id), req.BindAddresses, req.ApiPort, req.OtaPort)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid ports allocation: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, h.fillPortAllocation(alloc, graph.GetMainNetwork()))
+}
+
+// @Id deleteEsphomePorts
+// @Summary Restore the automatic esphome servers ports of a node
+// @Tags    Esphome
+// @Accept  json
+// @Produce json
+// @Param   id path string true "Node ID"
+// @Success 200 {object} EsphomePortAllocation
+// @Failure 400 {object} string
+// @Router /api/esphome/ports/{id} [delete]
+func (h *Handler) deleteEsphomePorts(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	alloc, err := h.esphomeServers.ResetPortAllocation(mm.MeshNodeId(id))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Can't reset ports allocation: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, h.fillPortAllocation(alloc, graph.GetMainNetwork()))
+}
+
+// @Id getEsphomeAccess
+// @Summary Get esphome servers default access rules and clients limits
+// @Tags    Esphome
+// @Accept  json
+// @Produce json
+// @Success 200 {object} EsphomeAccess
+// @Failure 400 {object} string
+// @Router /api/esphome/access [get]
+func (h *Handler) getEsphomeAccess(c *gin.Context) {
+	access := h.esphomeServers.Access()
+	rules := access.DefaultRules()
+	perNode, total := access.Limits()
+	c.JSON(http.StatusOK, EsphomeAccess{ApiAllow: rules.ApiAllow, OtaAllow: rules.OtaAllow, MaxClientsPerNode: perNode, MaxClients: total})
+}
+
+// @Id updateEsphomeAccess
+// @Summary Update esphome servers default access rules and clients limits
+// @Tags    Esphome
+// @Accept  json
+// @Produce json
+// @Param   access body EsphomeAccess true "Access rules"
+// @Success 200 {object} EsphomeAccess
+// @Failure 400 {object} string
+// @Router /api/esphome/access [put]
+func (h *Handler) updateEsphomeAccess(c *gin.Context) {
+	req := EsphomeAccess{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	rules, err := mm.NewAccessRules(req.ApiAllow, req.OtaAllow)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid access rules: " + err.Error()})
+		return
+	}
+	h.esphomeServers.Access().SetDefault(rules, req.MaxClientsPerNode, req.MaxClients)
+	c.JSON(http.StatusOK, req)
+}
+
+func (h *Handler) fillNodeAccess(id mm.MeshNodeId) EsphomeNodeAccess {
+	access := h.esphomeServers.Access()
+	rules, custom := access.Rules(id)
+	return EsphomeNodeAccess{
+		ID:       uint(id),
+		Node:     utils.FmtNodeId(int64(id)),
+		ApiAllow: rules.ApiAllow,
+		OtaAllow: rules.OtaAllow,
+		Custom:   custom,
+		Refused:  access.Refused(id),
+	}
+}
+
+// @Id getEsphomeNodesAccess
+// @Summary Get esphome access rules of the nodes
+// @Tags    Esphome
+// @Accept  json
+// @Produce json
+// @Success 200 {array} EsphomeNodeAccess
+// @Failure 400 {object} string
+// @Router /api/esphome/access/nodes [get]
+func (h *Handler) getEsphomeNodesAccess(c *gin.Context) {
+	jsonAccess := make([]EsphomeNodeAccess, 0)
+	nodes := graph.GetMainNetworkSnapshot().Nodes()
+	for nodes.Next() {
+		node := nodes.Node().(graph.NodeDevice)
+		if node.Device().InUse() {
+			jsonAccess = append(jsonAccess, h.fillNodeAccess(mm.MeshNodeId(node.ID())))
+		}
+	}
+	sort.Slice(jsonAccess, func(i, j int) bool { return jsonAccess[i].ID < jsonAccess[j].ID })
+	c.Header("Content-Range", fmt.Sprintf("%d-%d/%d", 0, len(jsonAccess), len(jsonAccess)))
+	c.JSON(http.StatusOK, jsonAccess)
+}
+
+// @Id updateEsphomeNodeAccess
+// @Summary Set the esphome access rules of a node
+// @Tags    Esphome
+// @Accept  json
+// @Produce json
+// @Param   id path string true "Node ID"
+// @Param   access body UpdateNodeAccessRequest true "Access rules"
+// @Success 200 {object} EsphomeNodeAccess
+// @Failure 400 {object} string
+// @Router /api/esphome/access/nodes/{id} [put]
+func (h *Handler) updateEsphomeNodeAccess(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	req := UpdateNodeAccessRequest{}
+	err = c.ShouldBindJSON(&req)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	rules, err := mm.NewAccessRules(req.ApiAllow, req.OtaAllow)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid access rules: " + err.Error()})
+		return
+	}
+	h.esphomeServers.Access().SetNodeRules(mm.MeshNodeId(id), rules)
+	c.JSON(http.StatusOK, h.fillNodeAccess(mm.MeshNodeId(id)))
+}
+
+// @Id deleteEsphomeNodeAccess
+// @Summary Restore the default esphome access rules for a node
+// @Tags    Esphome
+// @Accept  json
+// @Produce json
+// @Param   id path string true "Node ID"
+// @Success 200 {object} EsphomeNodeAccess
+// @Failure 400 {object} string
+// @Router /api/esphome/access/nodes/{id} [delete]
+func (h *Handler) deleteEsphomeNodeAccess(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	h.esphomeServers.Access().SetNodeRules(mm.MeshNodeId(id), nil)
+	c.JSON(http.StatusOK, h.fillNodeAccess(mm.MeshNodeId(id)))
+}
@@ -0,0 +1,152 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"leguru.net/m/v2/graph"
+	mm "leguru.net/m/v2/meshmesh"
+	"leguru.net/m/v2/utils"
+)
+
+func fillGraphVersionStruct(version graph.GraphVersion) MeshGraphVersion {
+	return MeshGraphVersion{
+		ID:       version.Id,
+		Time:     version.Time.Format(time.RFC3339),
+		Nodes:    version.Nodes,
+		Edges:    version.Edges,
+		Rollback: version.Rollback,
+	}
+}
+
+func fillGraphDiffItems(items []graph.DiffItem) []MeshGraphDiffItem {
+	nodeKinds := []graph.DiffKind{graph.DiffNodeAdded, graph.DiffNodeRemoved, graph.DiffNodeChanged, graph.DiffRouteChanged}
+	edgeKinds := []graph.DiffKind{graph.DiffEdgeAdded, graph.DiffEdgeRemoved, graph.DiffEdgeChanged}
+	jsonItems := make([]MeshGraphDiffItem, 0, len(items))
+	for _, item := range items {
+		jsonItems = append(jsonItems, MeshGraphDiffItem{
+			ID:        item.Id,
+			Kind:      string(item.Kind),
+			Node:      fmtOptionalNodeId(item.Node, item.Kind, nodeKinds...),
+			From:      fmtOptionalNodeId(item.From, item.Kind, edgeKinds...),
+			To:        fmtOptionalNodeId(item.To, item.Kind, edgeKinds...),
+			OldWeight: item.OldWeight,
+			NewWeight: item.NewWeight,
+			OldPath:   utils.FmtPath2Str(item.OldPath),
+			NewPath:   utils.FmtPath2Str(item.NewPath),
+			OldTag:    item.OldTag,
+			NewTag:    item.NewTag,
+			OldInUse:  item.OldInUse,
+			NewInUse:  item.NewInUse,
+		})
+	}
+	return jsonItems
+}
+
+// @Id getGraphVersions
+// @Summary Get the versions of the network graph
+// @Tags    History
+// @Accept  json
+// @Produce json
+// @Success 200 {array} MeshGraphVersion
+// @Router /api/history [get]
+func (h *Handler) getGraphVersions(c *gin.Context) {
+	versions := graph.GetGraphHistory().List()
+	jsonVersions := make([]MeshGraphVersion, 0, len(versions))
+	// Newest first
+	for i := len(versions) - 1; i >= 0; i-- {
+		jsonVersions = append(jsonVersions, fillGraphVersionStruct(versions[i]))
+	}
+	c.Header("Content-Range", fmt.Sprintf("%d-%d/%d", 0, len(jsonVersions), len(jsonVersions)))
+	c.JSON(http.StatusOK, jsonVersions)
+}
+
+// @Id getOneGraphVersion
+// @Summary Get a version of the network graph
+// @Tags    History
+// @Accept  json
+// @Produce json
+// @Param   id path int true "Version ID"
+// @Success 200 {object} MeshGraphVersion
+// @Failure 404 {object} string
+// @Router /api/history/{id} [get]
+func (h *Handler) getOneGraphVersion(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	version, ok := graph.GetGraphHistory().Get(id)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Version not found"})
+		return
+	}
+	c.JSON(http.StatusOK, fillGraphVersionStruct(version))
+}
+
+// @Id getGraphDiff
+// @Summary Get the changes between two versions of the network graph
+// @Tags    History
+// @Accept  json
+// @Produce json
+// @Param   from query int true "Older version ID"
+// @Param   to   query int true "Newer version ID"
+// @Success 200 {array} MeshGraphDiffItem
+// @Failure 400 {object} string
+// @Router /api/history/diff [get]
+func (h *Handler) getGraphDiff(c *gin.Context) {
+	req := GraphDiffRequest{}
+	err := c.ShouldBindQuery(&req)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	items, err := graph.GetGraphHistory().Diff(req.From, req.To, graph.GetMainNetwork().LocalDeviceId())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	jsonItems := fillGraphDiffItems(items)
+	c.Header("Content-Range", fmt.Sprintf("%d-%d/%d", 0, len(jsonItems), len(jsonItems)))
+	c.JSON(http.StatusOK, jsonItems)
+}
+
+// @Id rollbackGraphVersion
+// @Summary Replace the network graph with one of its versions
+// @Tags    History
+// @Accept  json
+// @Produce json
+// @Param   id path int true "Version ID"
+// @Success 200 {object} MeshGraphVersion
+// @Failure 400 {object} string
+// @Router /api/history/{id}/rollback [post]
+func (h *Handler) rollbackGraphVersion(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	if discovery := mm.CurrentDiscovery(); discovery != nil && discovery.IsRunning() {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Can't rollback while a discovery is running"})
+		return
+	}
+
+	err = graph.GetGraphHistory().Rollback(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	versions := graph.GetGraphHistory().List()
+	if len(versions) == 0 {
+		c.JSON(http.StatusOK, MeshGraphVersion{})
+		return
+	}
+	c.JSON(http.StatusOK, fillGraphVersionStruct(versions[len(versions)-1]))
+}
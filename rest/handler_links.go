@@ -26,9 +26,10 @@ func linkIdFromToId(id uint) (from, to uint) {
 	return from, to
 }
 
-func fillLinkStruct(edge gr.WeightedEdge) MeshLink {
+func fillLinkStruct(edge gr.WeightedEdge, network *graph.Network) MeshLink {
 	from := edge.From().(graph.NodeDevice)
 	to := edge.To().(graph.NodeDevice)
+	attrs := network.LinkAttributes(from.ID(), to.ID())
 
 	return MeshLink{
 		ID:          uint(from.ID()) + uint(to.ID())<<24,
@@ -36,6 +37,9 @@ func fillLinkStruct(edge gr.WeightedEdge) MeshLink {
 		To:          to.ID(),
 		Weight:      float32(edge.Weight()),
 		Description: fmt.Sprintf("from: %s to: %s", from.Device().Tag(), to.Device().Tag()),
+		Pinned:      attrs.Pinned,
+		Excluded:    attrs.Excluded,
+		Preferred:   attrs.Preferred,
 	}
 }
 
@@ -62,7 +66,7 @@ func (h *Handler) getLinks(c *gin.Context) {
 	filter_from, _ := utils.ParseNodeId(p.Filter["from"])
 	filter_any := smartInteger(p.Filter["any"])
 
-	network := graph.GetMainNetwork()
+	network := graph.GetMainNetworkSnapshot()
 	links := network.WeightedEdges()
 	jsonLinks := make([]MeshLink, 0, links.Len())
 	for links.Next() {
@@ -74,7 +78,7 @@ func (h *Handler) getLinks(c *gin.Context) {
 			continue
 		}
 
-		jsonLinks = append(jsonLinks, fillLinkStruct(edge))
+		jsonLinks = append(jsonLinks, fillLinkStruct(edge, network))
 	}
 
 	// Sort array base on request fields
@@ -104,14 +108,14 @@ func (h *Handler) getOneLink(c *gin.Context) {
 	}
 
 	fromID, toID := parseFromToId(uint(fromToId))
-	network := graph.GetMainNetwork()
+	network := graph.GetMainNetworkSnapshot()
 	edge := network.WeightedEdge(int64(fromID), int64(toID))
 	if edge == nil {
 		c.JSON(http.StatusNotFound, gin.H{"message": "Link not found"})
 		return
 	}
 
-	jsonLink := fillLinkStruct(edge)
+	jsonLink := fillLinkStruct(edge, network)
 	c.JSON(http.StatusOK, jsonLink)
 }
 
@@ -161,10 +165,23 @@ func (h *Handler) updateLink(c *gin.Context) {
 		return
 	}
 
-	network.ChangeEdgeWeight(int64(fromID), int64(toID), float64(req.Weight), float64(req.Weight))
-	graph.NotifyMainNetworkChanged()
+	_ = graph.UpdateMainNetwork(func(network *graph.Network) error {
+		network.ChangeEdgeWeight(int64(fromID), int64(toID), float64(req.Weight), float64(req.Weight))
+		attrs := network.LinkAttributes(int64(fromID), int64(toID))
+		if req.Pinned != nil {
+			attrs.Pinned = *req.Pinned
+		}
+		if req.Excluded != nil {
+			attrs.Excluded = *req.Excluded
+		}
+		if req.Preferred != nil {
+			attrs.Preferred = *req.Preferred
+		}
+		network.SetLinkAttributes(int64(fromID), int64(toID), attrs)
+		return nil
+	})
 
-	jsonLink := fillLinkStruct(edge)
+	jsonLink := fillLinkStruct(network.WeightedEdge(int64(fromID), int64(toID)), network)
 	c.JSON(http.StatusOK, jsonLink)
 }
 
@@ -193,9 +210,12 @@ func (h *Handler) deleteLink(c *gin.Context) {
 		return
 	}
 
-	network.RemoveEdge(int64(fromID), int64(toID))
-	graph.NotifyMainNetworkChanged()
+	jsonLink := fillLinkStruct(edge, network)
+	_ = graph.UpdateMainNetwork(func(network *graph.Network) error {
+		network.RemoveEdge(int64(fromID), int64(toID))
+		network.SetLinkAttributes(int64(fromID), int64(toID), graph.LinkAttributes{})
+		return nil
+	})
 
-	jsonLink := fillLinkStruct(edge)
 	c.JSON(http.StatusOK, jsonLink)
 }
@@ -0,0 +1,166 @@
+package rest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"leguru.net/m/v2/graph"
+	mm "leguru.net/m/v2/meshmesh"
+)
+
+// @Id exportNetwork
+// @Summary Export the network graph
+// @Description Formats: dot (Graphviz), gexf (Gephi), json (D3 node-link), csv_nodes, csv_edges and csv (zip of both tables)
+// @Tags    Network
+// @Produce plain
+// @Param   format query string false "Export format" Enums(dot, gexf, json, csv_nodes, csv_edges, csv) default(dot)
+// @Success 200 {string} string
+// @Failure 400 {object} string
+// @Router /api/network/export [get]
+func (h *Handler) exportNetwork(c *gin.Context) {
+	format, err := graph.ParseExportFormat(c.DefaultQuery("format", string(graph.ExportDot)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := graph.GetMainNetworkSnapshot().Export(&buf, format); err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=meshmesh.%s", format.Extension()))
+	c.Data(http.StatusOK, format.ContentType(), buf.Bytes())
+}
+
+func fillGraphImportStruct(i *graph.GraphImport) MeshGraphImport {
+	return MeshGraphImport{
+		Format:  string(i.Format),
+		Mode:    string(i.Mode),
+		Weights: string(i.Weights),
+		Created: i.Created.Format(time.RFC3339),
+		Items:   fillGraphDiffItems(i.Preview()),
+	}
+}
+
+// @Id importNetwork
+// @Summary Upload a network graph to replace or merge with the current one
+// @Description The import waits for commit, the response is the preview of the changes
+// @Tags    Network
+// @Accept  multipart/form-data
+// @Produce json
+// @Param   format formData string true "Import format" Enums(graphml, json, csv)
+// @Param   mode   formData string true "Import mode" Enums(replace, merge)
+// @Param   weights formData string false "Weight kept by a merge for the existing edges" Enums(imported, existing, newer) default(imported)
+// @Param   file   formData file   true "Graph file, the csv format accepts more files with the node and the edge tables or a zip of both"
+// @Success 200 {object} MeshGraphImport
+// @Failure 400 {object} string
+// @Router /api/network/import [post]
+func (h *Handler) importNetwork(c *gin.Context) {
+	req := ImportNetworkRequest{}
+	err := c.ShouldBind(&req)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	format, err := graph.ParseImportFormat(req.Format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	mode, err := graph.ParseImportMode(req.Mode)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	if req.Weights == "" {
+		req.Weights = string(graph.MergeWeightsImported)
+	}
+	weights, err := graph.ParseMergeWeights(req.Weights)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	form, err := c.MultipartForm()
+	if err != nil || len(form.File["file"]) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Missing graph file"})
+		return
+	}
+	files := make([]io.Reader, 0, len(form.File["file"]))
+	for _, header := range form.File["file"] {
+		file, err := header.Open()
+		if err != nil {
+			_ = c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+		defer file.Close()
+		files = append(files, file)
+	}
+
+	graphImport, err := graph.NewGraphImport(files, format, mode)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	graphImport.Weights = weights
+	graph.SetPendingImport(graphImport)
+	c.JSON(http.StatusOK, fillGraphImportStruct(graphImport))
+}
+
+// @Id getNetworkImport
+// @Summary Get the preview of the import waiting for commit
+// @Tags    Network
+// @Accept  json
+// @Produce json
+// @Success 200 {object} MeshGraphImport
+// @Failure 404 {object} string
+// @Router /api/network/import [get]
+func (h *Handler) getNetworkImport(c *gin.Context) {
+	graphImport := graph.PendingImport()
+	if graphImport == nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "No graph import pending"})
+		return
+	}
+	c.JSON(http.StatusOK, fillGraphImportStruct(graphImport))
+}
+
+// @Id commitNetworkImport
+// @Summary Apply the import waiting for commit to the network graph
+// @Tags    Network
+// @Accept  json
+// @Produce json
+// @Success 200 {object} string
+// @Failure 400 {object} string
+// @Router /api/network/import/commit [post]
+func (h *Handler) commitNetworkImport(c *gin.Context) {
+	if discovery := mm.CurrentDiscovery(); discovery != nil && discovery.IsRunning() {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Can't import while a discovery is running"})
+		return
+	}
+	if err := graph.CommitPendingImport(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "import committed"})
+}
+
+// @Id discardNetworkImport
+// @Summary Discard the import waiting for commit
+// @Tags    Network
+// @Accept  json
+// @Produce json
+// @Success 200 {object} string
+// @Failure 400 {object} string
+// @Router /api/network/import [delete]
+func (h *Handler) discardNetworkImport(c *gin.Context) {
+	if err := graph.DiscardPendingImport(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "import discarded"})
+}
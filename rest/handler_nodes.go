@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"sort"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/vincent-petithory/dataurl"
@@ -37,20 +38,40 @@ func (h *Handler) nodeInfoGetCmd(m *MeshNode) error {
 	m.Groups = int(cfg.Groups)
 	m.Binded = int(cfg.BindedServer)
 	m.Flags = int(cfg.Flags)
+	m.LastSeen = time.Now().UTC().Format(time.RFC3339)
+	meshmesh.UpdateNodeInfo(meshmesh.MeshNodeId(m.ID), rev.Revision, cfg.NodeConfig())
 
 	return nil
 }
 
 func (h *Handler) fillNodeStruct(dev graph.NodeDevice, withInfo bool, network *graph.Network) MeshNode {
 	jsonNode := MeshNode{
-		ID:       uint(dev.ID()),
-		Tag:      string(dev.Device().Tag()),
-		InUse:    dev.Device().InUse(),
-		Path:     graph.FmtNodePath(network, dev),
-		Firmware: []MeshNodeFirmware{},
+		ID:            uint(dev.ID()),
+		Tag:           string(dev.Device().Tag()),
+		InUse:         dev.Device().InUse(),
+		IsLocal:       dev.ID() == network.LocalDeviceId(),
+		Path:          graph.FmtNodePath(network, dev),
+		Firmware:      []MeshNodeFirmware{},
+		Chip:          dev.Device().Chip(),
+		WeightProfile: dev.Device().WeightProfile(),
+		Revision:      dev.Device().Firmware(),
+		Location:      dev.Device().Location(),
+		Notes:         dev.Device().Notes(),
+		Labels:        dev.Device().Labels(),
+	}
+	// The stored values are replaced by the ones read from the node when withInfo is set
+	if config, ok := dev.Device().Config(); ok {
+		jsonNode.Channel = int8(config.Channel)
+		jsonNode.TxPower = int8(config.TxPower)
+		jsonNode.Groups = int(config.Groups)
+		jsonNode.Binded = int(config.BindedServer)
+		jsonNode.Flags = int(config.Flags)
+	}
+	if lastSeen := dev.Device().LastSeen(); !lastSeen.IsZero() {
+		jsonNode.LastSeen = lastSeen.UTC().Format(time.RFC3339)
 	}
 
-	if h.firmwareUploadProcedure == nil || h.firmwareUploadProcedure.IsComplete() {
+	if procedure := h.firmwareProcedure(); procedure == nil || procedure.IsComplete() {
 		if withInfo {
 			err := h.nodeInfoGetCmd(&jsonNode)
 			if err != nil {
@@ -81,18 +102,12 @@ func (h *Handler) getNodes(c *gin.Context) {
 
 	p := req.toGetListParams()
 
-	network := graph.GetMainNetwork()
+	network := graph.GetMainNetworkSnapshot()
 	nodes := network.Nodes()
 	jsonNodes := make([]MeshNode, 0, nodes.Len())
 	for nodes.Next() {
 		dev := nodes.Node().(graph.NodeDevice)
-		jsonNodes = append(jsonNodes, MeshNode{
-			ID:      uint(dev.ID()),
-			Tag:     string(dev.Device().Tag()),
-			InUse:   dev.Device().InUse(),
-			Path:    graph.FmtNodePath(network, dev),
-			IsLocal: dev.ID() == network.LocalDeviceId(),
-		})
+		jsonNodes = append(jsonNodes, h.fillNodeStruct(dev, false, network))
 	}
 
 	sort.Slice(jsonNodes, func(i, j int) bool {
@@ -147,17 +162,21 @@ func (h *Handler) createNode(c *gin.Context) {
 		return
 	}
 
-	network := graph.GetMainNetwork()
-	_, err = network.GetNodeDevice(int64(req.ID))
-	if err == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "Node already exists"})
+	dev := graph.NewNodeDevice(int64(req.ID), req.InUse, req.Tag)
+	var network *graph.Network
+	err = graph.UpdateMainNetwork(func(main *graph.Network) error {
+		if main.NodeIdExists(int64(req.ID)) {
+			return fmt.Errorf("Node already exists")
+		}
+		main.AddNode(dev)
+		network = main
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
 	}
 
-	dev := graph.NewNodeDevice(int64(req.ID), req.InUse, req.Tag)
-	network.AddNode(dev)
-	graph.NotifyMainNetworkChanged()
-
 	jsonNode := h.fillNodeStruct(dev, false, network)
 
 	c.JSON(http.StatusOK, jsonNode)
@@ -182,7 +201,7 @@ func (h *Handler) getOneNode(c *gin.Context) {
 		return
 	}
 
-	network := graph.GetMainNetwork()
+	network := graph.GetMainNetworkSnapshot()
 	dev, err := network.GetNodeDevice(int64(id))
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"message": "Node not found: " + err.Error()})
@@ -193,6 +212,78 @@ func (h *Handler) getOneNode(c *gin.Context) {
 	c.JSON(http.StatusOK, jsonNode)
 }
 
+// maxNodeRoutes limit the alternative routes of a single request
+const maxNodeRoutes = 10
+
+// @Id      getNodeRoutes
+// @Summary Get the alternative routes to a node
+// @Tags    Nodes
+// @Accept  json
+// @Produce json
+// @Param   id       path     string true  "Node ID"
+// @Param   k        query    int    false "Number of routes"
+// @Param   max_hops query    int    false "Maximum number of hops"
+// @Param   metric   query    string false "Route metric: weight or bottleneck"
+// @Success 200      {array}  MeshRoute
+// @Failure 400      {string} string
+// @Router  /api/nodes/{id}/routes [get]
+func (h *Handler) getNodeRoutes(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	req := NodeRoutesRequest{}
+	err = c.ShouldBindQuery(&req)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	if req.K > maxNodeRoutes {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("At most %d routes can be requested", maxNodeRoutes)})
+		return
+	}
+	opts := graph.RouteOptions{K: req.K, MaxHops: req.MaxHops}
+	if req.Metric != "" {
+		opts.Metric, err = graph.ParseRouteMetric(req.Metric)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+	}
+
+	network := graph.GetMainNetworkSnapshot()
+	if !network.NodeIdExists(int64(id)) {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Node not found"})
+		return
+	}
+	routes, err := network.Routes(int64(id), opts)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+		return
+	}
+
+	jsonRoutes := make([]MeshRoute, 0, len(routes))
+	for _, route := range routes {
+		nodes := make([]uint, len(route.Path))
+		for i, nodeId := range route.Path {
+			nodes[i] = uint(nodeId)
+		}
+		jsonRoutes = append(jsonRoutes, MeshRoute{
+			Path:       utils.FmtPath2Str(route.Path),
+			Nodes:      nodes,
+			Hops:       route.Hops(),
+			Weight:     route.Weight,
+			Bottleneck: route.Bottleneck,
+		})
+	}
+
+	c.Header("Content-Range", fmt.Sprintf("%d-%d/%d", 0, len(jsonRoutes), len(jsonRoutes)))
+	c.JSON(http.StatusOK, jsonRoutes)
+}
+
 // @Id updateNode
 // @Summary Update node
 // @Tags    Nodes
@@ -225,9 +316,40 @@ func (h *Handler) updateNode(c *gin.Context) {
 		return
 	}
 
-	dev.Device().SetTag(req.Tag)
-	dev.Device().SetInUse(req.InUse)
-	graph.NotifyMainNetworkChanged()
+	if req.WeightProfile != nil && *req.WeightProfile != "" {
+		if _, ok := meshmesh.GetWeightProfiles().Get(*req.WeightProfile); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "Unknown weight profile: " + *req.WeightProfile})
+			return
+		}
+	}
+	if req.Labels != nil {
+		if err := graph.ValidateLabels(*req.Labels); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+			return
+		}
+	}
+
+	_ = graph.UpdateMainNetwork(func(network *graph.Network) error {
+		dev.Device().SetTag(req.Tag)
+		dev.Device().SetInUse(req.InUse)
+		if req.Chip != nil {
+			dev.Device().SetChip(*req.Chip)
+		}
+		if req.WeightProfile != nil {
+			dev.Device().SetWeightProfile(*req.WeightProfile)
+		}
+		if req.Location != nil {
+			dev.Device().SetLocation(*req.Location)
+		}
+		if req.Notes != nil {
+			dev.Device().SetNotes(*req.Notes)
+		}
+		if req.Labels != nil {
+			dev.Device().SetLabels(*req.Labels)
+		}
+		graph.NotifyMainNetworkChanged()
+		return nil
+	})
 
 	if req.Firmware != "" {
 		firmware, err := dataurl.DecodeString(req.Firmware)
@@ -290,17 +412,20 @@ func (h *Handler) deleteNode(c *gin.Context) {
 		return
 	}
 
-	network := graph.GetMainNetwork()
-	dev, err := network.GetNodeDevice(int64(id))
+	var jsonNode MeshNode
+	err = graph.UpdateMainNetwork(func(network *graph.Network) error {
+		dev, err := network.GetNodeDevice(int64(id))
+		if err != nil {
+			return err
+		}
+		jsonNode = h.fillNodeStruct(dev, false, network)
+		network.RemoveNode(int64(id))
+		return nil
+	})
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"message": "Node not found: " + err.Error()})
 		return
 	}
 
-	jsonNode := h.fillNodeStruct(dev, false, network)
-
-	network.RemoveNode(int64(id))
-	graph.NotifyMainNetworkChanged()
-
 	c.JSON(http.StatusOK, jsonNode)
 }
@@ -0,0 +1,145 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	mm "leguru.net/m/v2/meshmesh"
+	"leguru.net/m/v2/utils"
+)
+
+const defaultRssiWindow = 7 * 24 * time.Hour
+
+func (r RssiHistoryRequest) since() (time.Time, error) {
+	window := defaultRssiWindow
+	if r.Since != "" {
+		var err error
+		window, err = time.ParseDuration(r.Since)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+	return time.Now().Add(-window), nil
+}
+
+// parseRssiRequest returns the link and the start of the window of a rssi request
+func parseRssiRequest(c *gin.Context) (mm.MeshNodeId, mm.MeshNodeId, time.Time, error) {
+	fromToId, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return 0, 0, time.Time{}, err
+	}
+	req := RssiHistoryRequest{}
+	err = c.ShouldBindQuery(&req)
+	if err != nil {
+		return 0, 0, time.Time{}, err
+	}
+	since, err := req.since()
+	if err != nil {
+		return 0, 0, time.Time{}, err
+	}
+	from, to := parseFromToId(uint(fromToId))
+	return mm.MeshNodeId(from), mm.MeshNodeId(to), since, nil
+}
+
+func fillLinkTrendStruct(trend mm.RssiTrend) MeshLinkTrend {
+	jsonTrend := MeshLinkTrend{
+		ID:        uint(trend.From) + uint(trend.To)<<24,
+		From:      utils.FmtNodeId(int64(trend.From)),
+		To:        utils.FmtNodeId(int64(trend.To)),
+		Samples:   trend.Samples,
+		Rssi1:     trend.Latest.Rssi1,
+		Rssi2:     trend.Latest.Rssi2,
+		Mean:      trend.Mean,
+		Slope:     trend.Slope,
+		Drop:      trend.Drop,
+		Degrading: trend.Degrading,
+	}
+	if trend.Samples > 0 {
+		jsonTrend.First = trend.First.Format(time.RFC3339)
+		jsonTrend.Last = trend.Last.Format(time.RFC3339)
+	}
+	return jsonTrend
+}
+
+// @Id getLinkRssiHistory
+// @Summary Get the raw rssi samples of a link
+// @Tags    Links
+// @Accept  json
+// @Produce json
+// @Param   id path int true "Link ID"
+// @Param   since query string false "Time window, e.g. 24h"
+// @Success 200 {array} MeshRssiSample
+// @Failure 400 {object} string
+// @Router /api/links/{id}/rssi [get]
+func (h *Handler) getLinkRssiHistory(c *gin.Context) {
+	from, to, since, err := parseRssiRequest(c)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	samples := mm.GetRssiHistory().Samples(from, to, since)
+	jsonSamples := make([]MeshRssiSample, len(samples))
+	for i, sample := range samples {
+		jsonSamples[i] = MeshRssiSample{Time: sample.Time.Format(time.RFC3339), Rssi1: sample.Rssi1, Rssi2: sample.Rssi2}
+	}
+
+	c.Header("Content-Range", fmt.Sprintf("%d-%d/%d", 0, len(jsonSamples), len(jsonSamples)))
+	c.JSON(http.StatusOK, jsonSamples)
+}
+
+// @Id getLinkRssiTrend
+// @Summary Get the rssi trend of a link
+// @Tags    Links
+// @Accept  json
+// @Produce json
+// @Param   id path int true "Link ID"
+// @Param   since query string false "Time window, e.g. 24h"
+// @Success 200 {object} MeshLinkTrend
+// @Failure 400 {object} string
+// @Router /api/links/{id}/trend [get]
+func (h *Handler) getLinkRssiTrend(c *gin.Context) {
+	from, to, since, err := parseRssiRequest(c)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, fillLinkTrendStruct(mm.GetRssiHistory().Trend(from, to, since)))
+}
+
+// @Id getDegradingLinks
+// @Summary Get the links whose rssi is getting worse, worst first
+// @Tags    Links
+// @Accept  json
+// @Produce json
+// @Param   since query string false "Time window, e.g. 24h"
+// @Success 200 {array} MeshLinkTrend
+// @Failure 400 {object} string
+// @Router /api/links/degrading [get]
+func (h *Handler) getDegradingLinks(c *gin.Context) {
+	req := RssiHistoryRequest{}
+	err := c.ShouldBindQuery(&req)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	since, err := req.since()
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	trends := mm.GetRssiHistory().Degrading(since)
+	jsonTrends := make([]MeshLinkTrend, len(trends))
+	for i, trend := range trends {
+		jsonTrends[i] = fillLinkTrendStruct(trend)
+	}
+
+	c.Header("Content-Range", fmt.Sprintf("%d-%d/%d", 0, len(jsonTrends), len(jsonTrends)))
+	c.JSON(http.StatusOK, jsonTrends)
+}
@@ -0,0 +1,179 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"leguru.net/m/v2/graph"
+	mm "leguru.net/m/v2/meshmesh"
+)
+
+func fillWeightProfileStruct(profile mm.WeightProfile) MeshWeightProfile {
+	table := make([]MeshWeightPoint, len(profile.Table))
+	for i, point := range profile.Table {
+		table[i] = MeshWeightPoint{Rssi: point.Rssi, Weight: point.Weight}
+	}
+	return MeshWeightProfile{
+		ID:        profile.Name,
+		Model:     profile.Model,
+		Chips:     append([]string{}, profile.Chips...),
+		RssiMin:   profile.RssiMin,
+		RssiMax:   profile.RssiMax,
+		Midpoint:  profile.Midpoint,
+		Steepness: profile.Steepness,
+		Table:     table,
+	}
+}
+
+func (p MeshWeightProfile) toWeightProfile() mm.WeightProfile {
+	table := make([]mm.WeightPoint, len(p.Table))
+	for i, point := range p.Table {
+		table[i] = mm.WeightPoint{Rssi: point.Rssi, Weight: point.Weight}
+	}
+	return mm.WeightProfile{
+		Name:      p.ID,
+		Model:     p.Model,
+		Chips:     p.Chips,
+		RssiMin:   p.RssiMin,
+		RssiMax:   p.RssiMax,
+		Midpoint:  p.Midpoint,
+		Steepness: p.Steepness,
+		Table:     table,
+	}
+}
+
+// @Id getWeightProfiles
+// @Summary Get the rssi weight profiles
+// @Tags    WeightProfiles
+// @Accept  json
+// @Produce json
+// @Success 200 {array} MeshWeightProfile
+// @Failure 400 {object} string
+// @Router /api/weightProfiles [get]
+func (h *Handler) getWeightProfiles(c *gin.Context) {
+	profiles := mm.GetWeightProfiles().List()
+	jsonProfiles := make([]MeshWeightProfile, len(profiles))
+	for i, profile := range profiles {
+		jsonProfiles[i] = fillWeightProfileStruct(profile)
+	}
+
+	c.Header("Content-Range", fmt.Sprintf("%d-%d/%d", 0, len(jsonProfiles), len(jsonProfiles)))
+	c.JSON(http.StatusOK, jsonProfiles)
+}
+
+// @Id getOneWeightProfile
+// @Summary Get one rssi weight profile
+// @Tags    WeightProfiles
+// @Accept  json
+// @Produce json
+// @Param   id path string true "Profile name"
+// @Success 200 {object} MeshWeightProfile
+// @Failure 404 {object} string
+// @Router /api/weightProfiles/{id} [get]
+func (h *Handler) getOneWeightProfile(c *gin.Context) {
+	profile, ok := mm.GetWeightProfiles().Get(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Weight profile not found"})
+		return
+	}
+	c.JSON(http.StatusOK, fillWeightProfileStruct(profile))
+}
+
+// @Id createWeightProfile
+// @Summary Create a rssi weight profile
+// @Tags    WeightProfiles
+// @Accept  json
+// @Produce json
+// @Param   body body MeshWeightProfile true "Weight profile"
+// @Success 200 {object} MeshWeightProfile
+// @Failure 400 {object} string
+// @Router /api/weightProfiles [post]
+func (h *Handler) createWeightProfile(c *gin.Context) {
+	req := MeshWeightProfile{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	if _, ok := mm.GetWeightProfiles().Get(req.ID); ok {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Weight profile already exists"})
+		return
+	}
+	h.setWeightProfile(c, req)
+}
+
+// @Id updateWeightProfile
+// @Summary Update a rssi weight profile
+// @Tags    WeightProfiles
+// @Accept  json
+// @Produce json
+// @Param   id path string true "Profile name"
+// @Param   body body MeshWeightProfile true "Weight profile"
+// @Success 200 {object} MeshWeightProfile
+// @Failure 400 {object} string
+// @Router /api/weightProfiles/{id} [put]
+func (h *Handler) updateWeightProfile(c *gin.Context) {
+	req := MeshWeightProfile{}
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	req.ID = c.Param("id")
+	h.setWeightProfile(c, req)
+}
+
+func (h *Handler) setWeightProfile(c *gin.Context, req MeshWeightProfile) {
+	profile := req.toWeightProfile()
+	err := mm.GetWeightProfiles().Set(profile)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid weight profile: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, fillWeightProfileStruct(profile))
+}
+
+// @Id deleteWeightProfile
+// @Summary Delete a rssi weight profile
+// @Tags    WeightProfiles
+// @Accept  json
+// @Produce json
+// @Param   id path string true "Profile name"
+// @Success 200 {object} MeshWeightProfile
+// @Failure 400 {object} string
+// @Router /api/weightProfiles/{id} [delete]
+func (h *Handler) deleteWeightProfile(c *gin.Context) {
+	profile, ok := mm.GetWeightProfiles().Get(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Weight profile not found"})
+		return
+	}
+
+	err := mm.GetWeightProfiles().Delete(profile.Name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, fillWeightProfileStruct(profile))
+}
+
+// @Id recomputeWeights
+// @Summary Recompute the weight of every link from the stored raw rssi with the current profiles
+// @Tags    WeightProfiles
+// @Accept  json
+// @Produce json
+// @Success 200 {object} RecomputeWeightsReply
+// @Failure 400 {object} string
+// @Router /api/weightProfiles/recompute [post]
+func (h *Handler) recomputeWeights(c *gin.Context) {
+	var changed int
+	_ = graph.UpdateMainNetwork(func(network *graph.Network) error {
+		changed = mm.RecomputeWeights(network, mm.GetRssiHistory())
+		return nil
+	})
+	c.JSON(http.StatusOK, RecomputeWeightsReply{Changed: changed})
+}
@@ -40,6 +40,14 @@ type UpdateNodeRequest struct {
 	DevTag   string `json:"dev_tag"`
 	Channel  int8   `json:"channel"`
 	TxPower  int8   `json:"tx_power"`
+	// Chip type and rssi weight profile of the node, empty for the defaults. The missing
+	// fields of this group and of the next one are left unchanged
+	Chip          *string `json:"chip"`
+	WeightProfile *string `json:"weight_profile"`
+	// Room or location, free-form notes and user labels of the node
+	Location *string   `json:"location"`
+	Notes    *string   `json:"notes"`
+	Labels   *[]string `json:"labels"`
 }
 
 type MeshNodeFirmware struct {
@@ -48,26 +56,53 @@ type MeshNodeFirmware struct {
 }
 
 type MeshNode struct {
-	ID       uint               `json:"id"`
-	Tag      string             `json:"tag"`
-	InUse    bool               `json:"in_use"`
-	IsLocal  bool               `json:"is_local"`
-	Firmware []MeshNodeFirmware `json:"firmware"`
-	Progress int                `json:"progress"`
-	Path     string             `json:"path"`
-	Revision string             `json:"revision"`
-	Error    string             `json:"error"`
-	DevTag   string             `json:"dev_tag"`
-	Channel  int8               `json:"channel"`
-	TxPower  int8               `json:"tx_power"`
-	Groups   int                `json:"groups"`
-	Binded   int                `json:"binded"`
-	Flags    int                `json:"flags"`
+	ID            uint               `json:"id"`
+	Tag           string             `json:"tag"`
+	InUse         bool               `json:"in_use"`
+	IsLocal       bool               `json:"is_local"`
+	Firmware      []MeshNodeFirmware `json:"firmware"`
+	Progress      int                `json:"progress"`
+	Path          string             `json:"path"`
+	Revision      string             `json:"revision"`
+	Error         string             `json:"error"`
+	DevTag        string             `json:"dev_tag"`
+	Channel       int8               `json:"channel"`
+	TxPower       int8               `json:"tx_power"`
+	Groups        int                `json:"groups"`
+	Binded        int                `json:"binded"`
+	Flags         int                `json:"flags"`
+	Chip          string             `json:"chip"`
+	WeightProfile string             `json:"weight_profile"`
+	// Last reply of the node to the hub, empty if never seen
+	LastSeen string   `json:"last_seen"`
+	Location string   `json:"location"`
+	Notes    string   `json:"notes"`
+	Labels   []string `json:"labels"`
+}
+
+type NodeRoutesRequest struct {
+	// Number of alternative routes, 1 if missing
+	K int `form:"k"`
+	// Maximum number of hops, 0 for the configured limit
+	MaxHops int `form:"max_hops"`
+	// Metric of the routes: weight or bottleneck, empty for the configured one
+	Metric string `form:"metric"`
+}
+
+type MeshRoute struct {
+	Path       string  `json:"path"`
+	Nodes      []uint  `json:"nodes"`
+	Hops       int     `json:"hops"`
+	Weight     float64 `json:"weight"`
+	Bottleneck float64 `json:"bottleneck"`
 }
 
 type UpdateLinkRequest struct {
-	ID     uint    `json:"id"`
-	Weight float32 `json:"weight"`
+	ID        uint    `json:"id"`
+	Weight    float32 `json:"weight"`
+	Pinned    *bool   `json:"pinned"`
+	Excluded  *bool   `json:"excluded"`
+	Preferred *bool   `json:"preferred"`
 }
 
 type MeshLink struct {
@@ -76,6 +111,9 @@ type MeshLink struct {
 	To          int64   `json:"to"`
 	Weight      float32 `json:"weight"`
 	Description string  `json:"description"`
+	Pinned      bool    `json:"pinned"`
+	Excluded    bool    `json:"excluded"`
+	Preferred   bool    `json:"preferred"`
 }
 
 func (l MeshLink) Sort(other MeshLink, sortType SortType, sortBy SortFieldType) bool {
@@ -117,7 +155,23 @@ func (l MeshLink) Sort(other MeshLink, sortType SortType, sortBy SortFieldType)
 }
 
 type CtrlDiscoveryRequest struct {
+	// Mode is "refresh" to start from the current graph, "pause", "resume" or "cancel" to control a started procedure
 	Mode string `json:"mode"`
+	// Nodes restrict the discovery to the listed nodes, Subtree include the nodes routed through them
+	Nodes   []uint `json:"nodes"`
+	Subtree bool   `json:"subtree"`
+	// Optional parameters of the run, the missing ones use the configured defaults
+	Slots       *int     `json:"slots"`
+	Mask        *int     `json:"mask"`
+	Filter      *int     `json:"filter"`
+	Wait        *float64 `json:"wait"`
+	Repetitions *int     `json:"repetitions"`
+	Aggregation string   `json:"aggregation"`
+	RssiMin     *int     `json:"rssi_min"`
+	RssiMax     *int     `json:"rssi_max"`
+	// DryRun produce a proposal to review instead of changing the main network
+	DryRun          *bool    `json:"dry_run"`
+	WeightThreshold *float64 `json:"weight_threshold"`
 }
 
 type MeshNeighbor struct {
@@ -130,10 +184,104 @@ type MeshNeighbor struct {
 }
 
 type MeshDiscoveryState struct {
-	ID        int64  `json:"id"`
-	Status    string `json:"status"`
-	CurrentId string `json:"current_id"`
-	Repeat    int    `json:"repeat"`
+	ID          int64    `json:"id"`
+	Status      string   `json:"status"`
+	CurrentId   string   `json:"current_id"`
+	Repeat      int      `json:"repeat"`
+	Targets     []string `json:"targets"`
+	Repetitions int      `json:"repetitions"`
+	Done        int      `json:"done"`
+	Total       int      `json:"total"`
+	Eta         int      `json:"eta"`
+	Error       string   `json:"error"`
+}
+
+type MeshDiscoveryDiffItem struct {
+	ID        int     `json:"id"`
+	Kind      string  `json:"kind"`
+	Node      string  `json:"node"`
+	From      string  `json:"from"`
+	To        string  `json:"to"`
+	OldWeight float64 `json:"old_weight"`
+	NewWeight float64 `json:"new_weight"`
+	OldPath   string  `json:"old_path"`
+	NewPath   string  `json:"new_path"`
+	Applied   bool    `json:"applied"`
+	Stale     bool    `json:"stale"`
+}
+
+type ApplyDiscoveryProposalRequest struct {
+	// Items to apply, empty apply all the items of the proposal
+	Items []int `json:"items"`
+}
+
+type MeshAdoptionNeighbor struct {
+	ID   string `json:"id"`
+	Rssi int16  `json:"rssi"`
+}
+
+type MeshAdoption struct {
+	ID        uint                   `json:"id"`
+	Node      string                 `json:"node"`
+	Server    string                 `json:"server"`
+	Neighbors []MeshAdoptionNeighbor `json:"neighbors"`
+	State     string                 `json:"state"`
+	Tag       string                 `json:"tag"`
+	Revision  string                 `json:"revision"`
+	Channel   int                    `json:"channel"`
+	TxPower   int                    `json:"tx_power"`
+	Groups    int                    `json:"groups"`
+	Binded    int                    `json:"binded"`
+	Flags     int                    `json:"flags"`
+	Error     string                 `json:"error"`
+	Created   string                 `json:"created"`
+	Updated   string                 `json:"updated"`
+}
+
+type RssiHistoryRequest struct {
+	// Time window of the samples, e.g. 24h. Empty for the last week
+	Since string `form:"since"`
+}
+
+type MeshRssiSample struct {
+	Time  string `json:"time"`
+	Rssi1 int16  `json:"rssi1"`
+	Rssi2 int16  `json:"rssi2"`
+}
+
+type MeshLinkTrend struct {
+	ID        uint    `json:"id"`
+	From      string  `json:"from"`
+	To        string  `json:"to"`
+	Samples   int     `json:"samples"`
+	First     string  `json:"first"`
+	Last      string  `json:"last"`
+	Rssi1     int16   `json:"rssi1"`
+	Rssi2     int16   `json:"rssi2"`
+	Mean      float64 `json:"mean"`
+	Slope     float64 `json:"slope"`
+	Drop      float64 `json:"drop"`
+	Degrading bool    `json:"degrading"`
+}
+
+type MeshWeightPoint struct {
+	Rssi   int16   `json:"rssi"`
+	Weight float64 `json:"weight"`
+}
+
+type MeshWeightProfile struct {
+	ID        string            `json:"id"`
+	Model     string            `json:"model"`
+	Chips     []string          `json:"chips"`
+	RssiMin   int16             `json:"rssi_min"`
+	RssiMax   int16             `json:"rssi_max"`
+	Midpoint  float64           `json:"midpoint"`
+	Steepness float64           `json:"steepness"`
+	Table     []MeshWeightPoint `json:"table"`
+}
+
+type RecomputeWeightsReply struct {
+	Changed int `json:"changed"`
 }
 
 type MeshFirmware struct {
@@ -156,9 +304,84 @@ var acceptFilters = map[string]struct{}{
 }
 
 type EsphomeServer struct {
-	ID      uint   `json:"id"`
-	Address string `json:"address"`
-	Clients int    `json:"clients"`
+	ID         uint     `json:"id"`
+	Node       string   `json:"node"`
+	Address    string   `json:"address"`
+	Addresses  []string `json:"addresses"`
+	RemotePort int      `json:"remote_port"`
+	Listening  bool     `json:"listening"`
+	BindError  string   `json:"bind_error"`
+	Clients    int      `json:"clients"`
+	Proxy      bool     `json:"proxy"`
+	Online     bool     `json:"online"`
+}
+
+type EsphomeNodeCache struct {
+	ID       uint   `json:"id"`
+	Node     string `json:"node"`
+	Tag      string `json:"tag"`
+	Entities int    `json:"entities"`
+	States   int    `json:"states"`
+	Commands int    `json:"commands"`
+	LastSeen string `json:"last_seen"`
+	Age      string `json:"age"`
+	Stale    bool   `json:"stale"`
+}
+
+type EsphomeOtaSession struct {
+	ID          int     `json:"id"`
+	Node        string  `json:"node"`
+	Source      string  `json:"source"`
+	Client      string  `json:"client"`
+	State       string  `json:"state"`
+	Version     int     `json:"version"`
+	Compression bool    `json:"compression"`
+	Auth        bool    `json:"auth"`
+	Size        uint32  `json:"size"`
+	Md5         string  `json:"md5"`
+	Sent        uint32  `json:"sent"`
+	Percent     float64 `json:"percent"`
+	Stalled     bool    `json:"stalled"`
+	Started     string  `json:"started"`
+	Finished    string  `json:"finished"`
+	Error       string  `json:"error"`
+}
+
+type EsphomePortAllocation struct {
+	ID            uint     `json:"id"`
+	Node          string   `json:"node"`
+	Tag           string   `json:"tag"`
+	BindAddresses []string `json:"bind_addresses"`
+	ApiPort       int      `json:"api_port"`
+	OtaPort       int      `json:"ota_port"`
+	Manual        bool     `json:"manual"`
+}
+
+type UpdatePortAllocationRequest struct {
+	BindAddresses []string `json:"bind_addresses"`
+	ApiPort       int      `json:"api_port"`
+	OtaPort       int      `json:"ota_port"`
+}
+
+type EsphomeAccess struct {
+	ApiAllow          []string `json:"api_allow"`
+	OtaAllow          []string `json:"ota_allow"`
+	MaxClientsPerNode int      `json:"max_clients_per_node"`
+	MaxClients        int      `json:"max_clients"`
+}
+
+type EsphomeNodeAccess struct {
+	ID       uint     `json:"id"`
+	Node     string   `json:"node"`
+	ApiAllow []string `json:"api_allow"`
+	OtaAllow []string `json:"ota_allow"`
+	Custom   bool     `json:"custom"`
+	Refused  int      `json:"refused"`
+}
+
+type UpdateNodeAccessRequest struct {
+	ApiAllow []string `json:"api_allow"`
+	OtaAllow []string `json:"ota_allow"`
 }
 
 type EsphomeClient struct {
@@ -242,3 +465,86 @@ func (r GetListRequest) toGetListParams() GetListParams {
 
 	return p
 }
+
+type MeshNodeAnalysis struct {
+	ID           uint     `json:"id"`
+	Node         string   `json:"node"`
+	Hops         int      `json:"hops"`
+	Betweenness  float64  `json:"betweenness"`
+	Relayed      int      `json:"relayed"`
+	Articulation bool     `json:"articulation"`
+	Dependents   []string `json:"dependents"`
+	SinglePath   bool     `json:"single_path"`
+}
+
+type MeshBridgeAnalysis struct {
+	ID         uint     `json:"id"`
+	From       string   `json:"from"`
+	To         string   `json:"to"`
+	Dependents []string `json:"dependents"`
+}
+
+type MeshAnalysis struct {
+	Nodes       []MeshNodeAnalysis   `json:"nodes"`
+	Bridges     []MeshBridgeAnalysis `json:"bridges"`
+	Unreachable []string             `json:"unreachable"`
+}
+
+type MeshRouteChange struct {
+	Node    string `json:"node"`
+	OldPath string `json:"old_path"`
+	NewPath string `json:"new_path"`
+}
+
+type MeshFailureImpact struct {
+	ID       uint              `json:"id"`
+	Node     string            `json:"node"`
+	Lost     []string          `json:"lost"`
+	Rerouted []MeshRouteChange `json:"rerouted"`
+}
+
+type MeshGraphVersion struct {
+	ID       int    `json:"id"`
+	Time     string `json:"time"`
+	Nodes    int    `json:"nodes"`
+	Edges    int    `json:"edges"`
+	Rollback int    `json:"rollback"`
+}
+
+type GraphDiffRequest struct {
+	From int `form:"from" binding:"required"`
+	To   int `form:"to" binding:"required"`
+}
+
+type MeshGraphDiffItem struct {
+	ID        int     `json:"id"`
+	Kind      string  `json:"kind"`
+	Node      string  `json:"node"`
+	From      string  `json:"from"`
+	To        string  `json:"to"`
+	OldWeight float64 `json:"old_weight"`
+	NewWeight float64 `json:"new_weight"`
+	OldPath   string  `json:"old_path"`
+	NewPath   string  `json:"new_path"`
+	OldTag    string  `json:"old_tag"`
+	NewTag    string  `json:"new_tag"`
+	OldInUse  bool    `json:"old_in_use"`
+	NewInUse  bool    `json:"new_in_use"`
+}
+
+type ImportNetworkRequest struct {
+	// Format is graphml, json or csv
+	Format string `form:"format" binding:"required"`
+	// Mode is replace or merge
+	Mode string `form:"mode" binding:"required"`
+	// Weights is the weight kept by a merge for the edges already in the network, imported (the default), existing or newer
+	Weights string `form:"weights"`
+}
+
+type MeshGraphImport struct {
+	Format  string              `json:"format"`
+	Mode    string              `json:"mode"`
+	Weights string              `json:"weights"`
+	Created string              `json:"created"`
+	Items   []MeshGraphDiffItem `json:"items"`
+}
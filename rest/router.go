@@ -50,6 +50,7 @@ func (s router) Register(g gin.IRouter) {
 	{
 		nodesGroup.GET("", h.getNodes)
 		nodesGroup.GET("/:id", h.getOneNode)
+		nodesGroup.GET("/:id/routes", h.getNodeRoutes)
 		nodesGroup.POST("", h.createNode)
 		nodesGroup.PUT("/:id", h.updateNode)
 		nodesGroup.DELETE("/:id", h.deleteNode)
@@ -58,6 +59,9 @@ func (s router) Register(g gin.IRouter) {
 	linksGroup := r.Group("/links")
 	{
 		linksGroup.GET("", h.getLinks)
+		linksGroup.GET("/degrading", h.getDegradingLinks)
+		linksGroup.GET("/:id/rssi", h.getLinkRssiHistory)
+		linksGroup.GET("/:id/trend", h.getLinkRssiTrend)
 		linksGroup.GET("/:id", h.getOneLink)
 		linksGroup.POST("", h.createLink)
 		linksGroup.PUT("/:id", h.updateLink)
@@ -69,12 +73,66 @@ func (s router) Register(g gin.IRouter) {
 		neighborsGroup.GET("", h.getNeighbors)
 		neighborsGroup.GET("/discovery/:id", h.getDiscoveryProcedureState)
 		neighborsGroup.POST("/discovery", h.ctrlDiscoveryProcedure)
+		neighborsGroup.GET("/discovery/proposal", h.getDiscoveryProposal)
+		neighborsGroup.POST("/discovery/proposal", h.applyDiscoveryProposal)
+		neighborsGroup.DELETE("/discovery/proposal", h.rejectDiscoveryProposal)
+	}
+
+	analysisGroup := r.Group("/analysis")
+	{
+		analysisGroup.GET("", h.getAnalysis)
+		analysisGroup.GET("/failure/:id", h.getFailureImpact)
+	}
+
+	historyGroup := r.Group("/history")
+	{
+		historyGroup.GET("", h.getGraphVersions)
+		historyGroup.GET("/diff", h.getGraphDiff)
+		historyGroup.GET("/:id", h.getOneGraphVersion)
+		historyGroup.POST("/:id/rollback", h.rollbackGraphVersion)
+	}
+
+	networkGroup := r.Group("/network")
+	{
+		networkGroup.GET("/export", h.exportNetwork)
+		networkGroup.GET("/import", h.getNetworkImport)
+		networkGroup.POST("/import", h.importNetwork)
+		networkGroup.POST("/import/commit", h.commitNetworkImport)
+		networkGroup.DELETE("/import", h.discardNetworkImport)
+	}
+
+	adoptionsGroup := r.Group("/adoptions")
+	{
+		adoptionsGroup.GET("", h.getAdoptions)
+		adoptionsGroup.GET("/:id", h.getOneAdoption)
+		adoptionsGroup.POST("/:id", h.approveAdoption)
+		adoptionsGroup.DELETE("/:id", h.rejectAdoption)
+	}
+
+	weightProfilesGroup := r.Group("/weightProfiles")
+	{
+		weightProfilesGroup.GET("", h.getWeightProfiles)
+		weightProfilesGroup.GET("/:id", h.getOneWeightProfile)
+		weightProfilesGroup.POST("", h.createWeightProfile)
+		weightProfilesGroup.PUT("/:id", h.updateWeightProfile)
+		weightProfilesGroup.DELETE("/:id", h.deleteWeightProfile)
+		weightProfilesGroup.POST("/recompute", h.recomputeWeights)
 	}
 
 	esphomeServersGroup := r.Group("/esphomeServers")
 	{
 		esphomeServersGroup.GET("", h.getEsphomeServers)
 		esphomeServersGroup.GET("/connections", h.getEsphomeConnections)
+		esphomeServersGroup.GET("/cache", h.getEsphomeCache)
+		esphomeServersGroup.GET("/ota", h.getEsphomeOtaSessions)
+		esphomeServersGroup.GET("/ports", h.getEsphomePorts)
+		esphomeServersGroup.PUT("/ports/:id", h.updateEsphomePorts)
+		esphomeServersGroup.DELETE("/ports/:id", h.deleteEsphomePorts)
+		esphomeServersGroup.GET("/access", h.getEsphomeAccess)
+		esphomeServersGroup.PUT("/access", h.updateEsphomeAccess)
+		esphomeServersGroup.GET("/access/nodes", h.getEsphomeNodesAccess)
+		esphomeServersGroup.PUT("/access/nodes/:id", h.updateEsphomeNodeAccess)
+		esphomeServersGroup.DELETE("/access/nodes/:id", h.deleteEsphomeNodeAccess)
 	}
 
 	esphomeConnectionsGroup := r.Group("/esphomeConnections")
@@ -1225,27 +1225,2142 @@ func (x *ExecuteDiscoveryReply) GetSuccess() bool {
 	return false
 }
 
+// Parameters of a discovery run, the missing ones use the configured defaults
+type DiscoveryParams struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Slots       *uint32                `protobuf:"varint,1,opt,name=slots,proto3,oneof" json:"slots,omitempty"`
+	Mask        *uint32                `protobuf:"varint,2,opt,name=mask,proto3,oneof" json:"mask,omitempty"`
+	Filter      *uint32                `protobuf:"varint,3,opt,name=filter,proto3,oneof" json:"filter,omitempty"`
+	WaitMs      *uint32                `protobuf:"varint,4,opt,name=wait_ms,json=waitMs,proto3,oneof" json:"wait_ms,omitempty"`
+	Repetitions *uint32                `protobuf:"varint,5,opt,name=repetitions,proto3,oneof" json:"repetitions,omitempty"`
+	// One of "last", "min", "mean", "median"
+	Aggregation *string `protobuf:"bytes,6,opt,name=aggregation,proto3,oneof" json:"aggregation,omitempty"`
+	RssiMin     *int32  `protobuf:"varint,7,opt,name=rssi_min,json=rssiMin,proto3,oneof" json:"rssi_min,omitempty"`
+	RssiMax     *int32  `protobuf:"varint,8,opt,name=rssi_max,json=rssiMax,proto3,oneof" json:"rssi_max,omitempty"`
+	// Keep the result as a proposal to review over REST
+	DryRun          *bool    `protobuf:"varint,9,opt,name=dry_run,json=dryRun,proto3,oneof" json:"dry_run,omitempty"`
+	WeightThreshold *float64 `protobuf:"fixed64,10,opt,name=weight_threshold,json=weightThreshold,proto3,oneof" json:"weight_threshold,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DiscoveryParams) Reset() {
+	*x = DiscoveryParams{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiscoveryParams) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiscoveryParams) ProtoMessage() {}
+
+func (x *DiscoveryParams) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiscoveryParams.ProtoReflect.Descriptor instead.
+func (*DiscoveryParams) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *DiscoveryParams) GetSlots() uint32 {
+	if x != nil && x.Slots != nil {
+		return *x.Slots
+	}
+	return 0
+}
+
+func (x *DiscoveryParams) GetMask() uint32 {
+	if x != nil && x.Mask != nil {
+		return *x.Mask
+	}
+	return 0
+}
+
+func (x *DiscoveryParams) GetFilter() uint32 {
+	if x != nil && x.Filter != nil {
+		return *x.Filter
+	}
+	return 0
+}
+
+func (x *DiscoveryParams) GetWaitMs() uint32 {
+	if x != nil && x.WaitMs != nil {
+		return *x.WaitMs
+	}
+	return 0
+}
+
+func (x *DiscoveryParams) GetRepetitions() uint32 {
+	if x != nil && x.Repetitions != nil {
+		return *x.Repetitions
+	}
+	return 0
+}
+
+func (x *DiscoveryParams) GetAggregation() string {
+	if x != nil && x.Aggregation != nil {
+		return *x.Aggregation
+	}
+	return ""
+}
+
+func (x *DiscoveryParams) GetRssiMin() int32 {
+	if x != nil && x.RssiMin != nil {
+		return *x.RssiMin
+	}
+	return 0
+}
+
+func (x *DiscoveryParams) GetRssiMax() int32 {
+	if x != nil && x.RssiMax != nil {
+		return *x.RssiMax
+	}
+	return 0
+}
+
+func (x *DiscoveryParams) GetDryRun() bool {
+	if x != nil && x.DryRun != nil {
+		return *x.DryRun
+	}
+	return false
+}
+
+func (x *DiscoveryParams) GetWeightThreshold() float64 {
+	if x != nil && x.WeightThreshold != nil {
+		return *x.WeightThreshold
+	}
+	return 0
+}
+
+type StartDiscoveryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Start from the current graph instead of an empty one
+	Refresh       bool             `protobuf:"varint,1,opt,name=refresh,proto3" json:"refresh,omitempty"`
+	Params        *DiscoveryParams `protobuf:"bytes,2,opt,name=params,proto3" json:"params,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartDiscoveryRequest) Reset() {
+	*x = StartDiscoveryRequest{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartDiscoveryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartDiscoveryRequest) ProtoMessage() {}
+
+func (x *StartDiscoveryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartDiscoveryRequest.ProtoReflect.Descriptor instead.
+func (*StartDiscoveryRequest) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *StartDiscoveryRequest) GetRefresh() bool {
+	if x != nil {
+		return x.Refresh
+	}
+	return false
+}
+
+func (x *StartDiscoveryRequest) GetParams() *DiscoveryParams {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+type StartDiscoveryReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartDiscoveryReply) Reset() {
+	*x = StartDiscoveryReply{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartDiscoveryReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartDiscoveryReply) ProtoMessage() {}
+
+func (x *StartDiscoveryReply) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartDiscoveryReply.ProtoReflect.Descriptor instead.
+func (*StartDiscoveryReply) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *StartDiscoveryReply) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type DiscoverNodesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Ids   []uint32               `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	// Also rediscover the nodes routed through the listed ones
+	Subtree       bool             `protobuf:"varint,2,opt,name=subtree,proto3" json:"subtree,omitempty"`
+	Params        *DiscoveryParams `protobuf:"bytes,3,opt,name=params,proto3" json:"params,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiscoverNodesRequest) Reset() {
+	*x = DiscoverNodesRequest{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiscoverNodesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiscoverNodesRequest) ProtoMessage() {}
+
+func (x *DiscoverNodesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiscoverNodesRequest.ProtoReflect.Descriptor instead.
+func (*DiscoverNodesRequest) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *DiscoverNodesRequest) GetIds() []uint32 {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *DiscoverNodesRequest) GetSubtree() bool {
+	if x != nil {
+		return x.Subtree
+	}
+	return false
+}
+
+func (x *DiscoverNodesRequest) GetParams() *DiscoveryParams {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+type DiscoverNodesReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Targets       []uint32               `protobuf:"varint,2,rep,packed,name=targets,proto3" json:"targets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiscoverNodesReply) Reset() {
+	*x = DiscoverNodesReply{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiscoverNodesReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiscoverNodesReply) ProtoMessage() {}
+
+func (x *DiscoverNodesReply) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiscoverNodesReply.ProtoReflect.Descriptor instead.
+func (*DiscoverNodesReply) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *DiscoverNodesReply) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DiscoverNodesReply) GetTargets() []uint32 {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+type DiscoveryControlRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One of "pause", "resume", "cancel", empty to only read the state
+	Action        string `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiscoveryControlRequest) Reset() {
+	*x = DiscoveryControlRequest{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiscoveryControlRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiscoveryControlRequest) ProtoMessage() {}
+
+func (x *DiscoveryControlRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiscoveryControlRequest.ProtoReflect.Descriptor instead.
+func (*DiscoveryControlRequest) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *DiscoveryControlRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+type DiscoveryControlReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	State         string                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	CurrentId     uint32                 `protobuf:"varint,2,opt,name=current_id,json=currentId,proto3" json:"current_id,omitempty"`
+	Repeat        uint32                 `protobuf:"varint,3,opt,name=repeat,proto3" json:"repeat,omitempty"`
+	Done          uint32                 `protobuf:"varint,4,opt,name=done,proto3" json:"done,omitempty"`
+	Total         uint32                 `protobuf:"varint,5,opt,name=total,proto3" json:"total,omitempty"`
+	EtaSeconds    uint32                 `protobuf:"varint,6,opt,name=eta_seconds,json=etaSeconds,proto3" json:"eta_seconds,omitempty"`
+	Error         string                 `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiscoveryControlReply) Reset() {
+	*x = DiscoveryControlReply{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiscoveryControlReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiscoveryControlReply) ProtoMessage() {}
+
+func (x *DiscoveryControlReply) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiscoveryControlReply.ProtoReflect.Descriptor instead.
+func (*DiscoveryControlReply) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *DiscoveryControlReply) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *DiscoveryControlReply) GetCurrentId() uint32 {
+	if x != nil {
+		return x.CurrentId
+	}
+	return 0
+}
+
+func (x *DiscoveryControlReply) GetRepeat() uint32 {
+	if x != nil {
+		return x.Repeat
+	}
+	return 0
+}
+
+func (x *DiscoveryControlReply) GetDone() uint32 {
+	if x != nil {
+		return x.Done
+	}
+	return 0
+}
+
+func (x *DiscoveryControlReply) GetTotal() uint32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *DiscoveryControlReply) GetEtaSeconds() uint32 {
+	if x != nil {
+		return x.EtaSeconds
+	}
+	return 0
+}
+
+func (x *DiscoveryControlReply) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
 type NetworkNodesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *NetworkNodesRequest) Reset() {
-	*x = NetworkNodesRequest{}
-	mi := &file_meshmesh_meshmesh_proto_msgTypes[22]
+func (x *NetworkNodesRequest) Reset() {
+	*x = NetworkNodesRequest{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkNodesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkNodesRequest) ProtoMessage() {}
+
+func (x *NetworkNodesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkNodesRequest.ProtoReflect.Descriptor instead.
+func (*NetworkNodesRequest) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{29}
+}
+
+type NetworkNodesReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Nodes         []*NetworkNode         `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkNodesReply) Reset() {
+	*x = NetworkNodesReply{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkNodesReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkNodesReply) ProtoMessage() {}
+
+func (x *NetworkNodesReply) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkNodesReply.ProtoReflect.Descriptor instead.
+func (*NetworkNodesReply) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *NetworkNodesReply) GetNodes() []*NetworkNode {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+type NetworkEdgesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkEdgesRequest) Reset() {
+	*x = NetworkEdgesRequest{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkEdgesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkEdgesRequest) ProtoMessage() {}
+
+func (x *NetworkEdgesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkEdgesRequest.ProtoReflect.Descriptor instead.
+func (*NetworkEdgesRequest) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{31}
+}
+
+type NetworkEdgesReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Edges         []*NetworkEdge         `protobuf:"bytes,1,rep,name=edges,proto3" json:"edges,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkEdgesReply) Reset() {
+	*x = NetworkEdgesReply{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkEdgesReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkEdgesReply) ProtoMessage() {}
+
+func (x *NetworkEdgesReply) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkEdgesReply.ProtoReflect.Descriptor instead.
+func (*NetworkEdgesReply) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *NetworkEdgesReply) GetEdges() []*NetworkEdge {
+	if x != nil {
+		return x.Edges
+	}
+	return nil
+}
+
+type NetworkNode struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Tag           string                 `protobuf:"bytes,2,opt,name=tag,proto3" json:"tag,omitempty"`
+	Inuse         bool                   `protobuf:"varint,3,opt,name=inuse,proto3" json:"inuse,omitempty"`
+	Chip          string                 `protobuf:"bytes,4,opt,name=chip,proto3" json:"chip,omitempty"`
+	WeightProfile string                 `protobuf:"bytes,5,opt,name=weight_profile,json=weightProfile,proto3" json:"weight_profile,omitempty"`
+	// Firmware revision and configuration last read from the node, config is missing until read
+	Firmware string             `protobuf:"bytes,6,opt,name=firmware,proto3" json:"firmware,omitempty"`
+	Config   *NetworkNodeConfig `protobuf:"bytes,7,opt,name=config,proto3" json:"config,omitempty"`
+	// Unix time in seconds of the last reply of the node, 0 if never seen
+	LastSeen      int64    `protobuf:"varint,8,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
+	Location      string   `protobuf:"bytes,9,opt,name=location,proto3" json:"location,omitempty"`
+	Notes         string   `protobuf:"bytes,10,opt,name=notes,proto3" json:"notes,omitempty"`
+	Labels        []string `protobuf:"bytes,11,rep,name=labels,proto3" json:"labels,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkNode) Reset() {
+	*x = NetworkNode{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkNode) ProtoMessage() {}
+
+func (x *NetworkNode) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkNode.ProtoReflect.Descriptor instead.
+func (*NetworkNode) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *NetworkNode) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *NetworkNode) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *NetworkNode) GetInuse() bool {
+	if x != nil {
+		return x.Inuse
+	}
+	return false
+}
+
+func (x *NetworkNode) GetChip() string {
+	if x != nil {
+		return x.Chip
+	}
+	return ""
+}
+
+func (x *NetworkNode) GetWeightProfile() string {
+	if x != nil {
+		return x.WeightProfile
+	}
+	return ""
+}
+
+func (x *NetworkNode) GetFirmware() string {
+	if x != nil {
+		return x.Firmware
+	}
+	return ""
+}
+
+func (x *NetworkNode) GetConfig() *NetworkNodeConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *NetworkNode) GetLastSeen() int64 {
+	if x != nil {
+		return x.LastSeen
+	}
+	return 0
+}
+
+func (x *NetworkNode) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *NetworkNode) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+func (x *NetworkNode) GetLabels() []string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+type NetworkNodeConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Channel       uint32                 `protobuf:"varint,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	TxPower       uint32                 `protobuf:"varint,2,opt,name=tx_power,json=txPower,proto3" json:"tx_power,omitempty"`
+	Groups        uint32                 `protobuf:"varint,3,opt,name=groups,proto3" json:"groups,omitempty"`
+	BindedServer  uint32                 `protobuf:"varint,4,opt,name=binded_server,json=bindedServer,proto3" json:"binded_server,omitempty"`
+	Flags         uint32                 `protobuf:"varint,5,opt,name=flags,proto3" json:"flags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkNodeConfig) Reset() {
+	*x = NetworkNodeConfig{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkNodeConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkNodeConfig) ProtoMessage() {}
+
+func (x *NetworkNodeConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkNodeConfig.ProtoReflect.Descriptor instead.
+func (*NetworkNodeConfig) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *NetworkNodeConfig) GetChannel() uint32 {
+	if x != nil {
+		return x.Channel
+	}
+	return 0
+}
+
+func (x *NetworkNodeConfig) GetTxPower() uint32 {
+	if x != nil {
+		return x.TxPower
+	}
+	return 0
+}
+
+func (x *NetworkNodeConfig) GetGroups() uint32 {
+	if x != nil {
+		return x.Groups
+	}
+	return 0
+}
+
+func (x *NetworkNodeConfig) GetBindedServer() uint32 {
+	if x != nil {
+		return x.BindedServer
+	}
+	return 0
+}
+
+func (x *NetworkNodeConfig) GetFlags() uint32 {
+	if x != nil {
+		return x.Flags
+	}
+	return 0
+}
+
+type NetworkNodeLabels struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Labels        []string               `protobuf:"bytes,1,rep,name=labels,proto3" json:"labels,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkNodeLabels) Reset() {
+	*x = NetworkNodeLabels{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkNodeLabels) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkNodeLabels) ProtoMessage() {}
+
+func (x *NetworkNodeLabels) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkNodeLabels.ProtoReflect.Descriptor instead.
+func (*NetworkNodeLabels) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *NetworkNodeLabels) GetLabels() []string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+type NetworkEdge struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	From          uint32                 `protobuf:"varint,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            uint32                 `protobuf:"varint,3,opt,name=to,proto3" json:"to,omitempty"`
+	Weight        float32                `protobuf:"fixed32,4,opt,name=weight,proto3" json:"weight,omitempty"`
+	Pinned        bool                   `protobuf:"varint,5,opt,name=pinned,proto3" json:"pinned,omitempty"`
+	Excluded      bool                   `protobuf:"varint,6,opt,name=excluded,proto3" json:"excluded,omitempty"`
+	Preferred     bool                   `protobuf:"varint,7,opt,name=preferred,proto3" json:"preferred,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkEdge) Reset() {
+	*x = NetworkEdge{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkEdge) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkEdge) ProtoMessage() {}
+
+func (x *NetworkEdge) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkEdge.ProtoReflect.Descriptor instead.
+func (*NetworkEdge) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *NetworkEdge) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *NetworkEdge) GetFrom() uint32 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *NetworkEdge) GetTo() uint32 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *NetworkEdge) GetWeight() float32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *NetworkEdge) GetPinned() bool {
+	if x != nil {
+		return x.Pinned
+	}
+	return false
+}
+
+func (x *NetworkEdge) GetExcluded() bool {
+	if x != nil {
+		return x.Excluded
+	}
+	return false
+}
+
+func (x *NetworkEdge) GetPreferred() bool {
+	if x != nil {
+		return x.Preferred
+	}
+	return false
+}
+
+type NetworkEdgeConfigureRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          uint32                 `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            uint32                 `protobuf:"varint,2,opt,name=to,proto3" json:"to,omitempty"`
+	Weight        *float32               `protobuf:"fixed32,3,opt,name=weight,proto3,oneof" json:"weight,omitempty"`
+	Pinned        *bool                  `protobuf:"varint,4,opt,name=pinned,proto3,oneof" json:"pinned,omitempty"`
+	Excluded      *bool                  `protobuf:"varint,5,opt,name=excluded,proto3,oneof" json:"excluded,omitempty"`
+	Preferred     *bool                  `protobuf:"varint,6,opt,name=preferred,proto3,oneof" json:"preferred,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkEdgeConfigureRequest) Reset() {
+	*x = NetworkEdgeConfigureRequest{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkEdgeConfigureRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkEdgeConfigureRequest) ProtoMessage() {}
+
+func (x *NetworkEdgeConfigureRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkEdgeConfigureRequest.ProtoReflect.Descriptor instead.
+func (*NetworkEdgeConfigureRequest) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *NetworkEdgeConfigureRequest) GetFrom() uint32 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *NetworkEdgeConfigureRequest) GetTo() uint32 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *NetworkEdgeConfigureRequest) GetWeight() float32 {
+	if x != nil && x.Weight != nil {
+		return *x.Weight
+	}
+	return 0
+}
+
+func (x *NetworkEdgeConfigureRequest) GetPinned() bool {
+	if x != nil && x.Pinned != nil {
+		return *x.Pinned
+	}
+	return false
+}
+
+func (x *NetworkEdgeConfigureRequest) GetExcluded() bool {
+	if x != nil && x.Excluded != nil {
+		return *x.Excluded
+	}
+	return false
+}
+
+func (x *NetworkEdgeConfigureRequest) GetPreferred() bool {
+	if x != nil && x.Preferred != nil {
+		return *x.Preferred
+	}
+	return false
+}
+
+type NetworkEdgeConfigureReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Edge          *NetworkEdge           `protobuf:"bytes,1,opt,name=edge,proto3" json:"edge,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkEdgeConfigureReply) Reset() {
+	*x = NetworkEdgeConfigureReply{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkEdgeConfigureReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkEdgeConfigureReply) ProtoMessage() {}
+
+func (x *NetworkEdgeConfigureReply) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkEdgeConfigureReply.ProtoReflect.Descriptor instead.
+func (*NetworkEdgeConfigureReply) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *NetworkEdgeConfigureReply) GetEdge() *NetworkEdge {
+	if x != nil {
+		return x.Edge
+	}
+	return nil
+}
+
+type NetworkNodeAnalysis struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Hops of the route from the local node, -1 when unreachable
+	Hops        int32   `protobuf:"varint,2,opt,name=hops,proto3" json:"hops,omitempty"`
+	Betweenness float64 `protobuf:"fixed64,3,opt,name=betweenness,proto3" json:"betweenness,omitempty"`
+	// Routes from the local node passing through the node
+	Relayed      uint32 `protobuf:"varint,4,opt,name=relayed,proto3" json:"relayed,omitempty"`
+	Articulation bool   `protobuf:"varint,5,opt,name=articulation,proto3" json:"articulation,omitempty"`
+	// Nodes losing connectivity if the node fails
+	Dependents    []uint32 `protobuf:"varint,6,rep,packed,name=dependents,proto3" json:"dependents,omitempty"`
+	SinglePath    bool     `protobuf:"varint,7,opt,name=single_path,json=singlePath,proto3" json:"single_path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkNodeAnalysis) Reset() {
+	*x = NetworkNodeAnalysis{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkNodeAnalysis) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkNodeAnalysis) ProtoMessage() {}
+
+func (x *NetworkNodeAnalysis) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkNodeAnalysis.ProtoReflect.Descriptor instead.
+func (*NetworkNodeAnalysis) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *NetworkNodeAnalysis) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *NetworkNodeAnalysis) GetHops() int32 {
+	if x != nil {
+		return x.Hops
+	}
+	return 0
+}
+
+func (x *NetworkNodeAnalysis) GetBetweenness() float64 {
+	if x != nil {
+		return x.Betweenness
+	}
+	return 0
+}
+
+func (x *NetworkNodeAnalysis) GetRelayed() uint32 {
+	if x != nil {
+		return x.Relayed
+	}
+	return 0
+}
+
+func (x *NetworkNodeAnalysis) GetArticulation() bool {
+	if x != nil {
+		return x.Articulation
+	}
+	return false
+}
+
+func (x *NetworkNodeAnalysis) GetDependents() []uint32 {
+	if x != nil {
+		return x.Dependents
+	}
+	return nil
+}
+
+func (x *NetworkNodeAnalysis) GetSinglePath() bool {
+	if x != nil {
+		return x.SinglePath
+	}
+	return false
+}
+
+type NetworkBridgeAnalysis struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          uint32                 `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            uint32                 `protobuf:"varint,2,opt,name=to,proto3" json:"to,omitempty"`
+	Dependents    []uint32               `protobuf:"varint,3,rep,packed,name=dependents,proto3" json:"dependents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkBridgeAnalysis) Reset() {
+	*x = NetworkBridgeAnalysis{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkBridgeAnalysis) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkBridgeAnalysis) ProtoMessage() {}
+
+func (x *NetworkBridgeAnalysis) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkBridgeAnalysis.ProtoReflect.Descriptor instead.
+func (*NetworkBridgeAnalysis) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *NetworkBridgeAnalysis) GetFrom() uint32 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *NetworkBridgeAnalysis) GetTo() uint32 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *NetworkBridgeAnalysis) GetDependents() []uint32 {
+	if x != nil {
+		return x.Dependents
+	}
+	return nil
+}
+
+type NetworkAnalysisRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkAnalysisRequest) Reset() {
+	*x = NetworkAnalysisRequest{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkAnalysisRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkAnalysisRequest) ProtoMessage() {}
+
+func (x *NetworkAnalysisRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkAnalysisRequest.ProtoReflect.Descriptor instead.
+func (*NetworkAnalysisRequest) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{41}
+}
+
+type NetworkAnalysisReply struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Nodes         []*NetworkNodeAnalysis   `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Bridges       []*NetworkBridgeAnalysis `protobuf:"bytes,2,rep,name=bridges,proto3" json:"bridges,omitempty"`
+	Unreachable   []uint32                 `protobuf:"varint,3,rep,packed,name=unreachable,proto3" json:"unreachable,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkAnalysisReply) Reset() {
+	*x = NetworkAnalysisReply{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkAnalysisReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkAnalysisReply) ProtoMessage() {}
+
+func (x *NetworkAnalysisReply) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkAnalysisReply.ProtoReflect.Descriptor instead.
+func (*NetworkAnalysisReply) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *NetworkAnalysisReply) GetNodes() []*NetworkNodeAnalysis {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+func (x *NetworkAnalysisReply) GetBridges() []*NetworkBridgeAnalysis {
+	if x != nil {
+		return x.Bridges
+	}
+	return nil
+}
+
+func (x *NetworkAnalysisReply) GetUnreachable() []uint32 {
+	if x != nil {
+		return x.Unreachable
+	}
+	return nil
+}
+
+type NetworkRouteChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	OldPath       []uint32               `protobuf:"varint,2,rep,packed,name=old_path,json=oldPath,proto3" json:"old_path,omitempty"`
+	NewPath       []uint32               `protobuf:"varint,3,rep,packed,name=new_path,json=newPath,proto3" json:"new_path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkRouteChange) Reset() {
+	*x = NetworkRouteChange{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkRouteChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkRouteChange) ProtoMessage() {}
+
+func (x *NetworkRouteChange) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkRouteChange.ProtoReflect.Descriptor instead.
+func (*NetworkRouteChange) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *NetworkRouteChange) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *NetworkRouteChange) GetOldPath() []uint32 {
+	if x != nil {
+		return x.OldPath
+	}
+	return nil
+}
+
+func (x *NetworkRouteChange) GetNewPath() []uint32 {
+	if x != nil {
+		return x.NewPath
+	}
+	return nil
+}
+
+type NetworkFailureImpactRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkFailureImpactRequest) Reset() {
+	*x = NetworkFailureImpactRequest{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkFailureImpactRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkFailureImpactRequest) ProtoMessage() {}
+
+func (x *NetworkFailureImpactRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkFailureImpactRequest.ProtoReflect.Descriptor instead.
+func (*NetworkFailureImpactRequest) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *NetworkFailureImpactRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type NetworkFailureImpactReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lost          []uint32               `protobuf:"varint,1,rep,packed,name=lost,proto3" json:"lost,omitempty"`
+	Rerouted      []*NetworkRouteChange  `protobuf:"bytes,2,rep,name=rerouted,proto3" json:"rerouted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkFailureImpactReply) Reset() {
+	*x = NetworkFailureImpactReply{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkFailureImpactReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkFailureImpactReply) ProtoMessage() {}
+
+func (x *NetworkFailureImpactReply) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkFailureImpactReply.ProtoReflect.Descriptor instead.
+func (*NetworkFailureImpactReply) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *NetworkFailureImpactReply) GetLost() []uint32 {
+	if x != nil {
+		return x.Lost
+	}
+	return nil
+}
+
+func (x *NetworkFailureImpactReply) GetRerouted() []*NetworkRouteChange {
+	if x != nil {
+		return x.Rerouted
+	}
+	return nil
+}
+
+type NetworkVersion struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Unix time in seconds
+	Time  int64  `protobuf:"varint,2,opt,name=time,proto3" json:"time,omitempty"`
+	Nodes uint32 `protobuf:"varint,3,opt,name=nodes,proto3" json:"nodes,omitempty"`
+	Edges uint32 `protobuf:"varint,4,opt,name=edges,proto3" json:"edges,omitempty"`
+	// Version restored by this one, 0 for the normal changes
+	Rollback      uint32 `protobuf:"varint,5,opt,name=rollback,proto3" json:"rollback,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkVersion) Reset() {
+	*x = NetworkVersion{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkVersion) ProtoMessage() {}
+
+func (x *NetworkVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkVersion.ProtoReflect.Descriptor instead.
+func (*NetworkVersion) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *NetworkVersion) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *NetworkVersion) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *NetworkVersion) GetNodes() uint32 {
+	if x != nil {
+		return x.Nodes
+	}
+	return 0
+}
+
+func (x *NetworkVersion) GetEdges() uint32 {
+	if x != nil {
+		return x.Edges
+	}
+	return 0
+}
+
+func (x *NetworkVersion) GetRollback() uint32 {
+	if x != nil {
+		return x.Rollback
+	}
+	return 0
+}
+
+type NetworkHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkHistoryRequest) Reset() {
+	*x = NetworkHistoryRequest{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkHistoryRequest) ProtoMessage() {}
+
+func (x *NetworkHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkHistoryRequest.ProtoReflect.Descriptor instead.
+func (*NetworkHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{47}
+}
+
+type NetworkHistoryReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Versions      []*NetworkVersion      `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkHistoryReply) Reset() {
+	*x = NetworkHistoryReply{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkHistoryReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkHistoryReply) ProtoMessage() {}
+
+func (x *NetworkHistoryReply) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkHistoryReply.ProtoReflect.Descriptor instead.
+func (*NetworkHistoryReply) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *NetworkHistoryReply) GetVersions() []*NetworkVersion {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+type NetworkDiffItem struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One of "node_added", "node_removed", "node_changed", "edge_added", "edge_removed", "edge_changed", "route_changed"
+	Kind          string   `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Node          uint32   `protobuf:"varint,2,opt,name=node,proto3" json:"node,omitempty"`
+	From          uint32   `protobuf:"varint,3,opt,name=from,proto3" json:"from,omitempty"`
+	To            uint32   `protobuf:"varint,4,opt,name=to,proto3" json:"to,omitempty"`
+	OldWeight     float32  `protobuf:"fixed32,5,opt,name=old_weight,json=oldWeight,proto3" json:"old_weight,omitempty"`
+	NewWeight     float32  `protobuf:"fixed32,6,opt,name=new_weight,json=newWeight,proto3" json:"new_weight,omitempty"`
+	OldPath       []uint32 `protobuf:"varint,7,rep,packed,name=old_path,json=oldPath,proto3" json:"old_path,omitempty"`
+	NewPath       []uint32 `protobuf:"varint,8,rep,packed,name=new_path,json=newPath,proto3" json:"new_path,omitempty"`
+	OldTag        string   `protobuf:"bytes,9,opt,name=old_tag,json=oldTag,proto3" json:"old_tag,omitempty"`
+	NewTag        string   `protobuf:"bytes,10,opt,name=new_tag,json=newTag,proto3" json:"new_tag,omitempty"`
+	OldInuse      bool     `protobuf:"varint,11,opt,name=old_inuse,json=oldInuse,proto3" json:"old_inuse,omitempty"`
+	NewInuse      bool     `protobuf:"varint,12,opt,name=new_inuse,json=newInuse,proto3" json:"new_inuse,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkDiffItem) Reset() {
+	*x = NetworkDiffItem{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkDiffItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkDiffItem) ProtoMessage() {}
+
+func (x *NetworkDiffItem) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkDiffItem.ProtoReflect.Descriptor instead.
+func (*NetworkDiffItem) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *NetworkDiffItem) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *NetworkDiffItem) GetNode() uint32 {
+	if x != nil {
+		return x.Node
+	}
+	return 0
+}
+
+func (x *NetworkDiffItem) GetFrom() uint32 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *NetworkDiffItem) GetTo() uint32 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *NetworkDiffItem) GetOldWeight() float32 {
+	if x != nil {
+		return x.OldWeight
+	}
+	return 0
+}
+
+func (x *NetworkDiffItem) GetNewWeight() float32 {
+	if x != nil {
+		return x.NewWeight
+	}
+	return 0
+}
+
+func (x *NetworkDiffItem) GetOldPath() []uint32 {
+	if x != nil {
+		return x.OldPath
+	}
+	return nil
+}
+
+func (x *NetworkDiffItem) GetNewPath() []uint32 {
+	if x != nil {
+		return x.NewPath
+	}
+	return nil
+}
+
+func (x *NetworkDiffItem) GetOldTag() string {
+	if x != nil {
+		return x.OldTag
+	}
+	return ""
+}
+
+func (x *NetworkDiffItem) GetNewTag() string {
+	if x != nil {
+		return x.NewTag
+	}
+	return ""
+}
+
+func (x *NetworkDiffItem) GetOldInuse() bool {
+	if x != nil {
+		return x.OldInuse
+	}
+	return false
+}
+
+func (x *NetworkDiffItem) GetNewInuse() bool {
+	if x != nil {
+		return x.NewInuse
+	}
+	return false
+}
+
+type NetworkHistoryDiffRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          uint32                 `protobuf:"varint,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            uint32                 `protobuf:"varint,2,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkHistoryDiffRequest) Reset() {
+	*x = NetworkHistoryDiffRequest{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkHistoryDiffRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkHistoryDiffRequest) ProtoMessage() {}
+
+func (x *NetworkHistoryDiffRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkHistoryDiffRequest.ProtoReflect.Descriptor instead.
+func (*NetworkHistoryDiffRequest) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *NetworkHistoryDiffRequest) GetFrom() uint32 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *NetworkHistoryDiffRequest) GetTo() uint32 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+type NetworkHistoryDiffReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*NetworkDiffItem     `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkHistoryDiffReply) Reset() {
+	*x = NetworkHistoryDiffReply{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkHistoryDiffReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkHistoryDiffReply) ProtoMessage() {}
+
+func (x *NetworkHistoryDiffReply) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkHistoryDiffReply.ProtoReflect.Descriptor instead.
+func (*NetworkHistoryDiffReply) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *NetworkHistoryDiffReply) GetItems() []*NetworkDiffItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type NetworkRollbackRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkRollbackRequest) Reset() {
+	*x = NetworkRollbackRequest{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkRollbackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkRollbackRequest) ProtoMessage() {}
+
+func (x *NetworkRollbackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkRollbackRequest.ProtoReflect.Descriptor instead.
+func (*NetworkRollbackRequest) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *NetworkRollbackRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type NetworkRollbackReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkRollbackReply) Reset() {
+	*x = NetworkRollbackReply{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkRollbackReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkRollbackReply) ProtoMessage() {}
+
+func (x *NetworkRollbackReply) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkRollbackReply.ProtoReflect.Descriptor instead.
+func (*NetworkRollbackReply) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *NetworkRollbackReply) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type NetworkImportRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One of "graphml", "json", "csv"
+	Format string `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"`
+	// One of "replace", "merge"
+	Mode string `protobuf:"bytes,2,opt,name=mode,proto3" json:"mode,omitempty"`
+	// The csv format accepts a zip archive with the node and the edge tables
+	Data []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	// When false only the preview of the changes is returned
+	Commit bool `protobuf:"varint,4,opt,name=commit,proto3" json:"commit,omitempty"`
+	// Weight kept by a merge for the edges already in the graph: "imported" (the default), "existing" or "newer"
+	Weights       string `protobuf:"bytes,5,opt,name=weights,proto3" json:"weights,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkImportRequest) Reset() {
+	*x = NetworkImportRequest{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkImportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkImportRequest) ProtoMessage() {}
+
+func (x *NetworkImportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkImportRequest.ProtoReflect.Descriptor instead.
+func (*NetworkImportRequest) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *NetworkImportRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *NetworkImportRequest) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *NetworkImportRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *NetworkImportRequest) GetCommit() bool {
+	if x != nil {
+		return x.Commit
+	}
+	return false
+}
+
+func (x *NetworkImportRequest) GetWeights() string {
+	if x != nil {
+		return x.Weights
+	}
+	return ""
+}
+
+type NetworkImportReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*NetworkDiffItem     `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	Committed     bool                   `protobuf:"varint,2,opt,name=committed,proto3" json:"committed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkImportReply) Reset() {
+	*x = NetworkImportReply{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkImportReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkImportReply) ProtoMessage() {}
+
+func (x *NetworkImportReply) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkImportReply.ProtoReflect.Descriptor instead.
+func (*NetworkImportReply) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *NetworkImportReply) GetItems() []*NetworkDiffItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *NetworkImportReply) GetCommitted() bool {
+	if x != nil {
+		return x.Committed
+	}
+	return false
+}
+
+type NetworkAdoption struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Server        uint32                 `protobuf:"varint,2,opt,name=server,proto3" json:"server,omitempty"`
+	Neighbors     []uint32               `protobuf:"varint,3,rep,packed,name=neighbors,proto3" json:"neighbors,omitempty"`
+	Rssi          []int32                `protobuf:"varint,4,rep,packed,name=rssi,proto3" json:"rssi,omitempty"`
+	State         string                 `protobuf:"bytes,5,opt,name=state,proto3" json:"state,omitempty"`
+	Tag           string                 `protobuf:"bytes,6,opt,name=tag,proto3" json:"tag,omitempty"`
+	Revision      string                 `protobuf:"bytes,7,opt,name=revision,proto3" json:"revision,omitempty"`
+	Error         string                 `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkAdoption) Reset() {
+	*x = NetworkAdoption{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[56]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *NetworkNodesRequest) String() string {
+func (x *NetworkAdoption) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NetworkNodesRequest) ProtoMessage() {}
+func (*NetworkAdoption) ProtoMessage() {}
 
-func (x *NetworkNodesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_meshmesh_meshmesh_proto_msgTypes[22]
+func (x *NetworkAdoption) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[56]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1256,76 +3371,367 @@ func (x *NetworkNodesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NetworkNodesRequest.ProtoReflect.Descriptor instead.
-func (*NetworkNodesRequest) Descriptor() ([]byte, []int) {
-	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{22}
+// Deprecated: Use NetworkAdoption.ProtoReflect.Descriptor instead.
+func (*NetworkAdoption) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{56}
 }
 
-type NetworkNodesReply struct {
+func (x *NetworkAdoption) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *NetworkAdoption) GetServer() uint32 {
+	if x != nil {
+		return x.Server
+	}
+	return 0
+}
+
+func (x *NetworkAdoption) GetNeighbors() []uint32 {
+	if x != nil {
+		return x.Neighbors
+	}
+	return nil
+}
+
+func (x *NetworkAdoption) GetRssi() []int32 {
+	if x != nil {
+		return x.Rssi
+	}
+	return nil
+}
+
+func (x *NetworkAdoption) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *NetworkAdoption) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *NetworkAdoption) GetRevision() string {
+	if x != nil {
+		return x.Revision
+	}
+	return ""
+}
+
+func (x *NetworkAdoption) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type NetworkAdoptionsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Nodes         []*NetworkNode         `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *NetworkNodesReply) Reset() {
-	*x = NetworkNodesReply{}
-	mi := &file_meshmesh_meshmesh_proto_msgTypes[23]
+func (x *NetworkAdoptionsRequest) Reset() {
+	*x = NetworkAdoptionsRequest{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkAdoptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkAdoptionsRequest) ProtoMessage() {}
+
+func (x *NetworkAdoptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkAdoptionsRequest.ProtoReflect.Descriptor instead.
+func (*NetworkAdoptionsRequest) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{57}
+}
+
+type NetworkAdoptionsReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Adoptions     []*NetworkAdoption     `protobuf:"bytes,1,rep,name=adoptions,proto3" json:"adoptions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkAdoptionsReply) Reset() {
+	*x = NetworkAdoptionsReply{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkAdoptionsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkAdoptionsReply) ProtoMessage() {}
+
+func (x *NetworkAdoptionsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkAdoptionsReply.ProtoReflect.Descriptor instead.
+func (*NetworkAdoptionsReply) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *NetworkAdoptionsReply) GetAdoptions() []*NetworkAdoption {
+	if x != nil {
+		return x.Adoptions
+	}
+	return nil
+}
+
+type NetworkAdoptionControlRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// One of "approve" or "reject"
+	Action        string `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkAdoptionControlRequest) Reset() {
+	*x = NetworkAdoptionControlRequest{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkAdoptionControlRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkAdoptionControlRequest) ProtoMessage() {}
+
+func (x *NetworkAdoptionControlRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkAdoptionControlRequest.ProtoReflect.Descriptor instead.
+func (*NetworkAdoptionControlRequest) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *NetworkAdoptionControlRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *NetworkAdoptionControlRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+type NetworkAdoptionControlReply struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Adoption      *NetworkAdoption       `protobuf:"bytes,1,opt,name=adoption,proto3" json:"adoption,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkAdoptionControlReply) Reset() {
+	*x = NetworkAdoptionControlReply{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkAdoptionControlReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkAdoptionControlReply) ProtoMessage() {}
+
+func (x *NetworkAdoptionControlReply) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkAdoptionControlReply.ProtoReflect.Descriptor instead.
+func (*NetworkAdoptionControlReply) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *NetworkAdoptionControlReply) GetAdoption() *NetworkAdoption {
+	if x != nil {
+		return x.Adoption
+	}
+	return nil
+}
+
+type NetworkNodeConfigureRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Tag           string                 `protobuf:"bytes,2,opt,name=tag,proto3" json:"tag,omitempty"`
+	Inuse         bool                   `protobuf:"varint,3,opt,name=inuse,proto3" json:"inuse,omitempty"`
+	Chip          *string                `protobuf:"bytes,4,opt,name=chip,proto3,oneof" json:"chip,omitempty"`
+	WeightProfile *string                `protobuf:"bytes,5,opt,name=weight_profile,json=weightProfile,proto3,oneof" json:"weight_profile,omitempty"`
+	Location      *string                `protobuf:"bytes,6,opt,name=location,proto3,oneof" json:"location,omitempty"`
+	Notes         *string                `protobuf:"bytes,7,opt,name=notes,proto3,oneof" json:"notes,omitempty"`
+	// Replace the labels when set
+	Labels        *NetworkNodeLabels `protobuf:"bytes,8,opt,name=labels,proto3" json:"labels,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkNodeConfigureRequest) Reset() {
+	*x = NetworkNodeConfigureRequest{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[61]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *NetworkNodesReply) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *NetworkNodeConfigureRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkNodeConfigureRequest) ProtoMessage() {}
+
+func (x *NetworkNodeConfigureRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkNodeConfigureRequest.ProtoReflect.Descriptor instead.
+func (*NetworkNodeConfigureRequest) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *NetworkNodeConfigureRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *NetworkNodeConfigureRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *NetworkNodeConfigureRequest) GetInuse() bool {
+	if x != nil {
+		return x.Inuse
+	}
+	return false
+}
+
+func (x *NetworkNodeConfigureRequest) GetChip() string {
+	if x != nil && x.Chip != nil {
+		return *x.Chip
+	}
+	return ""
+}
+
+func (x *NetworkNodeConfigureRequest) GetWeightProfile() string {
+	if x != nil && x.WeightProfile != nil {
+		return *x.WeightProfile
+	}
+	return ""
 }
 
-func (*NetworkNodesReply) ProtoMessage() {}
-
-func (x *NetworkNodesReply) ProtoReflect() protoreflect.Message {
-	mi := &file_meshmesh_meshmesh_proto_msgTypes[23]
-	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *NetworkNodeConfigureRequest) GetLocation() string {
+	if x != nil && x.Location != nil {
+		return *x.Location
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use NetworkNodesReply.ProtoReflect.Descriptor instead.
-func (*NetworkNodesReply) Descriptor() ([]byte, []int) {
-	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{23}
+func (x *NetworkNodeConfigureRequest) GetNotes() string {
+	if x != nil && x.Notes != nil {
+		return *x.Notes
+	}
+	return ""
 }
 
-func (x *NetworkNodesReply) GetNodes() []*NetworkNode {
+func (x *NetworkNodeConfigureRequest) GetLabels() *NetworkNodeLabels {
 	if x != nil {
-		return x.Nodes
+		return x.Labels
 	}
 	return nil
 }
 
-type NetworkEdgesRequest struct {
+type NetworkNodeConfigureReply struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *NetworkEdgesRequest) Reset() {
-	*x = NetworkEdgesRequest{}
-	mi := &file_meshmesh_meshmesh_proto_msgTypes[24]
+func (x *NetworkNodeConfigureReply) Reset() {
+	*x = NetworkNodeConfigureReply{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[62]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *NetworkEdgesRequest) String() string {
+func (x *NetworkNodeConfigureReply) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NetworkEdgesRequest) ProtoMessage() {}
+func (*NetworkNodeConfigureReply) ProtoMessage() {}
 
-func (x *NetworkEdgesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_meshmesh_meshmesh_proto_msgTypes[24]
+func (x *NetworkNodeConfigureReply) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[62]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1336,33 +3742,40 @@ func (x *NetworkEdgesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NetworkEdgesRequest.ProtoReflect.Descriptor instead.
-func (*NetworkEdgesRequest) Descriptor() ([]byte, []int) {
-	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{24}
+// Deprecated: Use NetworkNodeConfigureReply.ProtoReflect.Descriptor instead.
+func (*NetworkNodeConfigureReply) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{62}
 }
 
-type NetworkEdgesReply struct {
+func (x *NetworkNodeConfigureReply) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type NetworkNodeDeleteRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Edges         []*NetworkEdge         `protobuf:"bytes,1,rep,name=edges,proto3" json:"edges,omitempty"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *NetworkEdgesReply) Reset() {
-	*x = NetworkEdgesReply{}
-	mi := &file_meshmesh_meshmesh_proto_msgTypes[25]
+func (x *NetworkNodeDeleteRequest) Reset() {
+	*x = NetworkNodeDeleteRequest{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[63]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *NetworkEdgesReply) String() string {
+func (x *NetworkNodeDeleteRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NetworkEdgesReply) ProtoMessage() {}
+func (*NetworkNodeDeleteRequest) ProtoMessage() {}
 
-func (x *NetworkEdgesReply) ProtoReflect() protoreflect.Message {
-	mi := &file_meshmesh_meshmesh_proto_msgTypes[25]
+func (x *NetworkNodeDeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[63]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1373,42 +3786,40 @@ func (x *NetworkEdgesReply) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NetworkEdgesReply.ProtoReflect.Descriptor instead.
-func (*NetworkEdgesReply) Descriptor() ([]byte, []int) {
-	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{25}
+// Deprecated: Use NetworkNodeDeleteRequest.ProtoReflect.Descriptor instead.
+func (*NetworkNodeDeleteRequest) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{63}
 }
 
-func (x *NetworkEdgesReply) GetEdges() []*NetworkEdge {
+func (x *NetworkNodeDeleteRequest) GetId() uint32 {
 	if x != nil {
-		return x.Edges
+		return x.Id
 	}
-	return nil
+	return 0
 }
 
-type NetworkNode struct {
+type NetworkNodeDeleteReply struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Tag           string                 `protobuf:"bytes,2,opt,name=tag,proto3" json:"tag,omitempty"`
-	Inuse         bool                   `protobuf:"varint,3,opt,name=inuse,proto3" json:"inuse,omitempty"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *NetworkNode) Reset() {
-	*x = NetworkNode{}
-	mi := &file_meshmesh_meshmesh_proto_msgTypes[26]
+func (x *NetworkNodeDeleteReply) Reset() {
+	*x = NetworkNodeDeleteReply{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[64]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *NetworkNode) String() string {
+func (x *NetworkNodeDeleteReply) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NetworkNode) ProtoMessage() {}
+func (*NetworkNodeDeleteReply) ProtoMessage() {}
 
-func (x *NetworkNode) ProtoReflect() protoreflect.Message {
-	mi := &file_meshmesh_meshmesh_proto_msgTypes[26]
+func (x *NetworkNodeDeleteReply) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[64]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1419,57 +3830,44 @@ func (x *NetworkNode) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NetworkNode.ProtoReflect.Descriptor instead.
-func (*NetworkNode) Descriptor() ([]byte, []int) {
-	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{26}
-}
-
-func (x *NetworkNode) GetId() uint32 {
-	if x != nil {
-		return x.Id
-	}
-	return 0
-}
-
-func (x *NetworkNode) GetTag() string {
-	if x != nil {
-		return x.Tag
-	}
-	return ""
+// Deprecated: Use NetworkNodeDeleteReply.ProtoReflect.Descriptor instead.
+func (*NetworkNodeDeleteReply) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{64}
 }
 
-func (x *NetworkNode) GetInuse() bool {
+func (x *NetworkNodeDeleteReply) GetSuccess() bool {
 	if x != nil {
-		return x.Inuse
+		return x.Success
 	}
 	return false
 }
 
-type NetworkEdge struct {
+type EsphomePortAllocation struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	From          uint32                 `protobuf:"varint,2,opt,name=from,proto3" json:"from,omitempty"`
-	To            uint32                 `protobuf:"varint,3,opt,name=to,proto3" json:"to,omitempty"`
-	Weight        float32                `protobuf:"fixed32,4,opt,name=weight,proto3" json:"weight,omitempty"`
+	BindAddresses []string               `protobuf:"bytes,2,rep,name=bind_addresses,json=bindAddresses,proto3" json:"bind_addresses,omitempty"`
+	ApiPort       uint32                 `protobuf:"varint,3,opt,name=api_port,json=apiPort,proto3" json:"api_port,omitempty"`
+	OtaPort       uint32                 `protobuf:"varint,4,opt,name=ota_port,json=otaPort,proto3" json:"ota_port,omitempty"`
+	Manual        bool                   `protobuf:"varint,5,opt,name=manual,proto3" json:"manual,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *NetworkEdge) Reset() {
-	*x = NetworkEdge{}
-	mi := &file_meshmesh_meshmesh_proto_msgTypes[27]
+func (x *EsphomePortAllocation) Reset() {
+	*x = EsphomePortAllocation{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[65]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *NetworkEdge) String() string {
+func (x *EsphomePortAllocation) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NetworkEdge) ProtoMessage() {}
+func (*EsphomePortAllocation) ProtoMessage() {}
 
-func (x *NetworkEdge) ProtoReflect() protoreflect.Message {
-	mi := &file_meshmesh_meshmesh_proto_msgTypes[27]
+func (x *EsphomePortAllocation) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[65]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1480,63 +3878,67 @@ func (x *NetworkEdge) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NetworkEdge.ProtoReflect.Descriptor instead.
-func (*NetworkEdge) Descriptor() ([]byte, []int) {
-	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{27}
+// Deprecated: Use EsphomePortAllocation.ProtoReflect.Descriptor instead.
+func (*EsphomePortAllocation) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{65}
 }
 
-func (x *NetworkEdge) GetId() uint32 {
+func (x *EsphomePortAllocation) GetId() uint32 {
 	if x != nil {
 		return x.Id
 	}
 	return 0
 }
 
-func (x *NetworkEdge) GetFrom() uint32 {
+func (x *EsphomePortAllocation) GetBindAddresses() []string {
 	if x != nil {
-		return x.From
+		return x.BindAddresses
 	}
-	return 0
+	return nil
 }
 
-func (x *NetworkEdge) GetTo() uint32 {
+func (x *EsphomePortAllocation) GetApiPort() uint32 {
 	if x != nil {
-		return x.To
+		return x.ApiPort
 	}
 	return 0
 }
 
-func (x *NetworkEdge) GetWeight() float32 {
+func (x *EsphomePortAllocation) GetOtaPort() uint32 {
 	if x != nil {
-		return x.Weight
+		return x.OtaPort
 	}
 	return 0
 }
 
-type NetworkNodeConfigureRequest struct {
+func (x *EsphomePortAllocation) GetManual() bool {
+	if x != nil {
+		return x.Manual
+	}
+	return false
+}
+
+type EsphomePortsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Tag           string                 `protobuf:"bytes,2,opt,name=tag,proto3" json:"tag,omitempty"`
-	Inuse         bool                   `protobuf:"varint,3,opt,name=inuse,proto3" json:"inuse,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *NetworkNodeConfigureRequest) Reset() {
-	*x = NetworkNodeConfigureRequest{}
-	mi := &file_meshmesh_meshmesh_proto_msgTypes[28]
+func (x *EsphomePortsRequest) Reset() {
+	*x = EsphomePortsRequest{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[66]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *NetworkNodeConfigureRequest) String() string {
+func (x *EsphomePortsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NetworkNodeConfigureRequest) ProtoMessage() {}
+func (*EsphomePortsRequest) ProtoMessage() {}
 
-func (x *NetworkNodeConfigureRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_meshmesh_meshmesh_proto_msgTypes[28]
+func (x *EsphomePortsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[66]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1547,54 +3949,33 @@ func (x *NetworkNodeConfigureRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NetworkNodeConfigureRequest.ProtoReflect.Descriptor instead.
-func (*NetworkNodeConfigureRequest) Descriptor() ([]byte, []int) {
-	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{28}
-}
-
-func (x *NetworkNodeConfigureRequest) GetId() uint32 {
-	if x != nil {
-		return x.Id
-	}
-	return 0
-}
-
-func (x *NetworkNodeConfigureRequest) GetTag() string {
-	if x != nil {
-		return x.Tag
-	}
-	return ""
-}
-
-func (x *NetworkNodeConfigureRequest) GetInuse() bool {
-	if x != nil {
-		return x.Inuse
-	}
-	return false
+// Deprecated: Use EsphomePortsRequest.ProtoReflect.Descriptor instead.
+func (*EsphomePortsRequest) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{66}
 }
 
-type NetworkNodeConfigureReply struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+type EsphomePortsReply struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Ports         []*EsphomePortAllocation `protobuf:"bytes,1,rep,name=ports,proto3" json:"ports,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *NetworkNodeConfigureReply) Reset() {
-	*x = NetworkNodeConfigureReply{}
-	mi := &file_meshmesh_meshmesh_proto_msgTypes[29]
+func (x *EsphomePortsReply) Reset() {
+	*x = EsphomePortsReply{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[67]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *NetworkNodeConfigureReply) String() string {
+func (x *EsphomePortsReply) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NetworkNodeConfigureReply) ProtoMessage() {}
+func (*EsphomePortsReply) ProtoMessage() {}
 
-func (x *NetworkNodeConfigureReply) ProtoReflect() protoreflect.Message {
-	mi := &file_meshmesh_meshmesh_proto_msgTypes[29]
+func (x *EsphomePortsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[67]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1605,40 +3986,45 @@ func (x *NetworkNodeConfigureReply) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NetworkNodeConfigureReply.ProtoReflect.Descriptor instead.
-func (*NetworkNodeConfigureReply) Descriptor() ([]byte, []int) {
-	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{29}
+// Deprecated: Use EsphomePortsReply.ProtoReflect.Descriptor instead.
+func (*EsphomePortsReply) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{67}
 }
 
-func (x *NetworkNodeConfigureReply) GetSuccess() bool {
+func (x *EsphomePortsReply) GetPorts() []*EsphomePortAllocation {
 	if x != nil {
-		return x.Success
+		return x.Ports
 	}
-	return false
+	return nil
 }
 
-type NetworkNodeDeleteRequest struct {
+type SetEsphomePortsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	BindAddresses []string               `protobuf:"bytes,2,rep,name=bind_addresses,json=bindAddresses,proto3" json:"bind_addresses,omitempty"`
+	ApiPort       uint32                 `protobuf:"varint,3,opt,name=api_port,json=apiPort,proto3" json:"api_port,omitempty"`
+	OtaPort       uint32                 `protobuf:"varint,4,opt,name=ota_port,json=otaPort,proto3" json:"ota_port,omitempty"`
+	// Restore the automatic allocation ignoring the other fields
+	Reset_        bool `protobuf:"varint,5,opt,name=reset,proto3" json:"reset,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *NetworkNodeDeleteRequest) Reset() {
-	*x = NetworkNodeDeleteRequest{}
-	mi := &file_meshmesh_meshmesh_proto_msgTypes[30]
+func (x *SetEsphomePortsRequest) Reset() {
+	*x = SetEsphomePortsRequest{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[68]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *NetworkNodeDeleteRequest) String() string {
+func (x *SetEsphomePortsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NetworkNodeDeleteRequest) ProtoMessage() {}
+func (*SetEsphomePortsRequest) ProtoMessage() {}
 
-func (x *NetworkNodeDeleteRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_meshmesh_meshmesh_proto_msgTypes[30]
+func (x *SetEsphomePortsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[68]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1649,40 +4035,68 @@ func (x *NetworkNodeDeleteRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NetworkNodeDeleteRequest.ProtoReflect.Descriptor instead.
-func (*NetworkNodeDeleteRequest) Descriptor() ([]byte, []int) {
-	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{30}
+// Deprecated: Use SetEsphomePortsRequest.ProtoReflect.Descriptor instead.
+func (*SetEsphomePortsRequest) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{68}
 }
 
-func (x *NetworkNodeDeleteRequest) GetId() uint32 {
+func (x *SetEsphomePortsRequest) GetId() uint32 {
 	if x != nil {
 		return x.Id
 	}
 	return 0
 }
 
-type NetworkNodeDeleteReply struct {
+func (x *SetEsphomePortsRequest) GetBindAddresses() []string {
+	if x != nil {
+		return x.BindAddresses
+	}
+	return nil
+}
+
+func (x *SetEsphomePortsRequest) GetApiPort() uint32 {
+	if x != nil {
+		return x.ApiPort
+	}
+	return 0
+}
+
+func (x *SetEsphomePortsRequest) GetOtaPort() uint32 {
+	if x != nil {
+		return x.OtaPort
+	}
+	return 0
+}
+
+func (x *SetEsphomePortsRequest) GetReset_() bool {
+	if x != nil {
+		return x.Reset_
+	}
+	return false
+}
+
+type SetEsphomePortsReply struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Port          *EsphomePortAllocation `protobuf:"bytes,1,opt,name=port,proto3" json:"port,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *NetworkNodeDeleteReply) Reset() {
-	*x = NetworkNodeDeleteReply{}
-	mi := &file_meshmesh_meshmesh_proto_msgTypes[31]
+func (x *SetEsphomePortsReply) Reset() {
+	*x = SetEsphomePortsReply{}
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[69]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *NetworkNodeDeleteReply) String() string {
+func (x *SetEsphomePortsReply) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NetworkNodeDeleteReply) ProtoMessage() {}
+func (*SetEsphomePortsReply) ProtoMessage() {}
 
-func (x *NetworkNodeDeleteReply) ProtoReflect() protoreflect.Message {
-	mi := &file_meshmesh_meshmesh_proto_msgTypes[31]
+func (x *SetEsphomePortsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_meshmesh_meshmesh_proto_msgTypes[69]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1693,16 +4107,16 @@ func (x *NetworkNodeDeleteReply) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NetworkNodeDeleteReply.ProtoReflect.Descriptor instead.
-func (*NetworkNodeDeleteReply) Descriptor() ([]byte, []int) {
-	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{31}
+// Deprecated: Use SetEsphomePortsReply.ProtoReflect.Descriptor instead.
+func (*SetEsphomePortsReply) Descriptor() ([]byte, []int) {
+	return file_meshmesh_meshmesh_proto_rawDescGZIP(), []int{69}
 }
 
-func (x *NetworkNodeDeleteReply) GetSuccess() bool {
+func (x *SetEsphomePortsReply) GetPort() *EsphomePortAllocation {
 	if x != nil {
-		return x.Success
+		return x.Port
 	}
-	return false
+	return nil
 }
 
 var File_meshmesh_meshmesh_proto protoreflect.FileDescriptor
@@ -1806,99 +4220,421 @@ var file_meshmesh_meshmesh_proto_rawDesc = string([]byte{
 	0x15, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72,
 	0x79, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73,
 	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73,
-	0x22, 0x15, 0x0a, 0x13, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x4e, 0x6f, 0x64, 0x65, 0x73,
+	0x22, 0xe1, 0x03, 0x0a, 0x0f, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x50, 0x61,
+	0x72, 0x61, 0x6d, 0x73, 0x12, 0x19, 0x0a, 0x05, 0x73, 0x6c, 0x6f, 0x74, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x05, 0x73, 0x6c, 0x6f, 0x74, 0x73, 0x88, 0x01, 0x01, 0x12,
+	0x17, 0x0a, 0x04, 0x6d, 0x61, 0x73, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x01, 0x52,
+	0x04, 0x6d, 0x61, 0x73, 0x6b, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x66, 0x69, 0x6c, 0x74,
+	0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x02, 0x52, 0x06, 0x66, 0x69, 0x6c, 0x74,
+	0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x1c, 0x0a, 0x07, 0x77, 0x61, 0x69, 0x74, 0x5f, 0x6d, 0x73,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x03, 0x52, 0x06, 0x77, 0x61, 0x69, 0x74, 0x4d, 0x73,
+	0x88, 0x01, 0x01, 0x12, 0x25, 0x0a, 0x0b, 0x72, 0x65, 0x70, 0x65, 0x74, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x04, 0x52, 0x0b, 0x72, 0x65, 0x70, 0x65,
+	0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x88, 0x01, 0x01, 0x12, 0x25, 0x0a, 0x0b, 0x61, 0x67,
+	0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x05, 0x52, 0x0b, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x88, 0x01,
+	0x01, 0x12, 0x1e, 0x0a, 0x08, 0x72, 0x73, 0x73, 0x69, 0x5f, 0x6d, 0x69, 0x6e, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x05, 0x48, 0x06, 0x52, 0x07, 0x72, 0x73, 0x73, 0x69, 0x4d, 0x69, 0x6e, 0x88, 0x01,
+	0x01, 0x12, 0x1e, 0x0a, 0x08, 0x72, 0x73, 0x73, 0x69, 0x5f, 0x6d, 0x61, 0x78, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x05, 0x48, 0x07, 0x52, 0x07, 0x72, 0x73, 0x73, 0x69, 0x4d, 0x61, 0x78, 0x88, 0x01,
+	0x01, 0x12, 0x1c, 0x0a, 0x07, 0x64, 0x72, 0x79, 0x5f, 0x72, 0x75, 0x6e, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x08, 0x48, 0x08, 0x52, 0x06, 0x64, 0x72, 0x79, 0x52, 0x75, 0x6e, 0x88, 0x01, 0x01, 0x12,
+	0x2e, 0x0a, 0x10, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68,
+	0x6f, 0x6c, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x01, 0x48, 0x09, 0x52, 0x0f, 0x77, 0x65, 0x69,
+	0x67, 0x68, 0x74, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x88, 0x01, 0x01, 0x42,
+	0x08, 0x0a, 0x06, 0x5f, 0x73, 0x6c, 0x6f, 0x74, 0x73, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x6d, 0x61,
+	0x73, 0x6b, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x42, 0x0a, 0x0a,
+	0x08, 0x5f, 0x77, 0x61, 0x69, 0x74, 0x5f, 0x6d, 0x73, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x72, 0x65,
+	0x70, 0x65, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x61, 0x67,
+	0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x72, 0x73,
+	0x73, 0x69, 0x5f, 0x6d, 0x69, 0x6e, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x72, 0x73, 0x73, 0x69, 0x5f,
+	0x6d, 0x61, 0x78, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x64, 0x72, 0x79, 0x5f, 0x72, 0x75, 0x6e, 0x42,
+	0x13, 0x0a, 0x11, 0x5f, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x73,
+	0x68, 0x6f, 0x6c, 0x64, 0x22, 0x64, 0x0a, 0x15, 0x53, 0x74, 0x61, 0x72, 0x74, 0x44, 0x69, 0x73,
+	0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a,
+	0x07, 0x72, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07,
+	0x72, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x12, 0x31, 0x0a, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d,
+	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65,
+	0x73, 0x68, 0x2e, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x50, 0x61, 0x72, 0x61,
+	0x6d, 0x73, 0x52, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x22, 0x2f, 0x0a, 0x13, 0x53, 0x74,
+	0x61, 0x72, 0x74, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x22, 0x75, 0x0a, 0x14, 0x44,
+	0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0d,
+	0x52, 0x03, 0x69, 0x64, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x62, 0x74, 0x72, 0x65, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x62, 0x74, 0x72, 0x65, 0x65, 0x12,
+	0x31, 0x0a, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x19, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x44, 0x69, 0x73, 0x63, 0x6f,
+	0x76, 0x65, 0x72, 0x79, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x52, 0x06, 0x70, 0x61, 0x72, 0x61,
+	0x6d, 0x73, 0x22, 0x48, 0x0a, 0x12, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x4e, 0x6f,
+	0x64, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63,
+	0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65,
+	0x73, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0d, 0x52, 0x07, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x73, 0x22, 0x31, 0x0a, 0x17,
+	0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22,
+	0xc5, 0x01, 0x0a, 0x15, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x43, 0x6f, 0x6e,
+	0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12,
+	0x1d, 0x0a, 0x0a, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x09, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x16,
+	0x0a, 0x06, 0x72, 0x65, 0x70, 0x65, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06,
+	0x72, 0x65, 0x70, 0x65, 0x61, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x6f, 0x6e, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x64, 0x6f, 0x6e, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f,
+	0x74, 0x61, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x12, 0x1f, 0x0a, 0x0b, 0x65, 0x74, 0x61, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x65, 0x74, 0x61, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64,
+	0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x15, 0x0a, 0x13, 0x4e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x40,
+	0x0a, 0x11, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x12, 0x2b, 0x0a, 0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x4e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73,
+	0x22, 0x15, 0x0a, 0x13, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x45, 0x64, 0x67, 0x65, 0x73,
 	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x40, 0x0a, 0x11, 0x4e, 0x65, 0x74, 0x77, 0x6f,
-	0x72, 0x6b, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2b, 0x0a, 0x05,
-	0x6e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6d, 0x65,
-	0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x4e, 0x6f,
-	0x64, 0x65, 0x52, 0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x22, 0x15, 0x0a, 0x13, 0x4e, 0x65, 0x74,
-	0x77, 0x6f, 0x72, 0x6b, 0x45, 0x64, 0x67, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x22, 0x40, 0x0a, 0x11, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x45, 0x64, 0x67, 0x65, 0x73,
-	0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2b, 0x0a, 0x05, 0x65, 0x64, 0x67, 0x65, 0x73, 0x18, 0x01,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e,
-	0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x45, 0x64, 0x67, 0x65, 0x52, 0x05, 0x65, 0x64, 0x67,
-	0x65, 0x73, 0x22, 0x45, 0x0a, 0x0b, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x4e, 0x6f, 0x64,
-	0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x02, 0x69,
-	0x64, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x61, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
-	0x74, 0x61, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x75, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x08, 0x52, 0x05, 0x69, 0x6e, 0x75, 0x73, 0x65, 0x22, 0x59, 0x0a, 0x0b, 0x4e, 0x65, 0x74,
-	0x77, 0x6f, 0x72, 0x6b, 0x45, 0x64, 0x67, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0d, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x0e, 0x0a, 0x02,
-	0x74, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x16, 0x0a, 0x06,
-	0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x02, 0x52, 0x06, 0x77, 0x65,
-	0x69, 0x67, 0x68, 0x74, 0x22, 0x55, 0x0a, 0x1b, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x4e,
-	0x6f, 0x64, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x72, 0x6b, 0x45, 0x64, 0x67, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2b, 0x0a, 0x05,
+	0x65, 0x64, 0x67, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6d, 0x65,
+	0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x45, 0x64,
+	0x67, 0x65, 0x52, 0x05, 0x65, 0x64, 0x67, 0x65, 0x73, 0x22, 0xb8, 0x02, 0x0a, 0x0b, 0x4e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x02, 0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x61, 0x67,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x74, 0x61, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x69,
+	0x6e, 0x75, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x69, 0x6e, 0x75, 0x73,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x68, 0x69, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x63, 0x68, 0x69, 0x70, 0x12, 0x25, 0x0a, 0x0e, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x5f,
+	0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x77,
+	0x65, 0x69, 0x67, 0x68, 0x74, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x1a, 0x0a, 0x08,
+	0x66, 0x69, 0x72, 0x6d, 0x77, 0x61, 0x72, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x66, 0x69, 0x72, 0x6d, 0x77, 0x61, 0x72, 0x65, 0x12, 0x33, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d,
+	0x65, 0x73, 0x68, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x4e, 0x6f, 0x64, 0x65, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x1b, 0x0a,
+	0x09, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x73, 0x65, 0x65, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x08, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x65, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x6f,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x6f,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x74, 0x65, 0x73, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6e, 0x6f, 0x74, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06,
+	0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x61,
+	0x62, 0x65, 0x6c, 0x73, 0x22, 0x9b, 0x01, 0x0a, 0x11, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b,
+	0x4e, 0x6f, 0x64, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x68,
+	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x63, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x19, 0x0a, 0x08, 0x74, 0x78, 0x5f, 0x70, 0x6f, 0x77, 0x65, 0x72,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x74, 0x78, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x12,
+	0x16, 0x0a, 0x06, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x06, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x62, 0x69, 0x6e, 0x64, 0x65,
+	0x64, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c,
+	0x62, 0x69, 0x6e, 0x64, 0x65, 0x64, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05,
+	0x66, 0x6c, 0x61, 0x67, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x66, 0x6c, 0x61,
+	0x67, 0x73, 0x22, 0x2b, 0x0a, 0x11, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x4e, 0x6f, 0x64,
+	0x65, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x22,
+	0xab, 0x01, 0x0a, 0x0b, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x45, 0x64, 0x67, 0x65, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x66,
+	0x72, 0x6f, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x02, 0x74, 0x6f, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x02, 0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x70,
+	0x69, 0x6e, 0x6e, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x70, 0x69, 0x6e,
+	0x6e, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x64, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x64, 0x12,
+	0x1c, 0x0a, 0x09, 0x70, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x09, 0x70, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x22, 0xf0, 0x01,
+	0x0a, 0x1b, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x45, 0x64, 0x67, 0x65, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x75, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x66, 0x72, 0x6f,
+	0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x02, 0x74,
+	0x6f, 0x12, 0x1b, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x02, 0x48, 0x00, 0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x88, 0x01, 0x01, 0x12, 0x1b,
+	0x0a, 0x06, 0x70, 0x69, 0x6e, 0x6e, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x48, 0x01,
+	0x52, 0x06, 0x70, 0x69, 0x6e, 0x6e, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x65,
+	0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x48, 0x02, 0x52,
+	0x08, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x21, 0x0a, 0x09,
+	0x70, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x48,
+	0x03, 0x52, 0x09, 0x70, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64, 0x88, 0x01, 0x01, 0x42,
+	0x09, 0x0a, 0x07, 0x5f, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x70,
+	0x69, 0x6e, 0x6e, 0x65, 0x64, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64,
+	0x65, 0x64, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65, 0x64,
+	0x22, 0x46, 0x0a, 0x19, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x45, 0x64, 0x67, 0x65, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x29, 0x0a,
+	0x04, 0x65, 0x64, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6d, 0x65,
+	0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x45, 0x64,
+	0x67, 0x65, 0x52, 0x04, 0x65, 0x64, 0x67, 0x65, 0x22, 0xda, 0x01, 0x0a, 0x13, 0x4e, 0x65, 0x74,
+	0x77, 0x6f, 0x72, 0x6b, 0x4e, 0x6f, 0x64, 0x65, 0x41, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x02, 0x69, 0x64,
+	0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x70, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04,
+	0x68, 0x6f, 0x70, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x62, 0x65, 0x74, 0x77, 0x65, 0x65, 0x6e, 0x6e,
+	0x65, 0x73, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x62, 0x65, 0x74, 0x77, 0x65,
+	0x65, 0x6e, 0x6e, 0x65, 0x73, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x65,
+	0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x65, 0x64,
+	0x12, 0x22, 0x0a, 0x0c, 0x61, 0x72, 0x74, 0x69, 0x63, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x61, 0x72, 0x74, 0x69, 0x63, 0x75, 0x6c, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x65, 0x70, 0x65, 0x6e, 0x64, 0x65, 0x6e,
+	0x74, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x0a, 0x64, 0x65, 0x70, 0x65, 0x6e, 0x64,
+	0x65, 0x6e, 0x74, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x69, 0x6e, 0x67, 0x6c, 0x65, 0x5f, 0x70,
+	0x61, 0x74, 0x68, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x73, 0x69, 0x6e, 0x67, 0x6c,
+	0x65, 0x50, 0x61, 0x74, 0x68, 0x22, 0x5b, 0x0a, 0x15, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b,
+	0x42, 0x72, 0x69, 0x64, 0x67, 0x65, 0x41, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x12, 0x12,
+	0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x66, 0x72,
+	0x6f, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x02,
+	0x74, 0x6f, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x65, 0x70, 0x65, 0x6e, 0x64, 0x65, 0x6e, 0x74, 0x73,
+	0x18, 0x03, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x0a, 0x64, 0x65, 0x70, 0x65, 0x6e, 0x64, 0x65, 0x6e,
+	0x74, 0x73, 0x22, 0x18, 0x0a, 0x16, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x41, 0x6e, 0x61,
+	0x6c, 0x79, 0x73, 0x69, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xa8, 0x01, 0x0a,
+	0x14, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x41, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x33, 0x0a, 0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e,
+	0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x4e, 0x6f, 0x64, 0x65, 0x41, 0x6e, 0x61, 0x6c, 0x79,
+	0x73, 0x69, 0x73, 0x52, 0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x39, 0x0a, 0x07, 0x62, 0x72,
+	0x69, 0x64, 0x67, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x6d, 0x65,
+	0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x42, 0x72,
+	0x69, 0x64, 0x67, 0x65, 0x41, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x52, 0x07, 0x62, 0x72,
+	0x69, 0x64, 0x67, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x75, 0x6e, 0x72, 0x65, 0x61, 0x63, 0x68,
+	0x61, 0x62, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x0b, 0x75, 0x6e, 0x72, 0x65,
+	0x61, 0x63, 0x68, 0x61, 0x62, 0x6c, 0x65, 0x22, 0x5a, 0x0a, 0x12, 0x4e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x0e, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x02, 0x69, 0x64, 0x12, 0x19, 0x0a,
+	0x08, 0x6f, 0x6c, 0x64, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0d, 0x52,
+	0x07, 0x6f, 0x6c, 0x64, 0x50, 0x61, 0x74, 0x68, 0x12, 0x19, 0x0a, 0x08, 0x6e, 0x65, 0x77, 0x5f,
+	0x70, 0x61, 0x74, 0x68, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x07, 0x6e, 0x65, 0x77, 0x50,
+	0x61, 0x74, 0x68, 0x22, 0x2d, 0x0a, 0x1b, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x46, 0x61,
+	0x69, 0x6c, 0x75, 0x72, 0x65, 0x49, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x02,
+	0x69, 0x64, 0x22, 0x69, 0x0a, 0x19, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x46, 0x61, 0x69,
+	0x6c, 0x75, 0x72, 0x65, 0x49, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12,
+	0x12, 0x0a, 0x04, 0x6c, 0x6f, 0x73, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x04, 0x6c,
+	0x6f, 0x73, 0x74, 0x12, 0x38, 0x0a, 0x08, 0x72, 0x65, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x64, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68,
+	0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x43, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x52, 0x08, 0x72, 0x65, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x64, 0x22, 0x7c, 0x0a,
+	0x0e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x74,
+	0x69, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x64, 0x67,
+	0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x65, 0x64, 0x67, 0x65, 0x73, 0x12,
+	0x1a, 0x0a, 0x08, 0x72, 0x6f, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x08, 0x72, 0x6f, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x22, 0x17, 0x0a, 0x15, 0x4e,
+	0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x22, 0x4b, 0x0a, 0x13, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x48,
+	0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x34, 0x0a, 0x08, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e,
+	0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x73, 0x22, 0xbd, 0x02, 0x0a, 0x0f, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x44, 0x69, 0x66,
+	0x66, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x6f, 0x64,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x66, 0x72, 0x6f,
+	0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x02, 0x74,
+	0x6f, 0x12, 0x1d, 0x0a, 0x0a, 0x6f, 0x6c, 0x64, 0x5f, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x02, 0x52, 0x09, 0x6f, 0x6c, 0x64, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x12, 0x1d, 0x0a, 0x0a, 0x6e, 0x65, 0x77, 0x5f, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x02, 0x52, 0x09, 0x6e, 0x65, 0x77, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12,
+	0x19, 0x0a, 0x08, 0x6f, 0x6c, 0x64, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x07, 0x20, 0x03, 0x28,
+	0x0d, 0x52, 0x07, 0x6f, 0x6c, 0x64, 0x50, 0x61, 0x74, 0x68, 0x12, 0x19, 0x0a, 0x08, 0x6e, 0x65,
+	0x77, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x07, 0x6e, 0x65,
+	0x77, 0x50, 0x61, 0x74, 0x68, 0x12, 0x17, 0x0a, 0x07, 0x6f, 0x6c, 0x64, 0x5f, 0x74, 0x61, 0x67,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6f, 0x6c, 0x64, 0x54, 0x61, 0x67, 0x12, 0x17,
+	0x0a, 0x07, 0x6e, 0x65, 0x77, 0x5f, 0x74, 0x61, 0x67, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x6e, 0x65, 0x77, 0x54, 0x61, 0x67, 0x12, 0x1b, 0x0a, 0x09, 0x6f, 0x6c, 0x64, 0x5f, 0x69,
+	0x6e, 0x75, 0x73, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x6f, 0x6c, 0x64, 0x49,
+	0x6e, 0x75, 0x73, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6e, 0x65, 0x77, 0x5f, 0x69, 0x6e, 0x75, 0x73,
+	0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x6e, 0x65, 0x77, 0x49, 0x6e, 0x75, 0x73,
+	0x65, 0x22, 0x3f, 0x0a, 0x19, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x48, 0x69, 0x73, 0x74,
+	0x6f, 0x72, 0x79, 0x44, 0x69, 0x66, 0x66, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x66, 0x72,
+	0x6f, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x02,
+	0x74, 0x6f, 0x22, 0x4a, 0x0a, 0x17, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x48, 0x69, 0x73,
+	0x74, 0x6f, 0x72, 0x79, 0x44, 0x69, 0x66, 0x66, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2f, 0x0a,
+	0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d,
+	0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x44,
+	0x69, 0x66, 0x66, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x22, 0x28,
+	0x0a, 0x16, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x52, 0x6f, 0x6c, 0x6c, 0x62, 0x61, 0x63,
+	0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x02, 0x69, 0x64, 0x22, 0x30, 0x0a, 0x14, 0x4e, 0x65, 0x74, 0x77,
+	0x6f, 0x72, 0x6b, 0x52, 0x6f, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x70, 0x6c, 0x79,
+	0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x22, 0x88, 0x01, 0x0a, 0x14, 0x4e,
+	0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6d,
+	0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12,
+	0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64,
+	0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x06, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x77,
+	0x65, 0x69, 0x67, 0x68, 0x74, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x77, 0x65,
+	0x69, 0x67, 0x68, 0x74, 0x73, 0x22, 0x63, 0x0a, 0x12, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b,
+	0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x2f, 0x0a, 0x05, 0x69,
+	0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d, 0x65, 0x73,
+	0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x44, 0x69, 0x66,
+	0x66, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x1c, 0x0a, 0x09,
+	0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x09, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x22, 0xc5, 0x01, 0x0a, 0x0f, 0x4e,
+	0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x41, 0x64, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x02, 0x69, 0x64, 0x12, 0x16,
+	0x0a, 0x06, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06,
+	0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x65, 0x69, 0x67, 0x68, 0x62,
+	0x6f, 0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x09, 0x6e, 0x65, 0x69, 0x67, 0x68,
+	0x62, 0x6f, 0x72, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x73, 0x73, 0x69, 0x18, 0x04, 0x20, 0x03,
+	0x28, 0x05, 0x52, 0x04, 0x72, 0x73, 0x73, 0x69, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x10,
+	0x0a, 0x03, 0x74, 0x61, 0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x74, 0x61, 0x67,
+	0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x22, 0x19, 0x0a, 0x17, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x41, 0x64, 0x6f,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x50, 0x0a,
+	0x15, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x41, 0x64, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x37, 0x0a, 0x09, 0x61, 0x64, 0x6f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d, 0x65, 0x73, 0x68,
+	0x6d, 0x65, 0x73, 0x68, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x41, 0x64, 0x6f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x61, 0x64, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22,
+	0x47, 0x0a, 0x1d, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x41, 0x64, 0x6f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x02, 0x69, 0x64,
+	0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x54, 0x0a, 0x1b, 0x4e, 0x65, 0x74, 0x77,
+	0x6f, 0x72, 0x6b, 0x41, 0x64, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72,
+	0x6f, 0x6c, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x35, 0x0a, 0x08, 0x61, 0x64, 0x6f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6d, 0x65, 0x73, 0x68,
+	0x6d, 0x65, 0x73, 0x68, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x41, 0x64, 0x6f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x61, 0x64, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xbe,
+	0x02, 0x0a, 0x1b, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x4e, 0x6f, 0x64, 0x65, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x02, 0x69, 0x64, 0x12, 0x10,
+	0x0a, 0x03, 0x74, 0x61, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x74, 0x61, 0x67,
+	0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x75, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x05, 0x69, 0x6e, 0x75, 0x73, 0x65, 0x12, 0x17, 0x0a, 0x04, 0x63, 0x68, 0x69, 0x70, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x04, 0x63, 0x68, 0x69, 0x70, 0x88, 0x01, 0x01, 0x12,
+	0x2a, 0x0a, 0x0e, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x5f, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c,
+	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x0d, 0x77, 0x65, 0x69, 0x67, 0x68,
+	0x74, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x6c,
+	0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52,
+	0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05,
+	0x6e, 0x6f, 0x74, 0x65, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x05, 0x6e,
+	0x6f, 0x74, 0x65, 0x73, 0x88, 0x01, 0x01, 0x12, 0x33, 0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c,
+	0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65,
+	0x73, 0x68, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x4e, 0x6f, 0x64, 0x65, 0x4c, 0x61,
+	0x62, 0x65, 0x6c, 0x73, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x42, 0x07, 0x0a, 0x05,
+	0x5f, 0x63, 0x68, 0x69, 0x70, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x5f, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x6c, 0x6f, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x6e, 0x6f, 0x74, 0x65, 0x73, 0x22,
+	0x35, 0x0a, 0x19, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x4e, 0x6f, 0x64, 0x65, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x18, 0x0a, 0x07,
+	0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73,
+	0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x22, 0x2a, 0x0a, 0x18, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72,
+	0x6b, 0x4e, 0x6f, 0x64, 0x65, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x02,
+	0x69, 0x64, 0x22, 0x32, 0x0a, 0x16, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x4e, 0x6f, 0x64,
+	0x65, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x18, 0x0a, 0x07,
+	0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73,
+	0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x22, 0x9c, 0x01, 0x0a, 0x15, 0x45, 0x73, 0x70, 0x68, 0x6f,
+	0x6d, 0x65, 0x50, 0x6f, 0x72, 0x74, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x02, 0x69, 0x64,
+	0x12, 0x25, 0x0a, 0x0e, 0x62, 0x69, 0x6e, 0x64, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x62, 0x69, 0x6e, 0x64, 0x41, 0x64,
+	0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x70, 0x69, 0x5f, 0x70,
+	0x6f, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x61, 0x70, 0x69, 0x50, 0x6f,
+	0x72, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x6f, 0x74, 0x61, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x6f, 0x74, 0x61, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x6d, 0x61, 0x6e, 0x75, 0x61, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x6d,
+	0x61, 0x6e, 0x75, 0x61, 0x6c, 0x22, 0x15, 0x0a, 0x13, 0x45, 0x73, 0x70, 0x68, 0x6f, 0x6d, 0x65,
+	0x50, 0x6f, 0x72, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x4a, 0x0a, 0x11,
+	0x45, 0x73, 0x70, 0x68, 0x6f, 0x6d, 0x65, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x12, 0x35, 0x0a, 0x05, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x1f, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x45, 0x73, 0x70, 0x68,
+	0x6f, 0x6d, 0x65, 0x50, 0x6f, 0x72, 0x74, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x05, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x22, 0x9b, 0x01, 0x0a, 0x16, 0x53, 0x65, 0x74,
+	0x45, 0x73, 0x70, 0x68, 0x6f, 0x6d, 0x65, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75,
 	0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52,
-	0x02, 0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x61, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x03, 0x74, 0x61, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x75, 0x73, 0x65, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x69, 0x6e, 0x75, 0x73, 0x65, 0x22, 0x35, 0x0a, 0x19, 0x4e,
-	0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x4e, 0x6f, 0x64, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
-	0x75, 0x72, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63,
-	0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65,
-	0x73, 0x73, 0x22, 0x2a, 0x0a, 0x18, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x4e, 0x6f, 0x64,
-	0x65, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e,
-	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x02, 0x69, 0x64, 0x22, 0x32,
-	0x0a, 0x16, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x4e, 0x6f, 0x64, 0x65, 0x44, 0x65, 0x6c,
-	0x65, 0x74, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63,
-	0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65,
-	0x73, 0x73, 0x2a, 0x5c, 0x0a, 0x0a, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x54, 0x79, 0x70, 0x65,
-	0x12, 0x07, 0x0a, 0x03, 0x41, 0x4c, 0x4c, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x45, 0x4e,
-	0x53, 0x4f, 0x52, 0x10, 0x01, 0x12, 0x11, 0x0a, 0x0d, 0x42, 0x49, 0x4e, 0x41, 0x52, 0x59, 0x5f,
-	0x53, 0x45, 0x4e, 0x53, 0x4f, 0x52, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x57, 0x49, 0x54,
-	0x43, 0x48, 0x10, 0x03, 0x12, 0x09, 0x0a, 0x05, 0x4c, 0x49, 0x47, 0x48, 0x54, 0x10, 0x04, 0x12,
-	0x0f, 0x0a, 0x0b, 0x54, 0x45, 0x58, 0x54, 0x5f, 0x53, 0x45, 0x4e, 0x53, 0x4f, 0x52, 0x10, 0x05,
-	0x32, 0x93, 0x09, 0x0a, 0x08, 0x4d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x12, 0x3a, 0x0a,
-	0x08, 0x53, 0x61, 0x79, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x12, 0x16, 0x2e, 0x6d, 0x65, 0x73, 0x68,
-	0x6d, 0x65, 0x73, 0x68, 0x2e, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x14, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x48, 0x65, 0x6c,
-	0x6c, 0x6f, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x40, 0x0a, 0x08, 0x4e, 0x6f, 0x64,
-	0x65, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x19, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68,
-	0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x17, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x4e, 0x6f, 0x64, 0x65,
-	0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x46, 0x0a, 0x0a, 0x4e,
-	0x6f, 0x64, 0x65, 0x52, 0x65, 0x62, 0x6f, 0x6f, 0x74, 0x12, 0x1b, 0x2e, 0x6d, 0x65, 0x73, 0x68,
-	0x6d, 0x65, 0x73, 0x68, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x62, 0x6f, 0x6f, 0x74, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73,
-	0x68, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x62, 0x6f, 0x6f, 0x74, 0x52, 0x65, 0x70, 0x6c,
-	0x79, 0x22, 0x00, 0x12, 0x43, 0x0a, 0x09, 0x42, 0x69, 0x6e, 0x64, 0x43, 0x6c, 0x65, 0x61, 0x72,
-	0x12, 0x1a, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x42, 0x69, 0x6e, 0x64,
-	0x43, 0x6c, 0x65, 0x61, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x6d,
-	0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x42, 0x69, 0x6e, 0x64, 0x43, 0x6c, 0x65, 0x61,
-	0x72, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x3a, 0x0a, 0x06, 0x53, 0x65, 0x74, 0x54,
-	0x61, 0x67, 0x12, 0x17, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x53, 0x65,
-	0x74, 0x54, 0x61, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x6d, 0x65,
-	0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x53, 0x65, 0x74, 0x54, 0x61, 0x67, 0x52, 0x65, 0x70,
-	0x6c, 0x79, 0x22, 0x00, 0x12, 0x46, 0x0a, 0x0a, 0x53, 0x65, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x6e,
-	0x65, 0x6c, 0x12, 0x1b, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x53, 0x65,
-	0x74, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x19, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x53, 0x65, 0x74, 0x43, 0x68,
-	0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x4f, 0x0a, 0x0d,
-	0x45, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1e, 0x2e,
-	0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65,
-	0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e,
-	0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65,
-	0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x46, 0x0a,
-	0x0a, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x48, 0x61, 0x73, 0x68, 0x12, 0x1b, 0x2e, 0x6d, 0x65,
-	0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x48, 0x61, 0x73,
-	0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d,
-	0x65, 0x73, 0x68, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x48, 0x61, 0x73, 0x68, 0x52, 0x65,
-	0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x52, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x69,
-	0x74, 0x79, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1f, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65,
-	0x73, 0x68, 0x2e, 0x47, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x53, 0x74, 0x61, 0x74,
-	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d,
+	0x02, 0x69, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x62, 0x69, 0x6e, 0x64, 0x5f, 0x61, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x62, 0x69, 0x6e,
+	0x64, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x70,
+	0x69, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x61, 0x70,
+	0x69, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x6f, 0x74, 0x61, 0x5f, 0x70, 0x6f, 0x72,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x6f, 0x74, 0x61, 0x50, 0x6f, 0x72, 0x74,
+	0x12, 0x14, 0x0a, 0x05, 0x72, 0x65, 0x73, 0x65, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x05, 0x72, 0x65, 0x73, 0x65, 0x74, 0x22, 0x4b, 0x0a, 0x14, 0x53, 0x65, 0x74, 0x45, 0x73, 0x70,
+	0x68, 0x6f, 0x6d, 0x65, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x33,
+	0x0a, 0x04, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x6d,
+	0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x45, 0x73, 0x70, 0x68, 0x6f, 0x6d, 0x65, 0x50,
+	0x6f, 0x72, 0x74, 0x41, 0x6c, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x04, 0x70,
+	0x6f, 0x72, 0x74, 0x2a, 0x5c, 0x0a, 0x0a, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x4c, 0x4c, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x45,
+	0x4e, 0x53, 0x4f, 0x52, 0x10, 0x01, 0x12, 0x11, 0x0a, 0x0d, 0x42, 0x49, 0x4e, 0x41, 0x52, 0x59,
+	0x5f, 0x53, 0x45, 0x4e, 0x53, 0x4f, 0x52, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x57, 0x49,
+	0x54, 0x43, 0x48, 0x10, 0x03, 0x12, 0x09, 0x0a, 0x05, 0x4c, 0x49, 0x47, 0x48, 0x54, 0x10, 0x04,
+	0x12, 0x0f, 0x0a, 0x0b, 0x54, 0x45, 0x58, 0x54, 0x5f, 0x53, 0x45, 0x4e, 0x53, 0x4f, 0x52, 0x10,
+	0x05, 0x32, 0xfc, 0x12, 0x0a, 0x08, 0x4d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x12, 0x3a,
+	0x0a, 0x08, 0x53, 0x61, 0x79, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x12, 0x16, 0x2e, 0x6d, 0x65, 0x73,
+	0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x14, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x48, 0x65,
+	0x6c, 0x6c, 0x6f, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x40, 0x0a, 0x08, 0x4e, 0x6f,
+	0x64, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x19, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73,
+	0x68, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x17, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x4e, 0x6f, 0x64,
+	0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x46, 0x0a, 0x0a,
+	0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x62, 0x6f, 0x6f, 0x74, 0x12, 0x1b, 0x2e, 0x6d, 0x65, 0x73,
+	0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x62, 0x6f, 0x6f, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65,
+	0x73, 0x68, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x62, 0x6f, 0x6f, 0x74, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x22, 0x00, 0x12, 0x43, 0x0a, 0x09, 0x42, 0x69, 0x6e, 0x64, 0x43, 0x6c, 0x65, 0x61,
+	0x72, 0x12, 0x1a, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x42, 0x69, 0x6e,
+	0x64, 0x43, 0x6c, 0x65, 0x61, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e,
+	0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x42, 0x69, 0x6e, 0x64, 0x43, 0x6c, 0x65,
+	0x61, 0x72, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x3a, 0x0a, 0x06, 0x53, 0x65, 0x74,
+	0x54, 0x61, 0x67, 0x12, 0x17, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x53,
+	0x65, 0x74, 0x54, 0x61, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x6d,
+	0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x53, 0x65, 0x74, 0x54, 0x61, 0x67, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x46, 0x0a, 0x0a, 0x53, 0x65, 0x74, 0x43, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x12, 0x1b, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x53,
+	0x65, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x19, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x53, 0x65, 0x74, 0x43,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x4f, 0x0a,
+	0x0d, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1e,
+	0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x69,
+	0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c,
+	0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x69,
+	0x65, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x46,
+	0x0a, 0x0a, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x48, 0x61, 0x73, 0x68, 0x12, 0x1b, 0x2e, 0x6d,
+	0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x48, 0x61,
+	0x73, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x6d, 0x65, 0x73, 0x68,
+	0x6d, 0x65, 0x73, 0x68, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x48, 0x61, 0x73, 0x68, 0x52,
+	0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x52, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x45, 0x6e, 0x74,
+	0x69, 0x74, 0x79, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1f, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d,
 	0x65, 0x73, 0x68, 0x2e, 0x47, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x53, 0x74, 0x61,
-	0x74, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x52, 0x0a, 0x0e, 0x53, 0x65, 0x74,
-	0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1f, 0x2e, 0x6d, 0x65,
-	0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x53, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79,
-	0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x6d,
+	0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x6d, 0x65, 0x73, 0x68,
+	0x6d, 0x65, 0x73, 0x68, 0x2e, 0x47, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x52, 0x0a, 0x0e, 0x53, 0x65,
+	0x74, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1f, 0x2e, 0x6d,
 	0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x53, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x69, 0x74,
-	0x79, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x58, 0x0a,
-	0x10, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72,
-	0x79, 0x12, 0x21, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x45, 0x78, 0x65,
-	0x63, 0x75, 0x74, 0x65, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x52, 0x65, 0x71,
+	0x79, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e,
+	0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x53, 0x65, 0x74, 0x45, 0x6e, 0x74, 0x69,
+	0x74, 0x79, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x58,
+	0x0a, 0x10, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65,
+	0x72, 0x79, 0x12, 0x21, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x45, 0x78,
+	0x65, 0x63, 0x75, 0x74, 0x65, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68,
+	0x2e, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72,
+	0x79, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x52, 0x0a, 0x0e, 0x53, 0x74, 0x61, 0x72,
+	0x74, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x12, 0x1f, 0x2e, 0x6d, 0x65, 0x73,
+	0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x44, 0x69, 0x73, 0x63, 0x6f,
+	0x76, 0x65, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x6d, 0x65,
+	0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x44, 0x69, 0x73, 0x63,
+	0x6f, 0x76, 0x65, 0x72, 0x79, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x4f, 0x0a, 0x0d,
+	0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x1e, 0x2e,
+	0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65,
+	0x72, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e,
+	0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65,
+	0x72, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x58, 0x0a,
+	0x10, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f,
+	0x6c, 0x12, 0x21, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x44, 0x69, 0x73,
+	0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x71,
 	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e,
-	0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79,
+	0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c,
 	0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x4c, 0x0a, 0x0c, 0x4e, 0x65, 0x74, 0x77, 0x6f,
 	0x72, 0x6b, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x1d, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65,
 	0x73, 0x68, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x52,
@@ -1921,12 +4657,74 @@ var file_meshmesh_meshmesh_proto_rawDesc = string([]byte{
 	0x6b, 0x4e, 0x6f, 0x64, 0x65, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
 	0x73, 0x74, 0x1a, 0x20, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x4e, 0x65,
 	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x4e, 0x6f, 0x64, 0x65, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52,
-	0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x42, 0x44, 0x0a, 0x13, 0x6c, 0x65, 0x67, 0x75, 0x72, 0x75,
-	0x2e, 0x6e, 0x65, 0x74, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x42, 0x0d, 0x4d,
-	0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x1c,
-	0x6c, 0x65, 0x67, 0x75, 0x72, 0x75, 0x2e, 0x6e, 0x65, 0x74, 0x2f, 0x6d, 0x2f, 0x76, 0x32, 0x2f,
-	0x72, 0x70, 0x63, 0x2f, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x62, 0x06, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x33,
+	0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x64, 0x0a, 0x14, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72,
+	0x6b, 0x45, 0x64, 0x67, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x65, 0x12, 0x25,
+	0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72,
+	0x6b, 0x45, 0x64, 0x67, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68,
+	0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x45, 0x64, 0x67, 0x65, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x75, 0x72, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x55, 0x0a, 0x0f,
+	0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x41, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x12,
+	0x20, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x41, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x1e, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x4e, 0x65, 0x74,
+	0x77, 0x6f, 0x72, 0x6b, 0x41, 0x6e, 0x61, 0x6c, 0x79, 0x73, 0x69, 0x73, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x22, 0x00, 0x12, 0x64, 0x0a, 0x14, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x46, 0x61,
+	0x69, 0x6c, 0x75, 0x72, 0x65, 0x49, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x12, 0x25, 0x2e, 0x6d, 0x65,
+	0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x46, 0x61,
+	0x69, 0x6c, 0x75, 0x72, 0x65, 0x49, 0x6d, 0x70, 0x61, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x23, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x4e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x49, 0x6d, 0x70, 0x61,
+	0x63, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x52, 0x0a, 0x0e, 0x4e, 0x65, 0x74,
+	0x77, 0x6f, 0x72, 0x6b, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x1f, 0x2e, 0x6d, 0x65,
+	0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x48, 0x69,
+	0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x6d,
+	0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x48,
+	0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x5e, 0x0a,
+	0x12, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x44,
+	0x69, 0x66, 0x66, 0x12, 0x23, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x4e,
+	0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x44, 0x69, 0x66,
+	0x66, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d,
+	0x65, 0x73, 0x68, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x48, 0x69, 0x73, 0x74, 0x6f,
+	0x72, 0x79, 0x44, 0x69, 0x66, 0x66, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x55, 0x0a,
+	0x0f, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x52, 0x6f, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b,
+	0x12, 0x20, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x4e, 0x65, 0x74, 0x77,
+	0x6f, 0x72, 0x6b, 0x52, 0x6f, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x4e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x52, 0x6f, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x22, 0x00, 0x12, 0x4f, 0x0a, 0x0d, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x49,
+	0x6d, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x1e, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68,
+	0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68,
+	0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x58, 0x0a, 0x10, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b,
+	0x41, 0x64, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x21, 0x2e, 0x6d, 0x65, 0x73, 0x68,
+	0x6d, 0x65, 0x73, 0x68, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x41, 0x64, 0x6f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x6d,
+	0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x41,
+	0x64, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12,
+	0x6a, 0x0a, 0x16, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x41, 0x64, 0x6f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x12, 0x27, 0x2e, 0x6d, 0x65, 0x73, 0x68,
+	0x6d, 0x65, 0x73, 0x68, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x41, 0x64, 0x6f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x25, 0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x4e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x41, 0x64, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e,
+	0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x4c, 0x0a, 0x0c, 0x45,
+	0x73, 0x70, 0x68, 0x6f, 0x6d, 0x65, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x12, 0x1d, 0x2e, 0x6d, 0x65,
+	0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x45, 0x73, 0x70, 0x68, 0x6f, 0x6d, 0x65, 0x50, 0x6f,
+	0x72, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x6d, 0x65, 0x73,
+	0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x45, 0x73, 0x70, 0x68, 0x6f, 0x6d, 0x65, 0x50, 0x6f, 0x72,
+	0x74, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00, 0x12, 0x55, 0x0a, 0x0f, 0x53, 0x65, 0x74,
+	0x45, 0x73, 0x70, 0x68, 0x6f, 0x6d, 0x65, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x12, 0x20, 0x2e, 0x6d,
+	0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x53, 0x65, 0x74, 0x45, 0x73, 0x70, 0x68, 0x6f,
+	0x6d, 0x65, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e,
+	0x2e, 0x6d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x2e, 0x53, 0x65, 0x74, 0x45, 0x73, 0x70,
+	0x68, 0x6f, 0x6d, 0x65, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x22, 0x00,
+	0x42, 0x44, 0x0a, 0x13, 0x6c, 0x65, 0x67, 0x75, 0x72, 0x75, 0x2e, 0x6e, 0x65, 0x74, 0x2e, 0x6d,
+	0x65, 0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x42, 0x0d, 0x4d, 0x65, 0x73, 0x68, 0x6d, 0x65, 0x73,
+	0x68, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x1c, 0x6c, 0x65, 0x67, 0x75, 0x72, 0x75,
+	0x2e, 0x6e, 0x65, 0x74, 0x2f, 0x6d, 0x2f, 0x76, 0x32, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x6d, 0x65,
+	0x73, 0x68, 0x6d, 0x65, 0x73, 0x68, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 })
 
 var (
@@ -1942,83 +4740,164 @@ func file_meshmesh_meshmesh_proto_rawDescGZIP() []byte {
 }
 
 var file_meshmesh_meshmesh_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_meshmesh_meshmesh_proto_msgTypes = make([]protoimpl.MessageInfo, 32)
+var file_meshmesh_meshmesh_proto_msgTypes = make([]protoimpl.MessageInfo, 70)
 var file_meshmesh_meshmesh_proto_goTypes = []any{
-	(EntityType)(0),                     // 0: meshmesh.EntityType
-	(*HelloRequest)(nil),                // 1: meshmesh.HelloRequest
-	(*HelloReply)(nil),                  // 2: meshmesh.HelloReply
-	(*NodeInfoRequest)(nil),             // 3: meshmesh.NodeInfoRequest
-	(*NodeInfoReply)(nil),               // 4: meshmesh.NodeInfoReply
-	(*NodeRebootRequest)(nil),           // 5: meshmesh.NodeRebootRequest
-	(*NodeRebootReply)(nil),             // 6: meshmesh.NodeRebootReply
-	(*BindClearRequest)(nil),            // 7: meshmesh.BindClearRequest
-	(*BindClearReply)(nil),              // 8: meshmesh.BindClearReply
-	(*SetTagRequest)(nil),               // 9: meshmesh.SetTagRequest
-	(*SetTagReply)(nil),                 // 10: meshmesh.SetTagReply
-	(*SetChannelRequest)(nil),           // 11: meshmesh.SetChannelRequest
-	(*SetChannelReply)(nil),             // 12: meshmesh.SetChannelReply
-	(*EntitiesCountRequest)(nil),        // 13: meshmesh.EntitiesCountRequest
-	(*EntitiesCountReply)(nil),          // 14: meshmesh.EntitiesCountReply
-	(*EntityHashRequest)(nil),           // 15: meshmesh.EntityHashRequest
-	(*EntityHashReply)(nil),             // 16: meshmesh.EntityHashReply
-	(*GetEntityStateRequest)(nil),       // 17: meshmesh.GetEntityStateRequest
-	(*GetEntityStateReply)(nil),         // 18: meshmesh.GetEntityStateReply
-	(*SetEntityStateRequest)(nil),       // 19: meshmesh.SetEntityStateRequest
-	(*SetEntityStateReply)(nil),         // 20: meshmesh.SetEntityStateReply
-	(*ExecuteDiscoveryRequest)(nil),     // 21: meshmesh.ExecuteDiscoveryRequest
-	(*ExecuteDiscoveryReply)(nil),       // 22: meshmesh.ExecuteDiscoveryReply
-	(*NetworkNodesRequest)(nil),         // 23: meshmesh.NetworkNodesRequest
-	(*NetworkNodesReply)(nil),           // 24: meshmesh.NetworkNodesReply
-	(*NetworkEdgesRequest)(nil),         // 25: meshmesh.NetworkEdgesRequest
-	(*NetworkEdgesReply)(nil),           // 26: meshmesh.NetworkEdgesReply
-	(*NetworkNode)(nil),                 // 27: meshmesh.NetworkNode
-	(*NetworkEdge)(nil),                 // 28: meshmesh.NetworkEdge
-	(*NetworkNodeConfigureRequest)(nil), // 29: meshmesh.NetworkNodeConfigureRequest
-	(*NetworkNodeConfigureReply)(nil),   // 30: meshmesh.NetworkNodeConfigureReply
-	(*NetworkNodeDeleteRequest)(nil),    // 31: meshmesh.NetworkNodeDeleteRequest
-	(*NetworkNodeDeleteReply)(nil),      // 32: meshmesh.NetworkNodeDeleteReply
+	(EntityType)(0),                       // 0: meshmesh.EntityType
+	(*HelloRequest)(nil),                  // 1: meshmesh.HelloRequest
+	(*HelloReply)(nil),                    // 2: meshmesh.HelloReply
+	(*NodeInfoRequest)(nil),               // 3: meshmesh.NodeInfoRequest
+	(*NodeInfoReply)(nil),                 // 4: meshmesh.NodeInfoReply
+	(*NodeRebootRequest)(nil),             // 5: meshmesh.NodeRebootRequest
+	(*NodeRebootReply)(nil),               // 6: meshmesh.NodeRebootReply
+	(*BindClearRequest)(nil),              // 7: meshmesh.BindClearRequest
+	(*BindClearReply)(nil),                // 8: meshmesh.BindClearReply
+	(*SetTagRequest)(nil),                 // 9: meshmesh.SetTagRequest
+	(*SetTagReply)(nil),                   // 10: meshmesh.SetTagReply
+	(*SetChannelRequest)(nil),             // 11: meshmesh.SetChannelRequest
+	(*SetChannelReply)(nil),               // 12: meshmesh.SetChannelReply
+	(*EntitiesCountRequest)(nil),          // 13: meshmesh.EntitiesCountRequest
+	(*EntitiesCountReply)(nil),            // 14: meshmesh.EntitiesCountReply
+	(*EntityHashRequest)(nil),             // 15: meshmesh.EntityHashRequest
+	(*EntityHashReply)(nil),               // 16: meshmesh.EntityHashReply
+	(*GetEntityStateRequest)(nil),         // 17: meshmesh.GetEntityStateRequest
+	(*GetEntityStateReply)(nil),           // 18: meshmesh.GetEntityStateReply
+	(*SetEntityStateRequest)(nil),         // 19: meshmesh.SetEntityStateRequest
+	(*SetEntityStateReply)(nil),           // 20: meshmesh.SetEntityStateReply
+	(*ExecuteDiscoveryRequest)(nil),       // 21: meshmesh.ExecuteDiscoveryRequest
+	(*ExecuteDiscoveryReply)(nil),         // 22: meshmesh.ExecuteDiscoveryReply
+	(*DiscoveryParams)(nil),               // 23: meshmesh.DiscoveryParams
+	(*StartDiscoveryRequest)(nil),         // 24: meshmesh.StartDiscoveryRequest
+	(*StartDiscoveryReply)(nil),           // 25: meshmesh.StartDiscoveryReply
+	(*DiscoverNodesRequest)(nil),          // 26: meshmesh.DiscoverNodesRequest
+	(*DiscoverNodesReply)(nil),            // 27: meshmesh.DiscoverNodesReply
+	(*DiscoveryControlRequest)(nil),       // 28: meshmesh.DiscoveryControlRequest
+	(*DiscoveryControlReply)(nil),         // 29: meshmesh.DiscoveryControlReply
+	(*NetworkNodesRequest)(nil),           // 30: meshmesh.NetworkNodesRequest
+	(*NetworkNodesReply)(nil),             // 31: meshmesh.NetworkNodesReply
+	(*NetworkEdgesRequest)(nil),           // 32: meshmesh.NetworkEdgesRequest
+	(*NetworkEdgesReply)(nil),             // 33: meshmesh.NetworkEdgesReply
+	(*NetworkNode)(nil),                   // 34: meshmesh.NetworkNode
+	(*NetworkNodeConfig)(nil),             // 35: meshmesh.NetworkNodeConfig
+	(*NetworkNodeLabels)(nil),             // 36: meshmesh.NetworkNodeLabels
+	(*NetworkEdge)(nil),                   // 37: meshmesh.NetworkEdge
+	(*NetworkEdgeConfigureRequest)(nil),   // 38: meshmesh.NetworkEdgeConfigureRequest
+	(*NetworkEdgeConfigureReply)(nil),     // 39: meshmesh.NetworkEdgeConfigureReply
+	(*NetworkNodeAnalysis)(nil),           // 40: meshmesh.NetworkNodeAnalysis
+	(*NetworkBridgeAnalysis)(nil),         // 41: meshmesh.NetworkBridgeAnalysis
+	(*NetworkAnalysisRequest)(nil),        // 42: meshmesh.NetworkAnalysisRequest
+	(*NetworkAnalysisReply)(nil),          // 43: meshmesh.NetworkAnalysisReply
+	(*NetworkRouteChange)(nil),            // 44: meshmesh.NetworkRouteChange
+	(*NetworkFailureImpactRequest)(nil),   // 45: meshmesh.NetworkFailureImpactRequest
+	(*NetworkFailureImpactReply)(nil),     // 46: meshmesh.NetworkFailureImpactReply
+	(*NetworkVersion)(nil),                // 47: meshmesh.NetworkVersion
+	(*NetworkHistoryRequest)(nil),         // 48: meshmesh.NetworkHistoryRequest
+	(*NetworkHistoryReply)(nil),           // 49: meshmesh.NetworkHistoryReply
+	(*NetworkDiffItem)(nil),               // 50: meshmesh.NetworkDiffItem
+	(*NetworkHistoryDiffRequest)(nil),     // 51: meshmesh.NetworkHistoryDiffRequest
+	(*NetworkHistoryDiffReply)(nil),       // 52: meshmesh.NetworkHistoryDiffReply
+	(*NetworkRollbackRequest)(nil),        // 53: meshmesh.NetworkRollbackRequest
+	(*NetworkRollbackReply)(nil),          // 54: meshmesh.NetworkRollbackReply
+	(*NetworkImportRequest)(nil),          // 55: meshmesh.NetworkImportRequest
+	(*NetworkImportReply)(nil),            // 56: meshmesh.NetworkImportReply
+	(*NetworkAdoption)(nil),               // 57: meshmesh.NetworkAdoption
+	(*NetworkAdoptionsRequest)(nil),       // 58: meshmesh.NetworkAdoptionsRequest
+	(*NetworkAdoptionsReply)(nil),         // 59: meshmesh.NetworkAdoptionsReply
+	(*NetworkAdoptionControlRequest)(nil), // 60: meshmesh.NetworkAdoptionControlRequest
+	(*NetworkAdoptionControlReply)(nil),   // 61: meshmesh.NetworkAdoptionControlReply
+	(*NetworkNodeConfigureRequest)(nil),   // 62: meshmesh.NetworkNodeConfigureRequest
+	(*NetworkNodeConfigureReply)(nil),     // 63: meshmesh.NetworkNodeConfigureReply
+	(*NetworkNodeDeleteRequest)(nil),      // 64: meshmesh.NetworkNodeDeleteRequest
+	(*NetworkNodeDeleteReply)(nil),        // 65: meshmesh.NetworkNodeDeleteReply
+	(*EsphomePortAllocation)(nil),         // 66: meshmesh.EsphomePortAllocation
+	(*EsphomePortsRequest)(nil),           // 67: meshmesh.EsphomePortsRequest
+	(*EsphomePortsReply)(nil),             // 68: meshmesh.EsphomePortsReply
+	(*SetEsphomePortsRequest)(nil),        // 69: meshmesh.SetEsphomePortsRequest
+	(*SetEsphomePortsReply)(nil),          // 70: meshmesh.SetEsphomePortsReply
 }
 var file_meshmesh_meshmesh_proto_depIdxs = []int32{
 	0,  // 0: meshmesh.EntityHashRequest.service:type_name -> meshmesh.EntityType
 	0,  // 1: meshmesh.GetEntityStateRequest.service:type_name -> meshmesh.EntityType
 	0,  // 2: meshmesh.SetEntityStateRequest.service:type_name -> meshmesh.EntityType
-	27, // 3: meshmesh.NetworkNodesReply.nodes:type_name -> meshmesh.NetworkNode
-	28, // 4: meshmesh.NetworkEdgesReply.edges:type_name -> meshmesh.NetworkEdge
-	1,  // 5: meshmesh.Meshmesh.SayHello:input_type -> meshmesh.HelloRequest
-	3,  // 6: meshmesh.Meshmesh.NodeInfo:input_type -> meshmesh.NodeInfoRequest
-	5,  // 7: meshmesh.Meshmesh.NodeReboot:input_type -> meshmesh.NodeRebootRequest
-	7,  // 8: meshmesh.Meshmesh.BindClear:input_type -> meshmesh.BindClearRequest
-	9,  // 9: meshmesh.Meshmesh.SetTag:input_type -> meshmesh.SetTagRequest
-	11, // 10: meshmesh.Meshmesh.SetChannel:input_type -> meshmesh.SetChannelRequest
-	13, // 11: meshmesh.Meshmesh.EntitiesCount:input_type -> meshmesh.EntitiesCountRequest
-	15, // 12: meshmesh.Meshmesh.EntityHash:input_type -> meshmesh.EntityHashRequest
-	17, // 13: meshmesh.Meshmesh.GetEntityState:input_type -> meshmesh.GetEntityStateRequest
-	19, // 14: meshmesh.Meshmesh.SetEntityState:input_type -> meshmesh.SetEntityStateRequest
-	21, // 15: meshmesh.Meshmesh.ExecuteDiscovery:input_type -> meshmesh.ExecuteDiscoveryRequest
-	23, // 16: meshmesh.Meshmesh.NetworkNodes:input_type -> meshmesh.NetworkNodesRequest
-	25, // 17: meshmesh.Meshmesh.NetworkEdges:input_type -> meshmesh.NetworkEdgesRequest
-	29, // 18: meshmesh.Meshmesh.NetworkNodeConfigure:input_type -> meshmesh.NetworkNodeConfigureRequest
-	31, // 19: meshmesh.Meshmesh.NetworkNodeDelete:input_type -> meshmesh.NetworkNodeDeleteRequest
-	2,  // 20: meshmesh.Meshmesh.SayHello:output_type -> meshmesh.HelloReply
-	4,  // 21: meshmesh.Meshmesh.NodeInfo:output_type -> meshmesh.NodeInfoReply
-	6,  // 22: meshmesh.Meshmesh.NodeReboot:output_type -> meshmesh.NodeRebootReply
-	8,  // 23: meshmesh.Meshmesh.BindClear:output_type -> meshmesh.BindClearReply
-	10, // 24: meshmesh.Meshmesh.SetTag:output_type -> meshmesh.SetTagReply
-	12, // 25: meshmesh.Meshmesh.SetChannel:output_type -> meshmesh.SetChannelReply
-	14, // 26: meshmesh.Meshmesh.EntitiesCount:output_type -> meshmesh.EntitiesCountReply
-	16, // 27: meshmesh.Meshmesh.EntityHash:output_type -> meshmesh.EntityHashReply
-	18, // 28: meshmesh.Meshmesh.GetEntityState:output_type -> meshmesh.GetEntityStateReply
-	20, // 29: meshmesh.Meshmesh.SetEntityState:output_type -> meshmesh.SetEntityStateReply
-	22, // 30: meshmesh.Meshmesh.ExecuteDiscovery:output_type -> meshmesh.ExecuteDiscoveryReply
-	24, // 31: meshmesh.Meshmesh.NetworkNodes:output_type -> meshmesh.NetworkNodesReply
-	26, // 32: meshmesh.Meshmesh.NetworkEdges:output_type -> meshmesh.NetworkEdgesReply
-	30, // 33: meshmesh.Meshmesh.NetworkNodeConfigure:output_type -> meshmesh.NetworkNodeConfigureReply
-	32, // 34: meshmesh.Meshmesh.NetworkNodeDelete:output_type -> meshmesh.NetworkNodeDeleteReply
-	20, // [20:35] is the sub-list for method output_type
-	5,  // [5:20] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+	23, // 3: meshmesh.StartDiscoveryRequest.params:type_name -> meshmesh.DiscoveryParams
+	23, // 4: meshmesh.DiscoverNodesRequest.params:type_name -> meshmesh.DiscoveryParams
+	34, // 5: meshmesh.NetworkNodesReply.nodes:type_name -> meshmesh.NetworkNode
+	37, // 6: meshmesh.NetworkEdgesReply.edges:type_name -> meshmesh.NetworkEdge
+	35, // 7: meshmesh.NetworkNode.config:type_name -> meshmesh.NetworkNodeConfig
+	37, // 8: meshmesh.NetworkEdgeConfigureReply.edge:type_name -> meshmesh.NetworkEdge
+	40, // 9: meshmesh.NetworkAnalysisReply.nodes:type_name -> meshmesh.NetworkNodeAnalysis
+	41, // 10: meshmesh.NetworkAnalysisReply.bridges:type_name -> meshmesh.NetworkBridgeAnalysis
+	44, // 11: meshmesh.NetworkFailureImpactReply.rerouted:type_name -> meshmesh.NetworkRouteChange
+	47, // 12: meshmesh.NetworkHistoryReply.versions:type_name -> meshmesh.NetworkVersion
+	50, // 13: meshmesh.NetworkHistoryDiffReply.items:type_name -> meshmesh.NetworkDiffItem
+	50, // 14: meshmesh.NetworkImportReply.items:type_name -> meshmesh.NetworkDiffItem
+	57, // 15: meshmesh.NetworkAdoptionsReply.adoptions:type_name -> meshmesh.NetworkAdoption
+	57, // 16: meshmesh.NetworkAdoptionControlReply.adoption:type_name -> meshmesh.NetworkAdoption
+	36, // 17: meshmesh.NetworkNodeConfigureRequest.labels:type_name -> meshmesh.NetworkNodeLabels
+	66, // 18: meshmesh.EsphomePortsReply.ports:type_name -> meshmesh.EsphomePortAllocation
+	66, // 19: meshmesh.SetEsphomePortsReply.port:type_name -> meshmesh.EsphomePortAllocation
+	1,  // 20: meshmesh.Meshmesh.SayHello:input_type -> meshmesh.HelloRequest
+	3,  // 21: meshmesh.Meshmesh.NodeInfo:input_type -> meshmesh.NodeInfoRequest
+	5,  // 22: meshmesh.Meshmesh.NodeReboot:input_type -> meshmesh.NodeRebootRequest
+	7,  // 23: meshmesh.Meshmesh.BindClear:input_type -> meshmesh.BindClearRequest
+	9,  // 24: meshmesh.Meshmesh.SetTag:input_type -> meshmesh.SetTagRequest
+	11, // 25: meshmesh.Meshmesh.SetChannel:input_type -> meshmesh.SetChannelRequest
+	13, // 26: meshmesh.Meshmesh.EntitiesCount:input_type -> meshmesh.EntitiesCountRequest
+	15, // 27: meshmesh.Meshmesh.EntityHash:input_type -> meshmesh.EntityHashRequest
+	17, // 28: meshmesh.Meshmesh.GetEntityState:input_type -> meshmesh.GetEntityStateRequest
+	19, // 29: meshmesh.Meshmesh.SetEntityState:input_type -> meshmesh.SetEntityStateRequest
+	21, // 30: meshmesh.Meshmesh.ExecuteDiscovery:input_type -> meshmesh.ExecuteDiscoveryRequest
+	24, // 31: meshmesh.Meshmesh.StartDiscovery:input_type -> meshmesh.StartDiscoveryRequest
+	26, // 32: meshmesh.Meshmesh.DiscoverNodes:input_type -> meshmesh.DiscoverNodesRequest
+	28, // 33: meshmesh.Meshmesh.DiscoveryControl:input_type -> meshmesh.DiscoveryControlRequest
+	30, // 34: meshmesh.Meshmesh.NetworkNodes:input_type -> meshmesh.NetworkNodesRequest
+	32, // 35: meshmesh.Meshmesh.NetworkEdges:input_type -> meshmesh.NetworkEdgesRequest
+	62, // 36: meshmesh.Meshmesh.NetworkNodeConfigure:input_type -> meshmesh.NetworkNodeConfigureRequest
+	64, // 37: meshmesh.Meshmesh.NetworkNodeDelete:input_type -> meshmesh.NetworkNodeDeleteRequest
+	38, // 38: meshmesh.Meshmesh.NetworkEdgeConfigure:input_type -> meshmesh.NetworkEdgeConfigureRequest
+	42, // 39: meshmesh.Meshmesh.NetworkAnalysis:input_type -> meshmesh.NetworkAnalysisRequest
+	45, // 40: meshmesh.Meshmesh.NetworkFailureImpact:input_type -> meshmesh.NetworkFailureImpactRequest
+	48, // 41: meshmesh.Meshmesh.NetworkHistory:input_type -> meshmesh.NetworkHistoryRequest
+	51, // 42: meshmesh.Meshmesh.NetworkHistoryDiff:input_type -> meshmesh.NetworkHistoryDiffRequest
+	53, // 43: meshmesh.Meshmesh.NetworkRollback:input_type -> meshmesh.NetworkRollbackRequest
+	55, // 44: meshmesh.Meshmesh.NetworkImport:input_type -> meshmesh.NetworkImportRequest
+	58, // 45: meshmesh.Meshmesh.NetworkAdoptions:input_type -> meshmesh.NetworkAdoptionsRequest
+	60, // 46: meshmesh.Meshmesh.NetworkAdoptionControl:input_type -> meshmesh.NetworkAdoptionControlRequest
+	67, // 47: meshmesh.Meshmesh.EsphomePorts:input_type -> meshmesh.EsphomePortsRequest
+	69, // 48: meshmesh.Meshmesh.SetEsphomePorts:input_type -> meshmesh.SetEsphomePortsRequest
+	2,  // 49: meshmesh.Meshmesh.SayHello:output_type -> meshmesh.HelloReply
+	4,  // 50: meshmesh.Meshmesh.NodeInfo:output_type -> meshmesh.NodeInfoReply
+	6,  // 51: meshmesh.Meshmesh.NodeReboot:output_type -> meshmesh.NodeRebootReply
+	8,  // 52: meshmesh.Meshmesh.BindClear:output_type -> meshmesh.BindClearReply
+	10, // 53: meshmesh.Meshmesh.SetTag:output_type -> meshmesh.SetTagReply
+	12, // 54: meshmesh.Meshmesh.SetChannel:output_type -> meshmesh.SetChannelReply
+	14, // 55: meshmesh.Meshmesh.EntitiesCount:output_type -> meshmesh.EntitiesCountReply
+	16, // 56: meshmesh.Meshmesh.EntityHash:output_type -> meshmesh.EntityHashReply
+	18, // 57: meshmesh.Meshmesh.GetEntityState:output_type -> meshmesh.GetEntityStateReply
+	20, // 58: meshmesh.Meshmesh.SetEntityState:output_type -> meshmesh.SetEntityStateReply
+	22, // 59: meshmesh.Meshmesh.ExecuteDiscovery:output_type -> meshmesh.ExecuteDiscoveryReply
+	25, // 60: meshmesh.Meshmesh.StartDiscovery:output_type -> meshmesh.StartDiscoveryReply
+	27, // 61: meshmesh.Meshmesh.DiscoverNodes:output_type -> meshmesh.DiscoverNodesReply
+	29, // 62: meshmesh.Meshmesh.DiscoveryControl:output_type -> meshmesh.DiscoveryControlReply
+	31, // 63: meshmesh.Meshmesh.NetworkNodes:output_type -> meshmesh.NetworkNodesReply
+	33, // 64: meshmesh.Meshmesh.NetworkEdges:output_type -> meshmesh.NetworkEdgesReply
+	63, // 65: meshmesh.Meshmesh.NetworkNodeConfigure:output_type -> meshmesh.NetworkNodeConfigureReply
+	65, // 66: meshmesh.Meshmesh.NetworkNodeDelete:output_type -> meshmesh.NetworkNodeDeleteReply
+	39, // 67: meshmesh.Meshmesh.NetworkEdgeConfigure:output_type -> meshmesh.NetworkEdgeConfigureReply
+	43, // 68: meshmesh.Meshmesh.NetworkAnalysis:output_type -> meshmesh.NetworkAnalysisReply
+	46, // 69: meshmesh.Meshmesh.NetworkFailureImpact:output_type -> meshmesh.NetworkFailureImpactReply
+	49, // 70: meshmesh.Meshmesh.NetworkHistory:output_type -> meshmesh.NetworkHistoryReply
+	52, // 71: meshmesh.Meshmesh.NetworkHistoryDiff:output_type -> meshmesh.NetworkHistoryDiffReply
+	54, // 72: meshmesh.Meshmesh.NetworkRollback:output_type -> meshmesh.NetworkRollbackReply
+	56, // 73: meshmesh.Meshmesh.NetworkImport:output_type -> meshmesh.NetworkImportReply
+	59, // 74: meshmesh.Meshmesh.NetworkAdoptions:output_type -> meshmesh.NetworkAdoptionsReply
+	61, // 75: meshmesh.Meshmesh.NetworkAdoptionControl:output_type -> meshmesh.NetworkAdoptionControlReply
+	68, // 76: meshmesh.Meshmesh.EsphomePorts:output_type -> meshmesh.EsphomePortsReply
+	70, // 77: meshmesh.Meshmesh.SetEsphomePorts:output_type -> meshmesh.SetEsphomePortsReply
+	49, // [49:78] is the sub-list for method output_type
+	20, // [20:49] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
 }
 
 func init() { file_meshmesh_meshmesh_proto_init() }
@@ -2027,13 +4906,16 @@ func file_meshmesh_meshmesh_proto_init() {
 		return
 	}
 	file_meshmesh_meshmesh_proto_msgTypes[20].OneofWrappers = []any{}
+	file_meshmesh_meshmesh_proto_msgTypes[22].OneofWrappers = []any{}
+	file_meshmesh_meshmesh_proto_msgTypes[37].OneofWrappers = []any{}
+	file_meshmesh_meshmesh_proto_msgTypes[61].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_meshmesh_meshmesh_proto_rawDesc), len(file_meshmesh_meshmesh_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   32,
+			NumMessages:   70,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
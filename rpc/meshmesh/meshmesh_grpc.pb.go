@@ -19,21 +19,35 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Meshmesh_SayHello_FullMethodName             = "/meshmesh.Meshmesh/SayHello"
-	Meshmesh_NodeInfo_FullMethodName             = "/meshmesh.Meshmesh/NodeInfo"
-	Meshmesh_NodeReboot_FullMethodName           = "/meshmesh.Meshmesh/NodeReboot"
-	Meshmesh_BindClear_FullMethodName            = "/meshmesh.Meshmesh/BindClear"
-	Meshmesh_SetTag_FullMethodName               = "/meshmesh.Meshmesh/SetTag"
-	Meshmesh_SetChannel_FullMethodName           = "/meshmesh.Meshmesh/SetChannel"
-	Meshmesh_EntitiesCount_FullMethodName        = "/meshmesh.Meshmesh/EntitiesCount"
-	Meshmesh_EntityHash_FullMethodName           = "/meshmesh.Meshmesh/EntityHash"
-	Meshmesh_GetEntityState_FullMethodName       = "/meshmesh.Meshmesh/GetEntityState"
-	Meshmesh_SetEntityState_FullMethodName       = "/meshmesh.Meshmesh/SetEntityState"
-	Meshmesh_ExecuteDiscovery_FullMethodName     = "/meshmesh.Meshmesh/ExecuteDiscovery"
-	Meshmesh_NetworkNodes_FullMethodName         = "/meshmesh.Meshmesh/NetworkNodes"
-	Meshmesh_NetworkEdges_FullMethodName         = "/meshmesh.Meshmesh/NetworkEdges"
-	Meshmesh_NetworkNodeConfigure_FullMethodName = "/meshmesh.Meshmesh/NetworkNodeConfigure"
-	Meshmesh_NetworkNodeDelete_FullMethodName    = "/meshmesh.Meshmesh/NetworkNodeDelete"
+	Meshmesh_SayHello_FullMethodName               = "/meshmesh.Meshmesh/SayHello"
+	Meshmesh_NodeInfo_FullMethodName               = "/meshmesh.Meshmesh/NodeInfo"
+	Meshmesh_NodeReboot_FullMethodName             = "/meshmesh.Meshmesh/NodeReboot"
+	Meshmesh_BindClear_FullMethodName              = "/meshmesh.Meshmesh/BindClear"
+	Meshmesh_SetTag_FullMethodName                 = "/meshmesh.Meshmesh/SetTag"
+	Meshmesh_SetChannel_FullMethodName             = "/meshmesh.Meshmesh/SetChannel"
+	Meshmesh_EntitiesCount_FullMethodName          = "/meshmesh.Meshmesh/EntitiesCount"
+	Meshmesh_EntityHash_FullMethodName             = "/meshmesh.Meshmesh/EntityHash"
+	Meshmesh_GetEntityState_FullMethodName         = "/meshmesh.Meshmesh/GetEntityState"
+	Meshmesh_SetEntityState_FullMethodName         = "/meshmesh.Meshmesh/SetEntityState"
+	Meshmesh_ExecuteDiscovery_FullMethodName       = "/meshmesh.Meshmesh/ExecuteDiscovery"
+	Meshmesh_StartDiscovery_FullMethodName         = "/meshmesh.Meshmesh/StartDiscovery"
+	Meshmesh_DiscoverNodes_FullMethodName          = "/meshmesh.Meshmesh/DiscoverNodes"
+	Meshmesh_DiscoveryControl_FullMethodName       = "/meshmesh.Meshmesh/DiscoveryControl"
+	Meshmesh_NetworkNodes_FullMethodName           = "/meshmesh.Meshmesh/NetworkNodes"
+	Meshmesh_NetworkEdges_FullMethodName           = "/meshmesh.Meshmesh/NetworkEdges"
+	Meshmesh_NetworkNodeConfigure_FullMethodName   = "/meshmesh.Meshmesh/NetworkNodeConfigure"
+	Meshmesh_NetworkNodeDelete_FullMethodName      = "/meshmesh.Meshmesh/NetworkNodeDelete"
+	Meshmesh_NetworkEdgeConfigure_FullMethodName   = "/meshmesh.Meshmesh/NetworkEdgeConfigure"
+	Meshmesh_NetworkAnalysis_FullMethodName        = "/meshmesh.Meshmesh/NetworkAnalysis"
+	Meshmesh_NetworkFailureImpact_FullMethodName   = "/meshmesh.Meshmesh/NetworkFailureImpact"
+	Meshmesh_NetworkHistory_FullMethodName         = "/meshmesh.Meshmesh/NetworkHistory"
+	Meshmesh_NetworkHistoryDiff_FullMethodName     = "/meshmesh.Meshmesh/NetworkHistoryDiff"
+	Meshmesh_NetworkRollback_FullMethodName        = "/meshmesh.Meshmesh/NetworkRollback"
+	Meshmesh_NetworkImport_FullMethodName          = "/meshmesh.Meshmesh/NetworkImport"
+	Meshmesh_NetworkAdoptions_FullMethodName       = "/meshmesh.Meshmesh/NetworkAdoptions"
+	Meshmesh_NetworkAdoptionControl_FullMethodName = "/meshmesh.Meshmesh/NetworkAdoptionControl"
+	Meshmesh_EsphomePorts_FullMethodName           = "/meshmesh.Meshmesh/EsphomePorts"
+	Meshmesh_SetEsphomePorts_FullMethodName        = "/meshmesh.Meshmesh/SetEsphomePorts"
 )
 
 // MeshmeshClient is the client API for Meshmesh service.
@@ -54,10 +68,24 @@ type MeshmeshClient interface {
 	GetEntityState(ctx context.Context, in *GetEntityStateRequest, opts ...grpc.CallOption) (*GetEntityStateReply, error)
 	SetEntityState(ctx context.Context, in *SetEntityStateRequest, opts ...grpc.CallOption) (*SetEntityStateReply, error)
 	ExecuteDiscovery(ctx context.Context, in *ExecuteDiscoveryRequest, opts ...grpc.CallOption) (*ExecuteDiscoveryReply, error)
+	StartDiscovery(ctx context.Context, in *StartDiscoveryRequest, opts ...grpc.CallOption) (*StartDiscoveryReply, error)
+	DiscoverNodes(ctx context.Context, in *DiscoverNodesRequest, opts ...grpc.CallOption) (*DiscoverNodesReply, error)
+	DiscoveryControl(ctx context.Context, in *DiscoveryControlRequest, opts ...grpc.CallOption) (*DiscoveryControlReply, error)
 	NetworkNodes(ctx context.Context, in *NetworkNodesRequest, opts ...grpc.CallOption) (*NetworkNodesReply, error)
 	NetworkEdges(ctx context.Context, in *NetworkEdgesRequest, opts ...grpc.CallOption) (*NetworkEdgesReply, error)
 	NetworkNodeConfigure(ctx context.Context, in *NetworkNodeConfigureRequest, opts ...grpc.CallOption) (*NetworkNodeConfigureReply, error)
 	NetworkNodeDelete(ctx context.Context, in *NetworkNodeDeleteRequest, opts ...grpc.CallOption) (*NetworkNodeDeleteReply, error)
+	NetworkEdgeConfigure(ctx context.Context, in *NetworkEdgeConfigureRequest, opts ...grpc.CallOption) (*NetworkEdgeConfigureReply, error)
+	NetworkAnalysis(ctx context.Context, in *NetworkAnalysisRequest, opts ...grpc.CallOption) (*NetworkAnalysisReply, error)
+	NetworkFailureImpact(ctx context.Context, in *NetworkFailureImpactRequest, opts ...grpc.CallOption) (*NetworkFailureImpactReply, error)
+	NetworkHistory(ctx context.Context, in *NetworkHistoryRequest, opts ...grpc.CallOption) (*NetworkHistoryReply, error)
+	NetworkHistoryDiff(ctx context.Context, in *NetworkHistoryDiffRequest, opts ...grpc.CallOption) (*NetworkHistoryDiffReply, error)
+	NetworkRollback(ctx context.Context, in *NetworkRollbackRequest, opts ...grpc.CallOption) (*NetworkRollbackReply, error)
+	NetworkImport(ctx context.Context, in *NetworkImportRequest, opts ...grpc.CallOption) (*NetworkImportReply, error)
+	NetworkAdoptions(ctx context.Context, in *NetworkAdoptionsRequest, opts ...grpc.CallOption) (*NetworkAdoptionsReply, error)
+	NetworkAdoptionControl(ctx context.Context, in *NetworkAdoptionControlRequest, opts ...grpc.CallOption) (*NetworkAdoptionControlReply, error)
+	EsphomePorts(ctx context.Context, in *EsphomePortsRequest, opts ...grpc.CallOption) (*EsphomePortsReply, error)
+	SetEsphomePorts(ctx context.Context, in *SetEsphomePortsRequest, opts ...grpc.CallOption) (*SetEsphomePortsReply, error)
 }
 
 type meshmeshClient struct {
@@ -178,6 +206,36 @@ func (c *meshmeshClient) ExecuteDiscovery(ctx context.Context, in *ExecuteDiscov
 	return out, nil
 }
 
+func (c *meshmeshClient) StartDiscovery(ctx context.Context, in *StartDiscoveryRequest, opts ...grpc.CallOption) (*StartDiscoveryReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StartDiscoveryReply)
+	err := c.cc.Invoke(ctx, Meshmesh_StartDiscovery_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshmeshClient) DiscoverNodes(ctx context.Context, in *DiscoverNodesRequest, opts ...grpc.CallOption) (*DiscoverNodesReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DiscoverNodesReply)
+	err := c.cc.Invoke(ctx, Meshmesh_DiscoverNodes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshmeshClient) DiscoveryControl(ctx context.Context, in *DiscoveryControlRequest, opts ...grpc.CallOption) (*DiscoveryControlReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DiscoveryControlReply)
+	err := c.cc.Invoke(ctx, Meshmesh_DiscoveryControl_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *meshmeshClient) NetworkNodes(ctx context.Context, in *NetworkNodesRequest, opts ...grpc.CallOption) (*NetworkNodesReply, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(NetworkNodesReply)
@@ -218,6 +276,116 @@ func (c *meshmeshClient) NetworkNodeDelete(ctx context.Context, in *NetworkNodeD
 	return out, nil
 }
 
+func (c *meshmeshClient) NetworkEdgeConfigure(ctx context.Context, in *NetworkEdgeConfigureRequest, opts ...grpc.CallOption) (*NetworkEdgeConfigureReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NetworkEdgeConfigureReply)
+	err := c.cc.Invoke(ctx, Meshmesh_NetworkEdgeConfigure_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshmeshClient) NetworkAnalysis(ctx context.Context, in *NetworkAnalysisRequest, opts ...grpc.CallOption) (*NetworkAnalysisReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NetworkAnalysisReply)
+	err := c.cc.Invoke(ctx, Meshmesh_NetworkAnalysis_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshmeshClient) NetworkFailureImpact(ctx context.Context, in *NetworkFailureImpactRequest, opts ...grpc.CallOption) (*NetworkFailureImpactReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NetworkFailureImpactReply)
+	err := c.cc.Invoke(ctx, Meshmesh_NetworkFailureImpact_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshmeshClient) NetworkHistory(ctx context.Context, in *NetworkHistoryRequest, opts ...grpc.CallOption) (*NetworkHistoryReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NetworkHistoryReply)
+	err := c.cc.Invoke(ctx, Meshmesh_NetworkHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshmeshClient) NetworkHistoryDiff(ctx context.Context, in *NetworkHistoryDiffRequest, opts ...grpc.CallOption) (*NetworkHistoryDiffReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NetworkHistoryDiffReply)
+	err := c.cc.Invoke(ctx, Meshmesh_NetworkHistoryDiff_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshmeshClient) NetworkRollback(ctx context.Context, in *NetworkRollbackRequest, opts ...grpc.CallOption) (*NetworkRollbackReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NetworkRollbackReply)
+	err := c.cc.Invoke(ctx, Meshmesh_NetworkRollback_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshmeshClient) NetworkImport(ctx context.Context, in *NetworkImportRequest, opts ...grpc.CallOption) (*NetworkImportReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NetworkImportReply)
+	err := c.cc.Invoke(ctx, Meshmesh_NetworkImport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshmeshClient) NetworkAdoptions(ctx context.Context, in *NetworkAdoptionsRequest, opts ...grpc.CallOption) (*NetworkAdoptionsReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NetworkAdoptionsReply)
+	err := c.cc.Invoke(ctx, Meshmesh_NetworkAdoptions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshmeshClient) NetworkAdoptionControl(ctx context.Context, in *NetworkAdoptionControlRequest, opts ...grpc.CallOption) (*NetworkAdoptionControlReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NetworkAdoptionControlReply)
+	err := c.cc.Invoke(ctx, Meshmesh_NetworkAdoptionControl_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshmeshClient) EsphomePorts(ctx context.Context, in *EsphomePortsRequest, opts ...grpc.CallOption) (*EsphomePortsReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EsphomePortsReply)
+	err := c.cc.Invoke(ctx, Meshmesh_EsphomePorts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meshmeshClient) SetEsphomePorts(ctx context.Context, in *SetEsphomePortsRequest, opts ...grpc.CallOption) (*SetEsphomePortsReply, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetEsphomePortsReply)
+	err := c.cc.Invoke(ctx, Meshmesh_SetEsphomePorts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MeshmeshServer is the server API for Meshmesh service.
 // All implementations must embed UnimplementedMeshmeshServer
 // for forward compatibility.
@@ -236,10 +404,24 @@ type MeshmeshServer interface {
 	GetEntityState(context.Context, *GetEntityStateRequest) (*GetEntityStateReply, error)
 	SetEntityState(context.Context, *SetEntityStateRequest) (*SetEntityStateReply, error)
 	ExecuteDiscovery(context.Context, *ExecuteDiscoveryRequest) (*ExecuteDiscoveryReply, error)
+	StartDiscovery(context.Context, *StartDiscoveryRequest) (*StartDiscoveryReply, error)
+	DiscoverNodes(context.Context, *DiscoverNodesRequest) (*DiscoverNodesReply, error)
+	DiscoveryControl(context.Context, *DiscoveryControlRequest) (*DiscoveryControlReply, error)
 	NetworkNodes(context.Context, *NetworkNodesRequest) (*NetworkNodesReply, error)
 	NetworkEdges(context.Context, *NetworkEdgesRequest) (*NetworkEdgesReply, error)
 	NetworkNodeConfigure(context.Context, *NetworkNodeConfigureRequest) (*NetworkNodeConfigureReply, error)
 	NetworkNodeDelete(context.Context, *NetworkNodeDeleteRequest) (*NetworkNodeDeleteReply, error)
+	NetworkEdgeConfigure(context.Context, *NetworkEdgeConfigureRequest) (*NetworkEdgeConfigureReply, error)
+	NetworkAnalysis(context.Context, *NetworkAnalysisRequest) (*NetworkAnalysisReply, error)
+	NetworkFailureImpact(context.Context, *NetworkFailureImpactRequest) (*NetworkFailureImpactReply, error)
+	NetworkHistory(context.Context, *NetworkHistoryRequest) (*NetworkHistoryReply, error)
+	NetworkHistoryDiff(context.Context, *NetworkHistoryDiffRequest) (*NetworkHistoryDiffReply, error)
+	NetworkRollback(context.Context, *NetworkRollbackRequest) (*NetworkRollbackReply, error)
+	NetworkImport(context.Context, *NetworkImportRequest) (*NetworkImportReply, error)
+	NetworkAdoptions(context.Context, *NetworkAdoptionsRequest) (*NetworkAdoptionsReply, error)
+	NetworkAdoptionControl(context.Context, *NetworkAdoptionControlRequest) (*NetworkAdoptionControlReply, error)
+	EsphomePorts(context.Context, *EsphomePortsRequest) (*EsphomePortsReply, error)
+	SetEsphomePorts(context.Context, *SetEsphomePortsRequest) (*SetEsphomePortsReply, error)
 	mustEmbedUnimplementedMeshmeshServer()
 }
 
@@ -283,6 +465,15 @@ func (UnimplementedMeshmeshServer) SetEntityState(context.Context, *SetEntitySta
 func (UnimplementedMeshmeshServer) ExecuteDiscovery(context.Context, *ExecuteDiscoveryRequest) (*ExecuteDiscoveryReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ExecuteDiscovery not implemented")
 }
+func (UnimplementedMeshmeshServer) StartDiscovery(context.Context, *StartDiscoveryRequest) (*StartDiscoveryReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartDiscovery not implemented")
+}
+func (UnimplementedMeshmeshServer) DiscoverNodes(context.Context, *DiscoverNodesRequest) (*DiscoverNodesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiscoverNodes not implemented")
+}
+func (UnimplementedMeshmeshServer) DiscoveryControl(context.Context, *DiscoveryControlRequest) (*DiscoveryControlReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiscoveryControl not implemented")
+}
 func (UnimplementedMeshmeshServer) NetworkNodes(context.Context, *NetworkNodesRequest) (*NetworkNodesReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method NetworkNodes not implemented")
 }
@@ -295,6 +486,39 @@ func (UnimplementedMeshmeshServer) NetworkNodeConfigure(context.Context, *Networ
 func (UnimplementedMeshmeshServer) NetworkNodeDelete(context.Context, *NetworkNodeDeleteRequest) (*NetworkNodeDeleteReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method NetworkNodeDelete not implemented")
 }
+func (UnimplementedMeshmeshServer) NetworkEdgeConfigure(context.Context, *NetworkEdgeConfigureRequest) (*NetworkEdgeConfigureReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NetworkEdgeConfigure not implemented")
+}
+func (UnimplementedMeshmeshServer) NetworkAnalysis(context.Context, *NetworkAnalysisRequest) (*NetworkAnalysisReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NetworkAnalysis not implemented")
+}
+func (UnimplementedMeshmeshServer) NetworkFailureImpact(context.Context, *NetworkFailureImpactRequest) (*NetworkFailureImpactReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NetworkFailureImpact not implemented")
+}
+func (UnimplementedMeshmeshServer) NetworkHistory(context.Context, *NetworkHistoryRequest) (*NetworkHistoryReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NetworkHistory not implemented")
+}
+func (UnimplementedMeshmeshServer) NetworkHistoryDiff(context.Context, *NetworkHistoryDiffRequest) (*NetworkHistoryDiffReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NetworkHistoryDiff not implemented")
+}
+func (UnimplementedMeshmeshServer) NetworkRollback(context.Context, *NetworkRollbackRequest) (*NetworkRollbackReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NetworkRollback not implemented")
+}
+func (UnimplementedMeshmeshServer) NetworkImport(context.Context, *NetworkImportRequest) (*NetworkImportReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NetworkImport not implemented")
+}
+func (UnimplementedMeshmeshServer) NetworkAdoptions(context.Context, *NetworkAdoptionsRequest) (*NetworkAdoptionsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NetworkAdoptions not implemented")
+}
+func (UnimplementedMeshmeshServer) NetworkAdoptionControl(context.Context, *NetworkAdoptionControlRequest) (*NetworkAdoptionControlReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NetworkAdoptionControl not implemented")
+}
+func (UnimplementedMeshmeshServer) EsphomePorts(context.Context, *EsphomePortsRequest) (*EsphomePortsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EsphomePorts not implemented")
+}
+func (UnimplementedMeshmeshServer) SetEsphomePorts(context.Context, *SetEsphomePortsRequest) (*SetEsphomePortsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetEsphomePorts not implemented")
+}
 func (UnimplementedMeshmeshServer) mustEmbedUnimplementedMeshmeshServer() {}
 func (UnimplementedMeshmeshServer) testEmbeddedByValue()                  {}
 
@@ -514,6 +738,60 @@ func _Meshmesh_ExecuteDiscovery_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Meshmesh_StartDiscovery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartDiscoveryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshmeshServer).StartDiscovery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Meshmesh_StartDiscovery_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshmeshServer).StartDiscovery(ctx, req.(*StartDiscoveryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Meshmesh_DiscoverNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiscoverNodesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshmeshServer).DiscoverNodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Meshmesh_DiscoverNodes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshmeshServer).DiscoverNodes(ctx, req.(*DiscoverNodesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Meshmesh_DiscoveryControl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiscoveryControlRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshmeshServer).DiscoveryControl(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Meshmesh_DiscoveryControl_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshmeshServer).DiscoveryControl(ctx, req.(*DiscoveryControlRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Meshmesh_NetworkNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(NetworkNodesRequest)
 	if err := dec(in); err != nil {
@@ -586,6 +864,204 @@ func _Meshmesh_NetworkNodeDelete_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Meshmesh_NetworkEdgeConfigure_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NetworkEdgeConfigureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshmeshServer).NetworkEdgeConfigure(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Meshmesh_NetworkEdgeConfigure_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshmeshServer).NetworkEdgeConfigure(ctx, req.(*NetworkEdgeConfigureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Meshmesh_NetworkAnalysis_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NetworkAnalysisRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshmeshServer).NetworkAnalysis(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Meshmesh_NetworkAnalysis_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshmeshServer).NetworkAnalysis(ctx, req.(*NetworkAnalysisRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Meshmesh_NetworkFailureImpact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NetworkFailureImpactRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshmeshServer).NetworkFailureImpact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Meshmesh_NetworkFailureImpact_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshmeshServer).NetworkFailureImpact(ctx, req.(*NetworkFailureImpactRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Meshmesh_NetworkHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NetworkHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshmeshServer).NetworkHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Meshmesh_NetworkHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshmeshServer).NetworkHistory(ctx, req.(*NetworkHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Meshmesh_NetworkHistoryDiff_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NetworkHistoryDiffRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshmeshServer).NetworkHistoryDiff(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Meshmesh_NetworkHistoryDiff_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshmeshServer).NetworkHistoryDiff(ctx, req.(*NetworkHistoryDiffRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Meshmesh_NetworkRollback_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NetworkRollbackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshmeshServer).NetworkRollback(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Meshmesh_NetworkRollback_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshmeshServer).NetworkRollback(ctx, req.(*NetworkRollbackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Meshmesh_NetworkImport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NetworkImportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshmeshServer).NetworkImport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Meshmesh_NetworkImport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshmeshServer).NetworkImport(ctx, req.(*NetworkImportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Meshmesh_NetworkAdoptions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NetworkAdoptionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshmeshServer).NetworkAdoptions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Meshmesh_NetworkAdoptions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshmeshServer).NetworkAdoptions(ctx, req.(*NetworkAdoptionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Meshmesh_NetworkAdoptionControl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NetworkAdoptionControlRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshmeshServer).NetworkAdoptionControl(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Meshmesh_NetworkAdoptionControl_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshmeshServer).NetworkAdoptionControl(ctx, req.(*NetworkAdoptionControlRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Meshmesh_EsphomePorts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EsphomePortsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshmeshServer).EsphomePorts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Meshmesh_EsphomePorts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshmeshServer).EsphomePorts(ctx, req.(*EsphomePortsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Meshmesh_SetEsphomePorts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetEsphomePortsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeshmeshServer).SetEsphomePorts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Meshmesh_SetEsphomePorts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeshmeshServer).SetEsphomePorts(ctx, req.(*SetEsphomePortsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Meshmesh_ServiceDesc is the grpc.ServiceDesc for Meshmesh service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -637,6 +1113,18 @@ var Meshmesh_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ExecuteDiscovery",
 			Handler:    _Meshmesh_ExecuteDiscovery_Handler,
 		},
+		{
+			MethodName: "StartDiscovery",
+			Handler:    _Meshmesh_StartDiscovery_Handler,
+		},
+		{
+			MethodName: "DiscoverNodes",
+			Handler:    _Meshmesh_DiscoverNodes_Handler,
+		},
+		{
+			MethodName: "DiscoveryControl",
+			Handler:    _Meshmesh_DiscoveryControl_Handler,
+		},
 		{
 			MethodName: "NetworkNodes",
 			Handler:    _Meshmesh_NetworkNodes_Handler,
@@ -653,6 +1141,50 @@ var Meshmesh_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "NetworkNodeDelete",
 			Handler:    _Meshmesh_NetworkNodeDelete_Handler,
 		},
+		{
+			MethodName: "NetworkEdgeConfigure",
+			Handler:    _Meshmesh_NetworkEdgeConfigure_Handler,
+		},
+		{
+			MethodName: "NetworkAnalysis",
+			Handler:    _Meshmesh_NetworkAnalysis_Handler,
+		},
+		{
+			MethodName: "NetworkFailureImpact",
+			Handler:    _Meshmesh_NetworkFailureImpact_Handler,
+		},
+		{
+			MethodName: "NetworkHistory",
+			Handler:    _Meshmesh_NetworkHistory_Handler,
+		},
+		{
+			MethodName: "NetworkHistoryDiff",
+			Handler:    _Meshmesh_NetworkHistoryDiff_Handler,
+		},
+		{
+			MethodName: "NetworkRollback",
+			Handler:    _Meshmesh_NetworkRollback_Handler,
+		},
+		{
+			MethodName: "NetworkImport",
+			Handler:    _Meshmesh_NetworkImport_Handler,
+		},
+		{
+			MethodName: "NetworkAdoptions",
+			Handler:    _Meshmesh_NetworkAdoptions_Handler,
+		},
+		{
+			MethodName: "NetworkAdoptionControl",
+			Handler:    _Meshmesh_NetworkAdoptionControl_Handler,
+		},
+		{
+			MethodName: "EsphomePorts",
+			Handler:    _Meshmesh_EsphomePorts_Handler,
+		},
+		{
+			MethodName: "SetEsphomePorts",
+			Handler:    _Meshmesh_SetEsphomePorts_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "meshmesh/meshmesh.proto",
@@ -16,12 +16,13 @@ import (
 type Server struct {
 	meshmesh.UnimplementedMeshmeshServer
 	serialConn     *mm.SerialConnection
+	esphomeServers *mm.MultiServerApi
 	programName    string
 	programVersion string
 }
 
-func NewServer(programName string, programVersion string, serialConn *mm.SerialConnection) *Server {
-	return &Server{programName: programName, programVersion: programVersion, serialConn: serialConn}
+func NewServer(programName string, programVersion string, serialConn *mm.SerialConnection, esphomeServers *mm.MultiServerApi) *Server {
+	return &Server{programName: programName, programVersion: programVersion, serialConn: serialConn, esphomeServers: esphomeServers}
 }
 
 func (s *Server) SayHello(_ context.Context, req *meshmesh.HelloRequest) (*meshmesh.HelloReply, error) {
@@ -44,7 +45,7 @@ func (s *RpcServer) serve() {
 	}
 }
 
-func (s *RpcServer) Start(programName string, programVersion string, serialConn *mm.SerialConnection) error {
+func (s *RpcServer) Start(programName string, programVersion string, serialConn *mm.SerialConnection, esphomeServers *mm.MultiServerApi) error {
 	var err error
 	s.lis, err = net.Listen("tcp", s.port)
 	if err != nil {
@@ -52,7 +53,7 @@ func (s *RpcServer) Start(programName string, programVersion string, serialConn
 	}
 
 	s.grpcServer = grpc.NewServer()
-	meshmesh.RegisterMeshmeshServer(s.grpcServer, NewServer(programName, programVersion, serialConn))
+	meshmesh.RegisterMeshmeshServer(s.grpcServer, NewServer(programName, programVersion, serialConn, esphomeServers))
 	logger.WithField("port", s.port).Info("Starting gRPC server")
 	reflection.Register(s.grpcServer)
 	go s.serve()
@@ -3,7 +3,9 @@ package rpc
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"strings"
+	"time"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -152,3 +154,118 @@ func (s *Server) ExecuteDiscovery(_ context.Context, req *meshmesh.ExecuteDiscov
 	}
 	return &meshmesh.ExecuteDiscoveryReply{Success: true}, nil
 }
+
+// discoveryParams returns the parameters of the request applied over the defaults
+func discoveryParams(req *meshmesh.DiscoveryParams) (mm.DiscoveryParams, error) {
+	params := mm.DefaultDiscoveryParams()
+	if req == nil {
+		return params, nil
+	}
+	for _, value := range []*uint32{req.Slots, req.Mask, req.Filter} {
+		if value != nil && *value > 255 {
+			return params, fmt.Errorf("value %d out of range", *value)
+		}
+	}
+	if req.Slots != nil {
+		params.Slots = uint8(*req.Slots)
+	}
+	if req.Mask != nil {
+		params.Mask = uint8(*req.Mask)
+	}
+	if req.Filter != nil {
+		params.Filter = uint8(*req.Filter)
+	}
+	if req.WaitMs != nil {
+		params.Wait = time.Duration(*req.WaitMs) * time.Millisecond
+	}
+	if req.Repetitions != nil {
+		params.Repetitions = int(*req.Repetitions)
+	}
+	if req.Aggregation != nil {
+		params.Aggregation = *req.Aggregation
+	}
+	if req.RssiMin != nil {
+		params.RssiMin = int16(*req.RssiMin)
+	}
+	if req.RssiMax != nil {
+		params.RssiMax = int16(*req.RssiMax)
+	}
+	if req.DryRun != nil {
+		params.DryRun = *req.DryRun
+	}
+	if req.WeightThreshold != nil {
+		params.WeightThreshold = *req.WeightThreshold
+	}
+	return params, params.Validate()
+}
+
+func (s *Server) StartDiscovery(_ context.Context, req *meshmesh.StartDiscoveryRequest) (*meshmesh.StartDiscoveryReply, error) {
+	params, err := discoveryParams(req.Params)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid discovery parameters: %v", err)
+	}
+
+	var network *graph.Network
+	if req.Refresh {
+		network = graph.GetMainNetwork().CopyNetwork()
+	}
+	discovery := mm.NewDiscoveryProcedure(s.serialConn, network, graph.GetMainNetwork().LocalDeviceId())
+	if err = discovery.SetParams(params); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid discovery parameters: %v", err)
+	}
+	if err = mm.StartDiscovery(discovery); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "Failed to start discovery: %v", err)
+	}
+	return &meshmesh.StartDiscoveryReply{Success: true}, nil
+}
+
+func (s *Server) DiscoverNodes(_ context.Context, req *meshmesh.DiscoverNodesRequest) (*meshmesh.DiscoverNodesReply, error) {
+	params, err := discoveryParams(req.Params)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid discovery parameters: %v", err)
+	}
+
+	nodes := make([]int64, len(req.Ids))
+	for i, id := range req.Ids {
+		nodes[i] = int64(id)
+	}
+	discovery, err := mm.NewTargetedDiscoveryProcedure(s.serialConn, nodes, req.Subtree)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid discovery request: %v", err)
+	}
+	if err = discovery.SetParams(params); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid discovery parameters: %v", err)
+	}
+	if err = mm.StartDiscovery(discovery); err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "Failed to start discovery: %v", err)
+	}
+
+	targets := make([]uint32, len(discovery.Targets()))
+	for i, id := range discovery.Targets() {
+		targets[i] = uint32(id)
+	}
+	return &meshmesh.DiscoverNodesReply{Success: true, Targets: targets}, nil
+}
+
+func (s *Server) DiscoveryControl(_ context.Context, req *meshmesh.DiscoveryControlRequest) (*meshmesh.DiscoveryControlReply, error) {
+	if req.Action != "" {
+		if err := mm.ControlDiscovery(req.Action); err != nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "Failed to control discovery: %v", err)
+		}
+	}
+
+	discovery := mm.CurrentDiscovery()
+	if discovery == nil {
+		return &meshmesh.DiscoveryControlReply{State: mm.DiscoveryProcedureStateIdle.String()}, nil
+	}
+	progress := discovery.Progress()
+	return &meshmesh.DiscoveryControlReply{
+		State:      progress.State.String(),
+		CurrentId:  uint32(progress.CurrentDeviceId),
+		Repeat:     uint32(progress.Repeat),
+		Done:       uint32(progress.Done),
+		Total:      uint32(progress.Total),
+		EtaSeconds: uint32(progress.Eta.Seconds()),
+		Error:      progress.Error,
+	}, nil
+}
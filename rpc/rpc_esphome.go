@@ -0,0 +1,43 @@
+package rpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	mm "leguru.net/m/v2/meshmesh"
+	"leguru.net/m/v2/rpc/meshmesh"
+)
+
+func portAllocationToRpc(alloc mm.PortAllocation) *meshmesh.EsphomePortAllocation {
+	return &meshmesh.EsphomePortAllocation{
+		Id:            uint32(alloc.Node),
+		BindAddresses: alloc.BindAddresses,
+		ApiPort:       uint32(alloc.ApiPort),
+		OtaPort:       uint32(alloc.OtaPort),
+		Manual:        alloc.Manual,
+	}
+}
+
+func (s *Server) EsphomePorts(_ context.Context, req *meshmesh.EsphomePortsRequest) (*meshmesh.EsphomePortsReply, error) {
+	allocs := s.esphomeServers.PortAllocations()
+	ports := make([]*meshmesh.EsphomePortAllocation, len(allocs))
+	for i, alloc := range allocs {
+		ports[i] = portAllocationToRpc(alloc)
+	}
+	return &meshmesh.EsphomePortsReply{Ports: ports}, nil
+}
+
+func (s *Server) SetEsphomePorts(_ context.Context, req *meshmesh.SetEsphomePortsRequest) (*meshmesh.SetEsphomePortsReply, error) {
+	var alloc mm.PortAllocation
+	var err error
+	if req.Reset_ {
+		alloc, err = s.esphomeServers.ResetPortAllocation(mm.MeshNodeId(req.Id))
+	} else {
+		alloc, err = s.esphomeServers.SetPortAllocation(mm.MeshNodeId(req.Id), req.BindAddresses, int(req.ApiPort), int(req.OtaPort))
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Failed to set esphome ports: %v", err)
+	}
+	return &meshmesh.SetEsphomePortsReply{Port: portAllocationToRpc(alloc)}, nil
+}
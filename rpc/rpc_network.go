@@ -1,24 +1,46 @@
 package rpc
 
 import (
+	"bytes"
 	"context"
+	"io"
 
+	gr "gonum.org/v1/gonum/graph"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"leguru.net/m/v2/graph"
+	mm "leguru.net/m/v2/meshmesh"
 	"leguru.net/m/v2/rpc/meshmesh"
 )
 
 func (s *Server) NetworkNodes(_ context.Context, req *meshmesh.NetworkNodesRequest) (*meshmesh.NetworkNodesReply, error) {
-	nodes := graph.GetMainNetwork().Nodes()
+	nodes := graph.GetMainNetworkSnapshot().Nodes()
 	device := make([]*meshmesh.NetworkNode, nodes.Len())
 	i := 0
 	for nodes.Next() {
 		dev := nodes.Node().(graph.NodeDevice)
 		device[i] = &meshmesh.NetworkNode{
-			Id:    uint32(dev.ID()),
-			Tag:   string(dev.Device().Tag()),
-			Inuse: dev.Device().InUse(),
+			Id:            uint32(dev.ID()),
+			Tag:           string(dev.Device().Tag()),
+			Inuse:         dev.Device().InUse(),
+			Chip:          dev.Device().Chip(),
+			WeightProfile: dev.Device().WeightProfile(),
+			Firmware:      dev.Device().Firmware(),
+			Location:      dev.Device().Location(),
+			Notes:         dev.Device().Notes(),
+			Labels:        dev.Device().Labels(),
+		}
+		if config, ok := dev.Device().Config(); ok {
+			device[i].Config = &meshmesh.NetworkNodeConfig{
+				Channel:      uint32(config.Channel),
+				TxPower:      uint32(config.TxPower),
+				Groups:       config.Groups,
+				BindedServer: config.BindedServer,
+				Flags:        uint32(config.Flags),
+			}
+		}
+		if lastSeen := dev.Device().LastSeen(); !lastSeen.IsZero() {
+			device[i].LastSeen = lastSeen.Unix()
 		}
 		i += 1
 	}
@@ -26,42 +48,284 @@ func (s *Server) NetworkNodes(_ context.Context, req *meshmesh.NetworkNodesReque
 }
 
 func (s *Server) NetworkEdges(_ context.Context, req *meshmesh.NetworkEdgesRequest) (*meshmesh.NetworkEdgesReply, error) {
-	edges := graph.GetMainNetwork().WeightedEdges()
+	network := graph.GetMainNetworkSnapshot()
+	edges := network.WeightedEdges()
 	_edges := make([]*meshmesh.NetworkEdge, edges.Len())
 	i := 0
 	for edges.Next() {
-		edge := edges.WeightedEdge()
-		_edges[i] = &meshmesh.NetworkEdge{
-			From:   uint32(edge.From().ID()),
-			To:     uint32(edge.To().ID()),
-			Weight: float32(edge.Weight()),
-		}
+		_edges[i] = networkEdge(network, edges.WeightedEdge())
 		i += 1
 	}
 	return &meshmesh.NetworkEdgesReply{Edges: _edges}, nil
 }
 
+func networkEdge(network *graph.Network, edge gr.WeightedEdge) *meshmesh.NetworkEdge {
+	attrs := network.LinkAttributes(edge.From().ID(), edge.To().ID())
+	return &meshmesh.NetworkEdge{
+		From:      uint32(edge.From().ID()),
+		To:        uint32(edge.To().ID()),
+		Weight:    float32(edge.Weight()),
+		Pinned:    attrs.Pinned,
+		Excluded:  attrs.Excluded,
+		Preferred: attrs.Preferred,
+	}
+}
+
+func (s *Server) NetworkEdgeConfigure(_ context.Context, req *meshmesh.NetworkEdgeConfigureRequest) (*meshmesh.NetworkEdgeConfigureReply, error) {
+	network := graph.GetMainNetwork()
+	from, to := int64(req.From), int64(req.To)
+	if !network.HasEdgeFromTo(from, to) {
+		return nil, status.Errorf(codes.NotFound, "Edge not found")
+	}
+
+	_ = graph.UpdateMainNetwork(func(network *graph.Network) error {
+		if req.Weight != nil {
+			network.ChangeEdgeWeight(from, to, float64(*req.Weight), float64(*req.Weight))
+		}
+		attrs := network.LinkAttributes(from, to)
+		if req.Pinned != nil {
+			attrs.Pinned = *req.Pinned
+		}
+		if req.Excluded != nil {
+			attrs.Excluded = *req.Excluded
+		}
+		if req.Preferred != nil {
+			attrs.Preferred = *req.Preferred
+		}
+		network.SetLinkAttributes(from, to, attrs)
+		return nil
+	})
+	return &meshmesh.NetworkEdgeConfigureReply{Edge: networkEdge(network, network.WeightedEdge(from, to))}, nil
+}
+
+func nodeIds(ids []int64) []uint32 {
+	_ids := make([]uint32, len(ids))
+	for i, id := range ids {
+		_ids[i] = uint32(id)
+	}
+	return _ids
+}
+
+func (s *Server) NetworkAnalysis(_ context.Context, req *meshmesh.NetworkAnalysisRequest) (*meshmesh.NetworkAnalysisReply, error) {
+	analysis := graph.GetMainNetworkSnapshot().Analyze()
+	_nodes := make([]*meshmesh.NetworkNodeAnalysis, len(analysis.Nodes))
+	for i, node := range analysis.Nodes {
+		_nodes[i] = &meshmesh.NetworkNodeAnalysis{
+			Id:           uint32(node.Node),
+			Hops:         int32(node.Hops),
+			Betweenness:  node.Betweenness,
+			Relayed:      uint32(node.Relayed),
+			Articulation: node.Articulation,
+			Dependents:   nodeIds(node.Dependents),
+			SinglePath:   node.SinglePath,
+		}
+	}
+	_bridges := make([]*meshmesh.NetworkBridgeAnalysis, len(analysis.Bridges))
+	for i, bridge := range analysis.Bridges {
+		_bridges[i] = &meshmesh.NetworkBridgeAnalysis{From: uint32(bridge.From), To: uint32(bridge.To), Dependents: nodeIds(bridge.Dependents)}
+	}
+	return &meshmesh.NetworkAnalysisReply{Nodes: _nodes, Bridges: _bridges, Unreachable: nodeIds(analysis.Unreachable)}, nil
+}
+
+func (s *Server) NetworkFailureImpact(_ context.Context, req *meshmesh.NetworkFailureImpactRequest) (*meshmesh.NetworkFailureImpactReply, error) {
+	impact, err := graph.GetMainNetworkSnapshot().FailureImpact(int64(req.Id))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Failed to compute failure impact: %v", err)
+	}
+	_rerouted := make([]*meshmesh.NetworkRouteChange, len(impact.Rerouted))
+	for i, change := range impact.Rerouted {
+		_rerouted[i] = &meshmesh.NetworkRouteChange{Id: uint32(change.Node), OldPath: nodeIds(change.OldPath), NewPath: nodeIds(change.NewPath)}
+	}
+	return &meshmesh.NetworkFailureImpactReply{Lost: nodeIds(impact.Lost), Rerouted: _rerouted}, nil
+}
+
+func networkVersion(version graph.GraphVersion) *meshmesh.NetworkVersion {
+	return &meshmesh.NetworkVersion{
+		Id:       uint32(version.Id),
+		Time:     version.Time.Unix(),
+		Nodes:    uint32(version.Nodes),
+		Edges:    uint32(version.Edges),
+		Rollback: uint32(version.Rollback),
+	}
+}
+
+func (s *Server) NetworkHistory(_ context.Context, req *meshmesh.NetworkHistoryRequest) (*meshmesh.NetworkHistoryReply, error) {
+	versions := graph.GetGraphHistory().List()
+	_versions := make([]*meshmesh.NetworkVersion, len(versions))
+	for i, version := range versions {
+		_versions[i] = networkVersion(version)
+	}
+	return &meshmesh.NetworkHistoryReply{Versions: _versions}, nil
+}
+
+func (s *Server) NetworkHistoryDiff(_ context.Context, req *meshmesh.NetworkHistoryDiffRequest) (*meshmesh.NetworkHistoryDiffReply, error) {
+	items, err := graph.GetGraphHistory().Diff(int(req.From), int(req.To), graph.GetMainNetwork().LocalDeviceId())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "Failed to compare versions: %v", err)
+	}
+	return &meshmesh.NetworkHistoryDiffReply{Items: networkDiffItems(items)}, nil
+}
+
+func networkDiffItems(items []graph.DiffItem) []*meshmesh.NetworkDiffItem {
+	_items := make([]*meshmesh.NetworkDiffItem, len(items))
+	for i, item := range items {
+		_items[i] = &meshmesh.NetworkDiffItem{
+			Kind:      string(item.Kind),
+			Node:      uint32(item.Node),
+			From:      uint32(item.From),
+			To:        uint32(item.To),
+			OldWeight: float32(item.OldWeight),
+			NewWeight: float32(item.NewWeight),
+			OldPath:   nodeIds(item.OldPath),
+			NewPath:   nodeIds(item.NewPath),
+			OldTag:    item.OldTag,
+			NewTag:    item.NewTag,
+			OldInuse:  item.OldInUse,
+			NewInuse:  item.NewInUse,
+		}
+	}
+	return _items
+}
+
+func (s *Server) NetworkRollback(_ context.Context, req *meshmesh.NetworkRollbackRequest) (*meshmesh.NetworkRollbackReply, error) {
+	if discovery := mm.CurrentDiscovery(); discovery != nil && discovery.IsRunning() {
+		return nil, status.Errorf(codes.FailedPrecondition, "Can't rollback while a discovery is running")
+	}
+	err := graph.GetGraphHistory().Rollback(int(req.Id))
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "Failed to rollback: %v", err)
+	}
+	return &meshmesh.NetworkRollbackReply{Success: true}, nil
+}
+
+func (s *Server) NetworkImport(_ context.Context, req *meshmesh.NetworkImportRequest) (*meshmesh.NetworkImportReply, error) {
+	format, err := graph.ParseImportFormat(req.Format)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	mode, err := graph.ParseImportMode(req.Mode)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	weights := graph.MergeWeightsImported
+	if req.Weights != "" {
+		if weights, err = graph.ParseMergeWeights(req.Weights); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+	}
+	graphImport, err := graph.NewGraphImport([]io.Reader{bytes.NewReader(req.Data)}, format, mode)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Failed to import graph: %v", err)
+	}
+	graphImport.Weights = weights
+	items := graphImport.Preview()
+	if !req.Commit {
+		return &meshmesh.NetworkImportReply{Items: networkDiffItems(items)}, nil
+	}
+	if discovery := mm.CurrentDiscovery(); discovery != nil && discovery.IsRunning() {
+		return nil, status.Errorf(codes.FailedPrecondition, "Can't import while a discovery is running")
+	}
+	if err := graphImport.Commit(); err != nil {
+		return nil, status.Errorf(codes.Aborted, "%v", err)
+	}
+	return &meshmesh.NetworkImportReply{Items: networkDiffItems(items), Committed: true}, nil
+}
+
 func (s *Server) NetworkNodeConfigure(_ context.Context, req *meshmesh.NetworkNodeConfigureRequest) (*meshmesh.NetworkNodeConfigureReply, error) {
 	network := graph.GetMainNetwork()
 	node := network.Node(int64(req.Id))
 	if node == nil {
 		return nil, status.Errorf(codes.NotFound, "Node not found")
 	}
+	if req.WeightProfile != nil && *req.WeightProfile != "" {
+		if _, ok := mm.GetWeightProfiles().Get(*req.WeightProfile); !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "Unknown weight profile %s", *req.WeightProfile)
+		}
+	}
+	if req.Labels != nil {
+		if err := graph.ValidateLabels(req.Labels.Labels); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+	}
 	dev := node.(graph.NodeDevice)
-	dev.Device().SetTag(req.Tag)
-	dev.Device().SetInUse(req.Inuse)
-	graph.NotifyMainNetworkChanged()
+	_ = graph.UpdateMainNetwork(func(network *graph.Network) error {
+		dev.Device().SetTag(req.Tag)
+		dev.Device().SetInUse(req.Inuse)
+		if req.Chip != nil {
+			dev.Device().SetChip(*req.Chip)
+		}
+		if req.WeightProfile != nil {
+			dev.Device().SetWeightProfile(*req.WeightProfile)
+		}
+		if req.Location != nil {
+			dev.Device().SetLocation(*req.Location)
+		}
+		if req.Notes != nil {
+			dev.Device().SetNotes(*req.Notes)
+		}
+		if req.Labels != nil {
+			dev.Device().SetLabels(req.Labels.Labels)
+		}
+		graph.NotifyMainNetworkChanged()
+		return nil
+	})
 	return &meshmesh.NetworkNodeConfigureReply{Success: true}, nil
 }
 
 func (s *Server) NetworkNodeDelete(_ context.Context, req *meshmesh.NetworkNodeDeleteRequest) (*meshmesh.NetworkNodeDeleteReply, error) {
-	network := graph.GetMainNetwork()
-	node := network.Node(int64(req.Id))
-	if node == nil {
-		return nil, status.Errorf(codes.NotFound, "Node not found")
+	err := graph.UpdateMainNetwork(func(network *graph.Network) error {
+		if !network.NodeIdExists(int64(req.Id)) {
+			return status.Errorf(codes.NotFound, "Node not found")
+		}
+		network.RemoveNode(int64(req.Id))
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-
-	network.RemoveNode(int64(req.Id))
-	graph.NotifyMainNetworkChanged()
 	return &meshmesh.NetworkNodeDeleteReply{Success: true}, nil
 }
+
+func networkAdoption(adoption mm.Adoption) *meshmesh.NetworkAdoption {
+	reply := &meshmesh.NetworkAdoption{
+		Id:       uint32(adoption.Node),
+		Server:   uint32(adoption.Server),
+		State:    adoption.State.String(),
+		Tag:      adoption.Tag,
+		Revision: adoption.Revision,
+		Error:    adoption.Error,
+	}
+	for _, neighbor := range adoption.Neighbors {
+		reply.Neighbors = append(reply.Neighbors, uint32(neighbor.Id))
+		reply.Rssi = append(reply.Rssi, int32(neighbor.Rssi))
+	}
+	return reply
+}
+
+func (s *Server) NetworkAdoptions(_ context.Context, req *meshmesh.NetworkAdoptionsRequest) (*meshmesh.NetworkAdoptionsReply, error) {
+	adoptions := mm.GetAdoptions().List()
+	_adoptions := make([]*meshmesh.NetworkAdoption, len(adoptions))
+	for i, adoption := range adoptions {
+		_adoptions[i] = networkAdoption(adoption)
+	}
+	return &meshmesh.NetworkAdoptionsReply{Adoptions: _adoptions}, nil
+}
+
+func (s *Server) NetworkAdoptionControl(_ context.Context, req *meshmesh.NetworkAdoptionControlRequest) (*meshmesh.NetworkAdoptionControlReply, error) {
+	var err error
+	node := mm.MeshNodeId(req.Id)
+	switch req.Action {
+	case "approve":
+		err = mm.GetAdoptions().Approve(node)
+	case "reject":
+		err = mm.GetAdoptions().Reject(node)
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "Unknown adoption action %s", req.Action)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "Failed to control adoption: %v", err)
+	}
+
+	adoption, _ := mm.GetAdoptions().Get(node)
+	return &meshmesh.NetworkAdoptionControlReply{Adoption: networkAdoption(adoption)}, nil
+}
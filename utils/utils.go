@@ -4,11 +4,9 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
+	"net"
 	"strconv"
 	"strings"
-	"time"
 
 	"hash/fnv"
 
@@ -40,6 +38,26 @@ func FmtNodeIdHass(nodeid int64) string {
 	return fmt.Sprintf("127.%d.%d.%d", (nodeid>>16)&0xFF, (nodeid>>8)&0xFF, nodeid&0xFF)
 }
 
+// FmtNodeIdHassIPv6 returns the address of the node inside the given IPv6 prefix (e.g. fd00:6d6d::/64)
+func FmtNodeIdHassIPv6(prefix string, nodeid int64) (string, error) {
+	_, network, err := net.ParseCIDR(prefix)
+	if err != nil {
+		return "", err
+	}
+	if network.IP.To4() != nil {
+		return "", fmt.Errorf("%s is not an IPv6 prefix", prefix)
+	}
+	if ones, _ := network.Mask.Size(); ones > 104 {
+		return "", fmt.Errorf("prefix %s is too small to contain a node id", prefix)
+	}
+	ip := make(net.IP, net.IPv6len)
+	copy(ip, network.IP)
+	ip[13] |= byte(nodeid >> 16)
+	ip[14] |= byte(nodeid >> 8)
+	ip[15] |= byte(nodeid)
+	return ip.String(), nil
+}
+
 func FmtPath2Str(path []int64) string {
 	var _path string
 	for _, p := range path {
@@ -95,15 +113,3 @@ func FindFirstZeroChar(s []byte) int {
 func TruncateZeros(s []byte) string {
 	return string(s[:FindFirstZeroChar(s)])
 }
-
-func BackupFile(filename string, backupdir string) {
-	if _, err := os.Stat(backupdir); err != nil {
-		os.MkdirAll(backupdir, 0755)
-	}
-	ext := filepath.Ext(filename)
-	filenamenoext := strings.TrimSuffix(filename, ext)
-	backupfile := filenamenoext + "_" + time.Now().Format("20060102150405") + ext + ".bak"
-	if _, err := os.Stat(filename); err == nil {
-		os.Rename(filename, filepath.Join(backupdir, backupfile))
-	}
-}
@@ -7,6 +7,9 @@ export const EspHomeServerList = () => {
         <DataTable bulkActionButtons={false}>
             <DataTable.Col source="id" render={record => formatNodeId(record.id)} />
             <DataTable.Col source="address" />
+            <DataTable.Col source="remote_port" />
+            <DataTable.Col source="listening" />
+            <DataTable.Col source="bind_error" />
             <DataTable.Col source="clients" />
         </DataTable>
     </List>;